@@ -0,0 +1,487 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Converts the string form of an ABI argument into the Go value expected by abi.Arguments.Pack. Arrays and tuples
+// are given as JSON arrays, whose elements may themselves be strings or JSON values.
+func ParseABIArgument(t abi.Type, raw string) (interface{}, error) {
+	raw = strings.TrimSpace(raw)
+
+	switch t.T {
+	case abi.AddressTy:
+		if !common.IsHexAddress(raw) {
+			return nil, fmt.Errorf("invalid address: %s", raw)
+		}
+		return common.HexToAddress(raw), nil
+
+	case abi.BoolTy:
+		switch strings.ToLower(raw) {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("invalid bool: %s", raw)
+
+	case abi.StringTy:
+		return raw, nil
+
+	case abi.IntTy, abi.UintTy:
+		n, ok := ParseInteger(raw)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s: %s", t.String(), raw)
+		}
+		if t.T == abi.UintTy && (n.Sign() < 0 || n.BitLen() > t.Size) {
+			return nil, fmt.Errorf("%s out of range for %s", raw, t.String())
+		}
+		if t.T == abi.IntTy {
+			limit := new(big.Int).Lsh(big.NewInt(1), uint(t.Size-1))
+			if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+				return nil, fmt.Errorf("%s out of range for %s", raw, t.String())
+			}
+		}
+		if t.GetType() == reflect.TypeOf(n) {
+			return n, nil
+		}
+		if t.T == abi.UintTy {
+			return reflect.ValueOf(n.Uint64()).Convert(t.GetType()).Interface(), nil
+		}
+		return reflect.ValueOf(n.Int64()).Convert(t.GetType()).Interface(), nil
+
+	case abi.BytesTy:
+		b, err := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bytes: %w", err)
+		}
+		return b, nil
+
+	case abi.FixedBytesTy:
+		b, err := hex.DecodeString(strings.TrimPrefix(raw, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", t.String(), err)
+		}
+		if len(b) != t.Size {
+			return nil, fmt.Errorf("%s must be %d bytes long, got %d", t.String(), t.Size, len(b))
+		}
+		value := reflect.New(t.GetType()).Elem()
+		reflect.Copy(value, reflect.ValueOf(b))
+		return value.Interface(), nil
+
+	case abi.SliceTy, abi.ArrayTy:
+		elements, err := splitJSONArray(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", t.String(), err)
+		}
+		var value reflect.Value
+		if t.T == abi.SliceTy {
+			value = reflect.MakeSlice(t.GetType(), len(elements), len(elements))
+		} else {
+			if len(elements) != t.Size {
+				return nil, fmt.Errorf("%s must have %d elements, got %d", t.String(), t.Size, len(elements))
+			}
+			value = reflect.New(t.GetType()).Elem()
+		}
+		for i, element := range elements {
+			parsed, err := ParseABIArgument(*t.Elem, element)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			value.Index(i).Set(reflect.ValueOf(parsed))
+		}
+		return value.Interface(), nil
+
+	case abi.TupleTy:
+		elements, err := splitJSONArray(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", t.String(), err)
+		}
+		if len(elements) != len(t.TupleElems) {
+			return nil, fmt.Errorf("%s must have %d components, got %d", t.String(), len(t.TupleElems), len(elements))
+		}
+		value := reflect.New(t.GetType()).Elem()
+		for i, element := range elements {
+			parsed, err := ParseABIArgument(*t.TupleElems[i], element)
+			if err != nil {
+				return nil, fmt.Errorf("component %d: %w", i, err)
+			}
+			value.Field(i).Set(reflect.ValueOf(parsed))
+		}
+		return value.Interface(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported argument type: %s", t.String())
+}
+
+// Parses a decimal integer, or a hexadecimal one with a 0x prefix, optionally preceded by a minus sign. Unlike
+// big.Int.SetString with base 0, a leading zero does not make the number octal and 0b and 0o prefixes are rejected.
+func ParseInteger(raw string) (*big.Int, bool) {
+	digits, negative := strings.CutPrefix(raw, "-")
+	base := 10
+	if hexDigits, ok := strings.CutPrefix(digits, "0x"); ok {
+		digits, base = hexDigits, 16
+	} else if hexDigits, ok := strings.CutPrefix(digits, "0X"); ok {
+		digits, base = hexDigits, 16
+	}
+	// SetString would accept a second sign.
+	if digits == "" || digits[0] == '+' || digits[0] == '-' {
+		return nil, false
+	}
+
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, false
+	}
+	if negative {
+		n.Neg(n)
+	}
+	return n, true
+}
+
+// Splits a JSON array into the string form of each of its elements. String elements are unquoted, other elements
+// are returned as raw JSON.
+func splitJSONArray(raw string) ([]string, error) {
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &elements); err != nil {
+		return nil, fmt.Errorf("expected a JSON array: %w", err)
+	}
+
+	result := make([]string, len(elements))
+	for i, element := range elements {
+		var s string
+		if err := json.Unmarshal(element, &s); err == nil {
+			result[i] = s
+		} else {
+			result[i] = string(element)
+		}
+	}
+	return result, nil
+}
+
+// Loads a contract ABI from a file containing either a bare ABI array or a compiler artifact (Hardhat, Foundry)
+// with an "abi" field.
+func LoadABI(path string) (*abi.ABI, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := json.RawMessage(content)
+	var artifact struct {
+		ABI json.RawMessage `json:"abi"`
+	}
+	if err := json.Unmarshal(content, &artifact); err == nil && len(artifact.ABI) > 0 {
+		raw = artifact.ABI
+	}
+
+	parsed, err := abi.JSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI in %s: %w", path, err)
+	}
+	return &parsed, nil
+}
+
+// Parses a human-readable function signature such as "transfer(address,uint256)" or
+// "function transfer(address to, uint256 amount)". Tuples are written as parenthesised lists of types.
+func ParseMethodSignature(signature string) (abi.Method, error) {
+	signature = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(signature), "function "))
+
+	open := strings.Index(signature, "(")
+	if open <= 0 {
+		return abi.Method{}, fmt.Errorf("invalid method signature: %s", signature)
+	}
+	name := strings.TrimSpace(signature[:open])
+
+	closing, err := matchingParen(signature, open)
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("invalid method signature %s: %w", signature, err)
+	}
+	payable := strings.Contains(signature[closing+1:], "payable")
+
+	params, err := parseSignatureParams(signature[open+1 : closing])
+	if err != nil {
+		return abi.Method{}, fmt.Errorf("invalid method signature %s: %w", signature, err)
+	}
+
+	var arguments abi.Arguments
+	for i, param := range params {
+		argumentType, err := abi.NewType(param.Type, "", param.Components)
+		if err == nil {
+			err = checkABIType(argumentType)
+		}
+		if err != nil {
+			return abi.Method{}, fmt.Errorf("invalid type for parameter %d of %s: %w", i, name, err)
+		}
+		arguments = append(arguments, abi.Argument{Name: param.Name, Type: argumentType})
+	}
+
+	stateMutability := "nonpayable"
+	if payable {
+		stateMutability = "payable"
+	}
+	return abi.NewMethod(name, name, abi.Function, stateMutability, false, payable, arguments, nil), nil
+}
+
+// Rejects types that abi.NewType accepts but Solidity does not, such as uint7, bytes0 or uint256[x], which would
+// give a wrong selector.
+func checkABIType(t abi.Type) error {
+	switch t.T {
+	case abi.IntTy, abi.UintTy:
+		if t.Size < 8 || t.Size > 256 || t.Size%8 != 0 {
+			return fmt.Errorf("%s is not a valid integer type", t.String())
+		}
+	case abi.FixedBytesTy:
+		if t.Size < 1 || t.Size > 32 {
+			return fmt.Errorf("%s is not a valid fixed bytes type", t.String())
+		}
+	case abi.ArrayTy, abi.SliceTy:
+		if t.T == abi.ArrayTy && t.Size < 1 {
+			return fmt.Errorf("%s has no elements", t.String())
+		}
+		if err := checkABIType(*t.Elem); err != nil {
+			return err
+		}
+	case abi.TupleTy:
+		for _, elem := range t.TupleElems {
+			if err := checkABIType(*elem); err != nil {
+				return err
+			}
+		}
+	}
+	if canonical := canonicalABIType(t); t.String() != canonical {
+		return fmt.Errorf("%s is not a valid type, expected something like %s", t.String(), canonical)
+	}
+	return nil
+}
+
+func canonicalABIType(t abi.Type) string {
+	switch t.T {
+	case abi.SliceTy:
+		return canonicalABIType(*t.Elem) + "[]"
+	case abi.ArrayTy:
+		return fmt.Sprintf("%s[%d]", canonicalABIType(*t.Elem), t.Size)
+	case abi.TupleTy:
+		elems := make([]string, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			elems[i] = canonicalABIType(*elem)
+		}
+		return "(" + strings.Join(elems, ",") + ")"
+	case abi.IntTy:
+		return fmt.Sprintf("int%d", t.Size)
+	case abi.UintTy:
+		return fmt.Sprintf("uint%d", t.Size)
+	case abi.FixedBytesTy:
+		return fmt.Sprintf("bytes%d", t.Size)
+	case abi.BytesTy:
+		return "bytes"
+	}
+	return t.String()
+}
+
+func matchingParen(s string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parentheses")
+}
+
+// Parses a comma-separated parameter list, where each parameter is a type optionally followed by a name.
+func parseSignatureParams(list string) ([]abi.ArgumentMarshaling, error) {
+	var params []abi.ArgumentMarshaling
+	if strings.TrimSpace(list) == "" {
+		return params, nil
+	}
+
+	depth, start := 0, 0
+	var parts []string
+	for i := 0; i < len(list); i++ {
+		switch list[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, list[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, list[start:])
+
+	for _, part := range parts {
+		part = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(part), "tuple"))
+		if part == "" {
+			return nil, fmt.Errorf("empty parameter")
+		}
+
+		var param abi.ArgumentMarshaling
+		rest := part
+		if strings.HasPrefix(part, "(") {
+			closing, err := matchingParen(part, 0)
+			if err != nil {
+				return nil, err
+			}
+			components, err := parseSignatureParams(part[1:closing])
+			if err != nil {
+				return nil, err
+			}
+			for i := range components {
+				if components[i].Name == "" {
+					components[i].Name = fmt.Sprintf("field%d", i)
+				}
+			}
+			fields := strings.Fields(part[closing+1:])
+			suffix := ""
+			if len(fields) > 0 && strings.HasPrefix(fields[0], "[") {
+				suffix, fields = fields[0], fields[1:]
+			}
+			param = abi.ArgumentMarshaling{Type: "tuple" + suffix, Components: components}
+			rest = strings.Join(fields, " ")
+		} else {
+			fields := strings.Fields(part)
+			param.Type, rest = fields[0], strings.Join(fields[1:], " ")
+		}
+
+		for _, field := range strings.Fields(rest) {
+			if field != "memory" && field != "calldata" && field != "storage" {
+				param.Name = field
+			}
+		}
+		params = append(params, param)
+	}
+
+	return params, nil
+}
+
+// Finds a method in a contract ABI, either by name or, for overloaded methods, by full signature.
+func FindABIMethod(contractABI *abi.ABI, method string) (abi.Method, error) {
+	method = strings.ReplaceAll(method, " ", "")
+	if m, ok := contractABI.Methods[method]; ok {
+		return m, nil
+	}
+
+	var byName []string
+	for _, m := range contractABI.Methods {
+		if m.Sig == method {
+			return m, nil
+		}
+		if m.RawName == method {
+			byName = append(byName, m.Sig)
+		}
+	}
+
+	if len(byName) > 0 {
+		sort.Strings(byName)
+		return abi.Method{}, fmt.Errorf("method %s is overloaded, please use one of: %s", method, strings.Join(byName, ", "))
+	}
+	return abi.Method{}, fmt.Errorf("method %s not found in ABI", method)
+}
+
+// Type-checks the string arguments of a method call and ABI-encodes them, including the selector.
+func EncodeMethodCall(method abi.Method, rawArgs []string) ([]byte, error) {
+	if len(rawArgs) != len(method.Inputs) {
+		return nil, fmt.Errorf("%s takes %d arguments, got %d", method.Sig, len(method.Inputs), len(rawArgs))
+	}
+
+	values := make([]interface{}, len(rawArgs))
+	for i, input := range method.Inputs {
+		value, err := ParseABIArgument(input.Type, rawArgs[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s) of %s: %w", i, argumentLabel(input, i), method.Sig, err)
+		}
+		values[i] = value
+	}
+
+	packed, err := method.Inputs.Pack(values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", method.Sig, err)
+	}
+	return append(append([]byte{}, method.ID...), packed...), nil
+}
+
+func argumentLabel(argument abi.Argument, index int) string {
+	if argument.Name != "" {
+		return argument.Name + " " + argument.Type.String()
+	}
+	return fmt.Sprintf("arg%d %s", index, argument.Type.String())
+}
+
+// Formats a value unpacked by the abi package for display.
+func FormatABIValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return "0x" + common.Bytes2Hex(v)
+	case string:
+		return strconv.Quote(v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return "0x" + common.Bytes2Hex(b)
+		}
+		fallthrough
+	case reflect.Slice:
+		elements := make([]string, rv.Len())
+		for i := range elements {
+			elements[i] = FormatABIValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case reflect.Struct:
+		fields := make([]string, rv.NumField())
+		for i := range fields {
+			fields[i] = FormatABIValue(rv.Field(i).Interface())
+		}
+		return "(" + strings.Join(fields, ", ") + ")"
+	}
+
+	return fmt.Sprintf("%v", value)
+}
+
+// Decodes calldata for the given method and prints its named arguments, indented by indent.
+func PrintDecodedCall(method abi.Method, calldata []byte, indent string) error {
+	if len(calldata) < 4 || !bytes.Equal(calldata[:4], method.ID) {
+		return fmt.Errorf("calldata does not match selector of %s", method.Sig)
+	}
+
+	values, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %w", method.Sig, err)
+	}
+
+	fmt.Printf("%s%s\n", indent, method.Sig)
+	for i, input := range method.Inputs {
+		fmt.Printf("%s  %s: %s\n", indent, argumentLabel(input, i), FormatABIValue(values[i]))
+	}
+	return nil
+}
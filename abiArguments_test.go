@@ -0,0 +1,189 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseInteger(t *testing.T) {
+	valid := map[string]string{
+		"0":      "0",
+		"10":     "10",
+		"010":    "10",
+		"-42":    "-42",
+		"0x10":   "16",
+		"0XfF":   "255",
+		"-0x80":  "-128",
+		"000000": "0",
+	}
+	for raw, want := range valid {
+		n, ok := ParseInteger(raw)
+		if !ok {
+			t.Errorf("ParseInteger(%q) failed", raw)
+		} else if n.String() != want {
+			t.Errorf("ParseInteger(%q) = %s, want %s", raw, n.String(), want)
+		}
+	}
+
+	for _, raw := range []string{"", "-", "0x", "+1", "--1", "-+1", "0b101", "0o17", "1_000", "1e3", "1.5", "0x-1", "ten", " 1"} {
+		if n, ok := ParseInteger(raw); ok {
+			t.Errorf("ParseInteger(%q) = %s, expected failure", raw, n.String())
+		}
+	}
+}
+
+// Encodes each call with EncodeMethodCall and decodes it again with the method's arguments, comparing the
+// formatted values.
+func TestEncodeMethodCallRoundTrip(t *testing.T) {
+	address := "0x000000000000000000000000000000000000dEaD"
+	cases := []struct {
+		signature string
+		args      []string
+		want      []string
+	}{
+		{"f(uint8)", []string{"255"}, []string{"255"}},
+		{"f(uint8)", []string{"010"}, []string{"10"}},
+		{"f(uint16)", []string{"0x0100"}, []string{"256"}},
+		{"f(uint256)", []string{"115792089237316195423570985008687907853269984665640564039457584007913129639935"}, []string{"115792089237316195423570985008687907853269984665640564039457584007913129639935"}},
+		{"f(int8)", []string{"-128"}, []string{"-128"}},
+		{"f(int8)", []string{"127"}, []string{"127"}},
+		{"f(int64)", []string{"-0x10"}, []string{"-16"}},
+		{"f(int256)", []string{"-57896044618658097711785492504343953926634992332820282019728792003956564819968"}, []string{"-57896044618658097711785492504343953926634992332820282019728792003956564819968"}},
+		{"f(bool,string)", []string{"TRUE", "hello, world"}, []string{"true", `"hello, world"`}},
+		{"f(address)", []string{address}, []string{address}},
+		{"f(bytes4)", []string{"0xa9059cbb"}, []string{"0xa9059cbb"}},
+		{"f(bytes32)", []string{"0x" + common.Bytes2Hex(make([]byte, 32))}, []string{"0x" + common.Bytes2Hex(make([]byte, 32))}},
+		{"f(bytes)", []string{"0x"}, []string{"0x"}},
+		{"f(bytes)", []string{"0xdeadbeef"}, []string{"0xdeadbeef"}},
+		{"f(uint256[])", []string{`["1", 2, "0x3"]`}, []string{"[1, 2, 3]"}},
+		{"f(uint256[])", []string{`[]`}, []string{"[]"}},
+		{"f(address[2])", []string{`["` + address + `", "` + address + `"]`}, []string{"[" + address + ", " + address + "]"}},
+		{"f(uint16[2][])", []string{`[["1","2"],[3,4]]`}, []string{"[[1, 2], [3, 4]]"}},
+		{"f(string[])", []string{`["a", "b,c"]`}, []string{`["a", "b,c"]`}},
+		{"f((address,uint256))", []string{`["` + address + `", "7"]`}, []string{"(" + address + ", 7)"}},
+		{"f((address to,uint256[] amounts)[] calls, bytes data)", []string{`[["` + address + `", [1, 2]], ["` + address + `", []]]`, "0x01"}, []string{"[(" + address + ", [1, 2]), (" + address + ", [])]", "0x01"}},
+		{"f((uint8,(bool,bytes2)))", []string{`[1, [true, "0xabcd"]]`}, []string{"(1, (true, 0xabcd))"}},
+	}
+	for _, c := range cases {
+		method, err := ParseMethodSignature(c.signature)
+		if err != nil {
+			t.Errorf("%s: %v", c.signature, err)
+			continue
+		}
+		encoded, err := EncodeMethodCall(method, c.args)
+		if err != nil {
+			t.Errorf("%s %v: %v", c.signature, c.args, err)
+			continue
+		}
+		values, err := method.Inputs.Unpack(encoded[4:])
+		if err != nil {
+			t.Errorf("%s %v: failed to unpack: %v", c.signature, c.args, err)
+			continue
+		}
+		for i, value := range values {
+			if got := FormatABIValue(value); got != c.want[i] {
+				t.Errorf("%s %v: argument %d decoded as %s, want %s", c.signature, c.args, i, got, c.want[i])
+			}
+		}
+	}
+}
+
+func TestEncodeMethodCallRejectsInvalidArguments(t *testing.T) {
+	cases := []struct {
+		signature string
+		args      []string
+	}{
+		{"f(uint8)", []string{"256"}},
+		{"f(uint8)", []string{"-1"}},
+		{"f(uint256)", []string{"115792089237316195423570985008687907853269984665640564039457584007913129639936"}},
+		{"f(uint256)", []string{"-0x1"}},
+		{"f(uint256)", []string{"0b1"}},
+		{"f(uint256)", []string{"1e18"}},
+		{"f(uint256)", []string{""}},
+		{"f(int8)", []string{"128"}},
+		{"f(int8)", []string{"-129"}},
+		{"f(int256)", []string{"57896044618658097711785492504343953926634992332820282019728792003956564819968"}},
+		{"f(bool)", []string{"1"}},
+		{"f(address)", []string{"0xdead"}},
+		{"f(bytes4)", []string{"0xa9059c"}},
+		{"f(bytes4)", []string{"0xa9059cbb00"}},
+		{"f(bytes)", []string{"0xabc"}},
+		{"f(uint256[2])", []string{`[1]`}},
+		{"f(uint256[2])", []string{`[1, 2, 3]`}},
+		{"f(uint256[])", []string{`1, 2`}},
+		{"f(uint8[2][])", []string{`[[1, 2], [3]]`}},
+		{"f(uint8[])", []string{`[1, 256]`}},
+		{"f((address,uint256))", []string{`["0x000000000000000000000000000000000000dEaD"]`}},
+		{"f((address,uint256))", []string{`["0x000000000000000000000000000000000000dEaD", "-1"]`}},
+		{"f(uint256,uint256)", []string{"1"}},
+	}
+	for _, c := range cases {
+		method, err := ParseMethodSignature(c.signature)
+		if err != nil {
+			t.Errorf("%s: %v", c.signature, err)
+			continue
+		}
+		if encoded, err := EncodeMethodCall(method, c.args); err == nil {
+			t.Errorf("%s %v: encoded as 0x%x, expected an error", c.signature, c.args, encoded)
+		}
+	}
+}
+
+func TestParseMethodSignature(t *testing.T) {
+	valid := []struct {
+		signature string
+		sig       string
+		selector  string
+		payable   bool
+	}{
+		{"transfer(address,uint256)", "transfer(address,uint256)", "a9059cbb", false},
+		{"function transfer(address to, uint256 amount) external", "transfer(address,uint256)", "a9059cbb", false},
+		{"  approve( address spender , uint256 value )  ", "approve(address,uint256)", "095ea7b3", false},
+		{"multiSend(bytes memory transactions) payable", "multiSend(bytes)", "8d80ff0a", true},
+		{"nonce()", "nonce()", "affed0e0", false},
+		{"f(tuple(address,uint256)[] calls)", "f((address,uint256)[])", "", false},
+		{"f((uint8,(bool,bytes2))[2][] nested, string calldata s)", "f((uint8,(bool,bytes2))[2][],string)", "", false},
+	}
+	for _, c := range valid {
+		method, err := ParseMethodSignature(c.signature)
+		if err != nil {
+			t.Errorf("%q: %v", c.signature, err)
+			continue
+		}
+		if method.Sig != c.sig {
+			t.Errorf("%q: signature %s, want %s", c.signature, method.Sig, c.sig)
+		}
+		if c.selector != "" && common.Bytes2Hex(method.ID) != c.selector {
+			t.Errorf("%q: selector %x, want %s", c.signature, method.ID, c.selector)
+		}
+		if method.Payable != c.payable {
+			t.Errorf("%q: payable %v, want %v", c.signature, method.Payable, c.payable)
+		}
+	}
+
+	for _, signature := range []string{
+		"",
+		"transfer",
+		"(address,uint256)",
+		"transfer(address,uint256",
+		"transfer(address,(uint256)",
+		"transfer(address,)",
+		"transfer(,address)",
+		"transfer(addr)",
+		"transfer(uint7)",
+		"transfer(bytes33)",
+		"transfer(uint256[x])",
+		"transfer(uint256[]x)",
+		"transfer(uint256[0])",
+		"transfer(int0)",
+		"transfer(uint264)",
+		"transfer(bytes0)",
+		"transfer((uint7,address))",
+		"transfer(uint)",
+	} {
+		if method, err := ParseMethodSignature(signature); err == nil {
+			t.Errorf("%q parsed as %s, expected an error", signature, method.Sig)
+		}
+	}
+}
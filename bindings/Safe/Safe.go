@@ -22,7 +22,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
-	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 
 	// Reference imports to suppress errors if they are not otherwise used.
 	"encoding/hex"
@@ -31,6 +30,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/G7DAO/safes/safetx"
+	"github.com/G7DAO/safes/signer"
 	"github.com/G7DAO/seer/bindings/CreateCall"
 	"github.com/G7DAO/seer/bindings/GnosisSafe"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -39,7 +40,6 @@ import (
 	"golang.org/x/term"
 
 	// SafeMetaData contains all meta data concerning the Safe contract.
-	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -3412,7 +3412,7 @@ func CreateSafeDeploymentCommand() *cobra.Command {
 		Short: "Deploy a new Safe contract",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -3487,7 +3487,7 @@ func CreateSafeDeploymentCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -3499,7 +3499,7 @@ func CreateSafeDeploymentCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -3581,8 +3581,8 @@ func CreateSafeDeploymentCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -5047,7 +5047,7 @@ func CreateAddOwnerWithThresholdCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -5109,7 +5109,7 @@ func CreateAddOwnerWithThresholdCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -5121,7 +5121,7 @@ func CreateAddOwnerWithThresholdCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -5215,8 +5215,8 @@ func CreateAddOwnerWithThresholdCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -5262,7 +5262,7 @@ func CreateApproveHashCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -5321,7 +5321,7 @@ func CreateApproveHashCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -5333,7 +5333,7 @@ func CreateApproveHashCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -5425,8 +5425,8 @@ func CreateApproveHashCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -5471,7 +5471,7 @@ func CreateChangeThresholdCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -5526,7 +5526,7 @@ func CreateChangeThresholdCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -5538,7 +5538,7 @@ func CreateChangeThresholdCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -5630,8 +5630,8 @@ func CreateChangeThresholdCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -5678,7 +5678,7 @@ func CreateDisableModuleCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -5741,7 +5741,7 @@ func CreateDisableModuleCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -5753,7 +5753,7 @@ func CreateDisableModuleCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -5847,8 +5847,8 @@ func CreateDisableModuleCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -5894,7 +5894,7 @@ func CreateEnableModuleCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -5950,7 +5950,7 @@ func CreateEnableModuleCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -5962,7 +5962,7 @@ func CreateEnableModuleCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -6054,8 +6054,8 @@ func CreateEnableModuleCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -6118,7 +6118,7 @@ func CreateExecTransactionCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -6232,7 +6232,7 @@ func CreateExecTransactionCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -6244,7 +6244,7 @@ func CreateExecTransactionCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -6354,8 +6354,8 @@ func CreateExecTransactionCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -6414,7 +6414,7 @@ func CreateExecTransactionFromModuleCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -6486,7 +6486,7 @@ func CreateExecTransactionFromModuleCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -6498,7 +6498,7 @@ func CreateExecTransactionFromModuleCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -6596,8 +6596,8 @@ func CreateExecTransactionFromModuleCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -6650,7 +6650,7 @@ func CreateExecTransactionFromModuleReturnDataCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -6722,7 +6722,7 @@ func CreateExecTransactionFromModuleReturnDataCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -6734,7 +6734,7 @@ func CreateExecTransactionFromModuleReturnDataCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -6832,8 +6832,8 @@ func CreateExecTransactionFromModuleReturnDataCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -6881,7 +6881,7 @@ func CreateFallbackCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -6940,7 +6940,7 @@ func CreateFallbackCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -6952,7 +6952,7 @@ func CreateFallbackCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -7044,8 +7044,8 @@ func CreateFallbackCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -7087,7 +7087,7 @@ func CreateReceiveCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -7136,7 +7136,7 @@ func CreateReceiveCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -7148,7 +7148,7 @@ func CreateReceiveCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -7236,8 +7236,8 @@ func CreateReceiveCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -7284,7 +7284,7 @@ func CreateRemoveOwnerCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -7353,7 +7353,7 @@ func CreateRemoveOwnerCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -7365,7 +7365,7 @@ func CreateRemoveOwnerCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -7461,8 +7461,8 @@ func CreateRemoveOwnerCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -7509,7 +7509,7 @@ func CreateSetFallbackHandlerCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -7565,7 +7565,7 @@ func CreateSetFallbackHandlerCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -7577,7 +7577,7 @@ func CreateSetFallbackHandlerCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -7669,8 +7669,8 @@ func CreateSetFallbackHandlerCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -7715,7 +7715,7 @@ func CreateSetGuardCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -7771,7 +7771,7 @@ func CreateSetGuardCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -7783,7 +7783,7 @@ func CreateSetGuardCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -7875,8 +7875,8 @@ func CreateSetGuardCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -7921,7 +7921,7 @@ func CreateSetModuleGuardCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -7977,7 +7977,7 @@ func CreateSetModuleGuardCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -7989,7 +7989,7 @@ func CreateSetModuleGuardCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -8081,8 +8081,8 @@ func CreateSetModuleGuardCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -8141,7 +8141,7 @@ func CreateSetupCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -8259,7 +8259,7 @@ func CreateSetupCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -8271,7 +8271,7 @@ func CreateSetupCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -8377,8 +8377,8 @@ func CreateSetupCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -8432,7 +8432,7 @@ func CreateSimulateAndRevertCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -8498,7 +8498,7 @@ func CreateSimulateAndRevertCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -8510,7 +8510,7 @@ func CreateSimulateAndRevertCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -8604,8 +8604,8 @@ func CreateSimulateAndRevertCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -8655,7 +8655,7 @@ func CreateSwapOwnerCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -8725,7 +8725,7 @@ func CreateSwapOwnerCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -8737,7 +8737,7 @@ func CreateSwapOwnerCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -8833,8 +8833,8 @@ func CreateSwapOwnerCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -9137,7 +9137,7 @@ const (
 	NativeTokenAddress = "0x0000000000000000000000000000000000000000"
 )
 
-func DeployWithSafe(client *ethclient.Client, key *keystore.Key, safeAddress common.Address, factoryAddress common.Address, value *big.Int, safeApi string, deployBytecode []byte, safeOperationType SafeOperationType, salt [32]byte, safeNonce *big.Int) error {
+func DeployWithSafe(client *ethclient.Client, key signer.Signer, safeAddress common.Address, factoryAddress common.Address, value *big.Int, safeApi string, deployBytecode []byte, safeOperationType SafeOperationType, salt [32]byte, safeNonce *big.Int) error {
 	abi, err := CreateCall.CreateCallMetaData.GetAbi()
 	if err != nil {
 		return fmt.Errorf("failed to get ABI: %v", err)
@@ -9161,7 +9161,7 @@ func PredictDeploymentAddressSafe(from common.Address, salt [32]byte, deployByte
 	return deployedAddress, nil
 }
 
-func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress common.Address, to common.Address, data []byte, value *big.Int, safeApi string, safeOperationType SafeOperationType, safeNonce *big.Int) error {
+func CreateSafeProposal(client *ethclient.Client, key signer.Signer, safeAddress common.Address, to common.Address, data []byte, value *big.Int, safeApi string, safeOperationType SafeOperationType, safeNonce *big.Int) error {
 	chainID, err := client.ChainID(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get chain ID: %v", err)
@@ -9198,20 +9198,33 @@ func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress
 		Nonce:          nonce,
 	}
 
-	// Calculate SafeTxHash
-	safeTxHash, err := CalculateSafeTxHash(safeAddress, safeTransactionData, chainID)
+	// Calculate SafeTxHash for the Safe's version
+	safeTx := safetx.SafeTx{
+		To:             safeTransactionData.To,
+		Value:          safeTransactionData.Value,
+		Data:           safeTransactionData.Data,
+		Operation:      uint8(safeTransactionData.Operation),
+		SafeTxGas:      safeTransactionData.SafeTxGas,
+		BaseGas:        safeTransactionData.BaseGas,
+		GasPrice:       safeTransactionData.GasPrice,
+		GasToken:       safeTransactionData.GasToken,
+		RefundReceiver: safeTransactionData.RefundReceiver,
+		Nonce:          safeTransactionData.Nonce,
+	}
+	safeTxHash, safeVersion, err := safetx.HashForSafe(client, safeAddress, safeTx, chainID)
 	if err != nil {
 		return fmt.Errorf("failed to calculate SafeTxHash: %v", err)
 	}
 
-	// Sign the SafeTxHash
-	signature, err := crypto.Sign(safeTxHash.Bytes(), key.PrivateKey)
+	// Sign the SafeTx as EIP-712 typed data
+	typedData, err := safetx.TypedData(safeAddress, safeTx, chainID, safeVersion)
 	if err != nil {
-		return fmt.Errorf("failed to sign SafeTxHash: %v", err)
+		return fmt.Errorf("failed to build SafeTx typed data: %v", err)
+	}
+	signature, err := key.SignTypedData(typedData)
+	if err != nil {
+		return fmt.Errorf("failed to sign SafeTx: %v", err)
 	}
-
-	// Adjust V value for Ethereum's replay protection
-	signature[64] += 27
 
 	// Convert signature to hex
 	senderSignature := "0x" + common.Bytes2Hex(signature)
@@ -9229,7 +9242,7 @@ func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress
 		"refundReceiver": safeTransactionData.RefundReceiver,
 		"nonce":          fmt.Sprintf("%d", safeTransactionData.Nonce),
 		"safeTxHash":     safeTxHash.Hex(),
-		"sender":         key.Address.Hex(),
+		"sender":         key.Address().Hex(),
 		"signature":      senderSignature,
 		"origin":         fmt.Sprintf("{\"url\":\"%s\",\"name\":\"TokenSender Deployment\"}", safeApi),
 	}
@@ -9263,51 +9276,19 @@ func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress
 	return nil
 }
 
-func CalculateSafeTxHash(safeAddress common.Address, txData SafeTransactionData, chainID *big.Int) (common.Hash, error) {
-	domainSeparator := apitypes.TypedDataDomain{
-		ChainId:           (*math.HexOrDecimal256)(chainID),
-		VerifyingContract: safeAddress.Hex(),
-	}
-
-	typedData := apitypes.TypedData{
-		Types: apitypes.Types{
-			"EIP712Domain": []apitypes.Type{
-				{Name: "chainId", Type: "uint256"},
-				{Name: "verifyingContract", Type: "address"},
-			},
-			"SafeTx": []apitypes.Type{
-				{Name: "to", Type: "address"},
-				{Name: "value", Type: "uint256"},
-				{Name: "data", Type: "bytes"},
-				{Name: "operation", Type: "uint8"},
-				{Name: "safeTxGas", Type: "uint256"},
-				{Name: "baseGas", Type: "uint256"},
-				{Name: "gasPrice", Type: "uint256"},
-				{Name: "gasToken", Type: "address"},
-				{Name: "refundReceiver", Type: "address"},
-				{Name: "nonce", Type: "uint256"},
-			},
-		},
-		Domain:      domainSeparator,
-		PrimaryType: "SafeTx",
-		Message: apitypes.TypedDataMessage{
-			"to":             txData.To,
-			"value":          txData.Value,
-			"data":           "0x" + txData.Data,
-			"operation":      fmt.Sprintf("%d", txData.Operation),
-			"safeTxGas":      fmt.Sprintf("%d", txData.SafeTxGas),
-			"baseGas":        fmt.Sprintf("%d", txData.BaseGas),
-			"gasPrice":       txData.GasPrice,
-			"gasToken":       txData.GasToken,
-			"refundReceiver": txData.RefundReceiver,
-			"nonce":          fmt.Sprintf("%d", txData.Nonce),
-		},
-	}
-
-	typedDataHash, _, err := apitypes.TypedDataAndHash(typedData)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to hash typed data: %v", err)
-	}
-
-	return common.BytesToHash(typedDataHash), nil
+// Calculates the SafeTxHash of txData for a Safe of the given version, with the EIP-712 domain and SafeTx layout
+// that version uses. See safetx.Hash.
+func CalculateSafeTxHash(safeAddress common.Address, txData SafeTransactionData, chainID *big.Int, safeVersion string) (common.Hash, error) {
+	return safetx.Hash(safeAddress, safetx.SafeTx{
+		To:             txData.To,
+		Value:          txData.Value,
+		Data:           txData.Data,
+		Operation:      uint8(txData.Operation),
+		SafeTxGas:      txData.SafeTxGas,
+		BaseGas:        txData.BaseGas,
+		GasPrice:       txData.GasPrice,
+		GasToken:       txData.GasToken,
+		RefundReceiver: txData.RefundReceiver,
+		Nonce:          txData.Nonce,
+	}, chainID, safeVersion)
 }
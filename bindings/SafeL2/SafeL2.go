@@ -22,7 +22,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
-	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 
 	// Reference imports to suppress errors if they are not otherwise used.
 	"encoding/hex"
@@ -31,6 +30,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/G7DAO/safes/safetx"
+	"github.com/G7DAO/safes/signer"
 	"github.com/G7DAO/seer/bindings/CreateCall"
 	"github.com/G7DAO/seer/bindings/GnosisSafe"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -39,7 +40,6 @@ import (
 	"golang.org/x/term"
 
 	// SafeL2MetaData contains all meta data concerning the SafeL2 contract.
-	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -3694,7 +3694,7 @@ func CreateSafeL2DeploymentCommand() *cobra.Command {
 		Short: "Deploy a new SafeL2 contract",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -3769,7 +3769,7 @@ func CreateSafeL2DeploymentCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -3781,7 +3781,7 @@ func CreateSafeL2DeploymentCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -3863,8 +3863,8 @@ func CreateSafeL2DeploymentCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -5329,7 +5329,7 @@ func CreateAddOwnerWithThresholdCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -5391,7 +5391,7 @@ func CreateAddOwnerWithThresholdCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -5403,7 +5403,7 @@ func CreateAddOwnerWithThresholdCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -5497,8 +5497,8 @@ func CreateAddOwnerWithThresholdCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -5544,7 +5544,7 @@ func CreateApproveHashCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -5603,7 +5603,7 @@ func CreateApproveHashCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -5615,7 +5615,7 @@ func CreateApproveHashCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -5707,8 +5707,8 @@ func CreateApproveHashCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -5753,7 +5753,7 @@ func CreateChangeThresholdCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -5808,7 +5808,7 @@ func CreateChangeThresholdCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -5820,7 +5820,7 @@ func CreateChangeThresholdCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -5912,8 +5912,8 @@ func CreateChangeThresholdCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -5960,7 +5960,7 @@ func CreateDisableModuleCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -6023,7 +6023,7 @@ func CreateDisableModuleCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -6035,7 +6035,7 @@ func CreateDisableModuleCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -6129,8 +6129,8 @@ func CreateDisableModuleCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -6176,7 +6176,7 @@ func CreateEnableModuleCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -6232,7 +6232,7 @@ func CreateEnableModuleCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -6244,7 +6244,7 @@ func CreateEnableModuleCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -6336,8 +6336,8 @@ func CreateEnableModuleCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -6400,7 +6400,7 @@ func CreateExecTransactionCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -6514,7 +6514,7 @@ func CreateExecTransactionCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -6526,7 +6526,7 @@ func CreateExecTransactionCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -6636,8 +6636,8 @@ func CreateExecTransactionCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -6696,7 +6696,7 @@ func CreateExecTransactionFromModuleCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -6768,7 +6768,7 @@ func CreateExecTransactionFromModuleCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -6780,7 +6780,7 @@ func CreateExecTransactionFromModuleCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -6878,8 +6878,8 @@ func CreateExecTransactionFromModuleCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -6932,7 +6932,7 @@ func CreateExecTransactionFromModuleReturnDataCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -7004,7 +7004,7 @@ func CreateExecTransactionFromModuleReturnDataCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -7016,7 +7016,7 @@ func CreateExecTransactionFromModuleReturnDataCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -7114,8 +7114,8 @@ func CreateExecTransactionFromModuleReturnDataCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -7163,7 +7163,7 @@ func CreateFallbackCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -7222,7 +7222,7 @@ func CreateFallbackCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -7234,7 +7234,7 @@ func CreateFallbackCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -7326,8 +7326,8 @@ func CreateFallbackCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -7369,7 +7369,7 @@ func CreateReceiveCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -7418,7 +7418,7 @@ func CreateReceiveCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -7430,7 +7430,7 @@ func CreateReceiveCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -7518,8 +7518,8 @@ func CreateReceiveCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -7566,7 +7566,7 @@ func CreateRemoveOwnerCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -7635,7 +7635,7 @@ func CreateRemoveOwnerCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -7647,7 +7647,7 @@ func CreateRemoveOwnerCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -7743,8 +7743,8 @@ func CreateRemoveOwnerCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -7791,7 +7791,7 @@ func CreateSetFallbackHandlerCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -7847,7 +7847,7 @@ func CreateSetFallbackHandlerCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -7859,7 +7859,7 @@ func CreateSetFallbackHandlerCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -7951,8 +7951,8 @@ func CreateSetFallbackHandlerCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -7997,7 +7997,7 @@ func CreateSetGuardCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -8053,7 +8053,7 @@ func CreateSetGuardCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -8065,7 +8065,7 @@ func CreateSetGuardCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -8157,8 +8157,8 @@ func CreateSetGuardCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -8203,7 +8203,7 @@ func CreateSetModuleGuardCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -8259,7 +8259,7 @@ func CreateSetModuleGuardCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -8271,7 +8271,7 @@ func CreateSetModuleGuardCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -8363,8 +8363,8 @@ func CreateSetModuleGuardCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -8423,7 +8423,7 @@ func CreateSetupCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -8541,7 +8541,7 @@ func CreateSetupCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -8553,7 +8553,7 @@ func CreateSetupCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -8659,8 +8659,8 @@ func CreateSetupCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -8714,7 +8714,7 @@ func CreateSimulateAndRevertCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -8780,7 +8780,7 @@ func CreateSimulateAndRevertCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -8792,7 +8792,7 @@ func CreateSimulateAndRevertCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -8886,8 +8886,8 @@ func CreateSimulateAndRevertCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -8937,7 +8937,7 @@ func CreateSwapOwnerCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -9007,7 +9007,7 @@ func CreateSwapOwnerCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -9019,7 +9019,7 @@ func CreateSwapOwnerCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -9115,8 +9115,8 @@ func CreateSwapOwnerCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -9419,7 +9419,7 @@ const (
 	NativeTokenAddress = "0x0000000000000000000000000000000000000000"
 )
 
-func DeployWithSafe(client *ethclient.Client, key *keystore.Key, safeAddress common.Address, factoryAddress common.Address, value *big.Int, safeApi string, deployBytecode []byte, safeOperationType SafeOperationType, salt [32]byte, safeNonce *big.Int) error {
+func DeployWithSafe(client *ethclient.Client, key signer.Signer, safeAddress common.Address, factoryAddress common.Address, value *big.Int, safeApi string, deployBytecode []byte, safeOperationType SafeOperationType, salt [32]byte, safeNonce *big.Int) error {
 	abi, err := CreateCall.CreateCallMetaData.GetAbi()
 	if err != nil {
 		return fmt.Errorf("failed to get ABI: %v", err)
@@ -9443,7 +9443,7 @@ func PredictDeploymentAddressSafe(from common.Address, salt [32]byte, deployByte
 	return deployedAddress, nil
 }
 
-func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress common.Address, to common.Address, data []byte, value *big.Int, safeApi string, safeOperationType SafeOperationType, safeNonce *big.Int) error {
+func CreateSafeProposal(client *ethclient.Client, key signer.Signer, safeAddress common.Address, to common.Address, data []byte, value *big.Int, safeApi string, safeOperationType SafeOperationType, safeNonce *big.Int) error {
 	chainID, err := client.ChainID(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get chain ID: %v", err)
@@ -9480,20 +9480,33 @@ func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress
 		Nonce:          nonce,
 	}
 
-	// Calculate SafeTxHash
-	safeTxHash, err := CalculateSafeTxHash(safeAddress, safeTransactionData, chainID)
+	// Calculate SafeTxHash for the Safe's version
+	safeTx := safetx.SafeTx{
+		To:             safeTransactionData.To,
+		Value:          safeTransactionData.Value,
+		Data:           safeTransactionData.Data,
+		Operation:      uint8(safeTransactionData.Operation),
+		SafeTxGas:      safeTransactionData.SafeTxGas,
+		BaseGas:        safeTransactionData.BaseGas,
+		GasPrice:       safeTransactionData.GasPrice,
+		GasToken:       safeTransactionData.GasToken,
+		RefundReceiver: safeTransactionData.RefundReceiver,
+		Nonce:          safeTransactionData.Nonce,
+	}
+	safeTxHash, safeVersion, err := safetx.HashForSafe(client, safeAddress, safeTx, chainID)
 	if err != nil {
 		return fmt.Errorf("failed to calculate SafeTxHash: %v", err)
 	}
 
-	// Sign the SafeTxHash
-	signature, err := crypto.Sign(safeTxHash.Bytes(), key.PrivateKey)
+	// Sign the SafeTx as EIP-712 typed data
+	typedData, err := safetx.TypedData(safeAddress, safeTx, chainID, safeVersion)
 	if err != nil {
-		return fmt.Errorf("failed to sign SafeTxHash: %v", err)
+		return fmt.Errorf("failed to build SafeTx typed data: %v", err)
+	}
+	signature, err := key.SignTypedData(typedData)
+	if err != nil {
+		return fmt.Errorf("failed to sign SafeTx: %v", err)
 	}
-
-	// Adjust V value for Ethereum's replay protection
-	signature[64] += 27
 
 	// Convert signature to hex
 	senderSignature := "0x" + common.Bytes2Hex(signature)
@@ -9511,7 +9524,7 @@ func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress
 		"refundReceiver": safeTransactionData.RefundReceiver,
 		"nonce":          fmt.Sprintf("%d", safeTransactionData.Nonce),
 		"safeTxHash":     safeTxHash.Hex(),
-		"sender":         key.Address.Hex(),
+		"sender":         key.Address().Hex(),
 		"signature":      senderSignature,
 		"origin":         fmt.Sprintf("{\"url\":\"%s\",\"name\":\"TokenSender Deployment\"}", safeApi),
 	}
@@ -9545,51 +9558,19 @@ func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress
 	return nil
 }
 
-func CalculateSafeTxHash(safeAddress common.Address, txData SafeTransactionData, chainID *big.Int) (common.Hash, error) {
-	domainSeparator := apitypes.TypedDataDomain{
-		ChainId:           (*math.HexOrDecimal256)(chainID),
-		VerifyingContract: safeAddress.Hex(),
-	}
-
-	typedData := apitypes.TypedData{
-		Types: apitypes.Types{
-			"EIP712Domain": []apitypes.Type{
-				{Name: "chainId", Type: "uint256"},
-				{Name: "verifyingContract", Type: "address"},
-			},
-			"SafeTx": []apitypes.Type{
-				{Name: "to", Type: "address"},
-				{Name: "value", Type: "uint256"},
-				{Name: "data", Type: "bytes"},
-				{Name: "operation", Type: "uint8"},
-				{Name: "safeTxGas", Type: "uint256"},
-				{Name: "baseGas", Type: "uint256"},
-				{Name: "gasPrice", Type: "uint256"},
-				{Name: "gasToken", Type: "address"},
-				{Name: "refundReceiver", Type: "address"},
-				{Name: "nonce", Type: "uint256"},
-			},
-		},
-		Domain:      domainSeparator,
-		PrimaryType: "SafeTx",
-		Message: apitypes.TypedDataMessage{
-			"to":             txData.To,
-			"value":          txData.Value,
-			"data":           "0x" + txData.Data,
-			"operation":      fmt.Sprintf("%d", txData.Operation),
-			"safeTxGas":      fmt.Sprintf("%d", txData.SafeTxGas),
-			"baseGas":        fmt.Sprintf("%d", txData.BaseGas),
-			"gasPrice":       txData.GasPrice,
-			"gasToken":       txData.GasToken,
-			"refundReceiver": txData.RefundReceiver,
-			"nonce":          fmt.Sprintf("%d", txData.Nonce),
-		},
-	}
-
-	typedDataHash, _, err := apitypes.TypedDataAndHash(typedData)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to hash typed data: %v", err)
-	}
-
-	return common.BytesToHash(typedDataHash), nil
+// Calculates the SafeTxHash of txData for a Safe of the given version, with the EIP-712 domain and SafeTx layout
+// that version uses. See safetx.Hash.
+func CalculateSafeTxHash(safeAddress common.Address, txData SafeTransactionData, chainID *big.Int, safeVersion string) (common.Hash, error) {
+	return safetx.Hash(safeAddress, safetx.SafeTx{
+		To:             txData.To,
+		Value:          txData.Value,
+		Data:           txData.Data,
+		Operation:      uint8(txData.Operation),
+		SafeTxGas:      txData.SafeTxGas,
+		BaseGas:        txData.BaseGas,
+		GasPrice:       txData.GasPrice,
+		GasToken:       txData.GasToken,
+		RefundReceiver: txData.RefundReceiver,
+		Nonce:          txData.Nonce,
+	}, chainID, safeVersion)
 }
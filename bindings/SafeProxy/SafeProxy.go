@@ -22,7 +22,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
-	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 
 	// Reference imports to suppress errors if they are not otherwise used.
 	"encoding/hex"
@@ -31,6 +30,8 @@ import (
 	"os"
 	"time"
 
+	"github.com/G7DAO/safes/safetx"
+	"github.com/G7DAO/safes/signer"
 	"github.com/G7DAO/seer/bindings/CreateCall"
 	"github.com/G7DAO/seer/bindings/GnosisSafe"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -39,7 +40,6 @@ import (
 	"golang.org/x/term"
 
 	// SafeProxyMetaData contains all meta data concerning the SafeProxy contract.
-	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
@@ -267,7 +267,7 @@ func CreateSafeProxyDeploymentCommand() *cobra.Command {
 		Short: "Deploy a new SafeProxy contract",
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -349,7 +349,7 @@ func CreateSafeProxyDeploymentCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -361,7 +361,7 @@ func CreateSafeProxyDeploymentCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -446,8 +446,8 @@ func CreateSafeProxyDeploymentCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -513,7 +513,7 @@ func CreateFallbackCommand() *cobra.Command {
 			contractAddress = common.HexToAddress(contractAddressRaw)
 
 			if keyfile == "" {
-				return fmt.Errorf("--keystore not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			if rpc == "" {
@@ -572,7 +572,7 @@ func CreateFallbackCommand() *cobra.Command {
 				return clientErr
 			}
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -584,7 +584,7 @@ func CreateFallbackCommand() *cobra.Command {
 				return chainIDErr
 			}
 
-			transactionOpts, transactionOptsErr := bind.NewKeyedTransactorWithChainID(key.PrivateKey, chainID)
+			transactionOpts, transactionOptsErr := signer.NewTransactor(key, chainID)
 			if transactionOptsErr != nil {
 				return transactionOptsErr
 			}
@@ -676,8 +676,8 @@ func CreateFallbackCommand() *cobra.Command {
 	}
 
 	cmd.Flags().StringVar(&rpc, "rpc", "", "URL of the JSONRPC API to use")
-	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Path to the keystore file to use for the transaction")
-	cmd.Flags().StringVar(&password, "password", "", "Password to use to unlock the keystore (if not specified, you will be prompted for the password when the command executes)")
+	cmd.Flags().StringVar(&keyfile, "keyfile", "", "Signer to use for the transaction: "+signer.SpecUsage)
+	cmd.Flags().StringVar(&password, "password", "", signer.PasswordUsage)
 	cmd.Flags().StringVar(&nonce, "nonce", "", "Nonce to use for the transaction")
 	cmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	cmd.Flags().StringVar(&gasPrice, "gas-price", "", "Gas price to use for the transaction")
@@ -881,7 +881,7 @@ const (
 	NativeTokenAddress = "0x0000000000000000000000000000000000000000"
 )
 
-func DeployWithSafe(client *ethclient.Client, key *keystore.Key, safeAddress common.Address, factoryAddress common.Address, value *big.Int, safeApi string, deployBytecode []byte, safeOperationType SafeOperationType, salt [32]byte, safeNonce *big.Int) error {
+func DeployWithSafe(client *ethclient.Client, key signer.Signer, safeAddress common.Address, factoryAddress common.Address, value *big.Int, safeApi string, deployBytecode []byte, safeOperationType SafeOperationType, salt [32]byte, safeNonce *big.Int) error {
 	abi, err := CreateCall.CreateCallMetaData.GetAbi()
 	if err != nil {
 		return fmt.Errorf("failed to get ABI: %v", err)
@@ -905,7 +905,7 @@ func PredictDeploymentAddressSafe(from common.Address, salt [32]byte, deployByte
 	return deployedAddress, nil
 }
 
-func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress common.Address, to common.Address, data []byte, value *big.Int, safeApi string, safeOperationType SafeOperationType, safeNonce *big.Int) error {
+func CreateSafeProposal(client *ethclient.Client, key signer.Signer, safeAddress common.Address, to common.Address, data []byte, value *big.Int, safeApi string, safeOperationType SafeOperationType, safeNonce *big.Int) error {
 	chainID, err := client.ChainID(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to get chain ID: %v", err)
@@ -942,20 +942,33 @@ func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress
 		Nonce:          nonce,
 	}
 
-	// Calculate SafeTxHash
-	safeTxHash, err := CalculateSafeTxHash(safeAddress, safeTransactionData, chainID)
+	// Calculate SafeTxHash for the Safe's version
+	safeTx := safetx.SafeTx{
+		To:             safeTransactionData.To,
+		Value:          safeTransactionData.Value,
+		Data:           safeTransactionData.Data,
+		Operation:      uint8(safeTransactionData.Operation),
+		SafeTxGas:      safeTransactionData.SafeTxGas,
+		BaseGas:        safeTransactionData.BaseGas,
+		GasPrice:       safeTransactionData.GasPrice,
+		GasToken:       safeTransactionData.GasToken,
+		RefundReceiver: safeTransactionData.RefundReceiver,
+		Nonce:          safeTransactionData.Nonce,
+	}
+	safeTxHash, safeVersion, err := safetx.HashForSafe(client, safeAddress, safeTx, chainID)
 	if err != nil {
 		return fmt.Errorf("failed to calculate SafeTxHash: %v", err)
 	}
 
-	// Sign the SafeTxHash
-	signature, err := crypto.Sign(safeTxHash.Bytes(), key.PrivateKey)
+	// Sign the SafeTx as EIP-712 typed data
+	typedData, err := safetx.TypedData(safeAddress, safeTx, chainID, safeVersion)
 	if err != nil {
-		return fmt.Errorf("failed to sign SafeTxHash: %v", err)
+		return fmt.Errorf("failed to build SafeTx typed data: %v", err)
+	}
+	signature, err := key.SignTypedData(typedData)
+	if err != nil {
+		return fmt.Errorf("failed to sign SafeTx: %v", err)
 	}
-
-	// Adjust V value for Ethereum's replay protection
-	signature[64] += 27
 
 	// Convert signature to hex
 	senderSignature := "0x" + common.Bytes2Hex(signature)
@@ -973,7 +986,7 @@ func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress
 		"refundReceiver": safeTransactionData.RefundReceiver,
 		"nonce":          fmt.Sprintf("%d", safeTransactionData.Nonce),
 		"safeTxHash":     safeTxHash.Hex(),
-		"sender":         key.Address.Hex(),
+		"sender":         key.Address().Hex(),
 		"signature":      senderSignature,
 		"origin":         fmt.Sprintf("{\"url\":\"%s\",\"name\":\"TokenSender Deployment\"}", safeApi),
 	}
@@ -1007,51 +1020,19 @@ func CreateSafeProposal(client *ethclient.Client, key *keystore.Key, safeAddress
 	return nil
 }
 
-func CalculateSafeTxHash(safeAddress common.Address, txData SafeTransactionData, chainID *big.Int) (common.Hash, error) {
-	domainSeparator := apitypes.TypedDataDomain{
-		ChainId:           (*math.HexOrDecimal256)(chainID),
-		VerifyingContract: safeAddress.Hex(),
-	}
-
-	typedData := apitypes.TypedData{
-		Types: apitypes.Types{
-			"EIP712Domain": []apitypes.Type{
-				{Name: "chainId", Type: "uint256"},
-				{Name: "verifyingContract", Type: "address"},
-			},
-			"SafeTx": []apitypes.Type{
-				{Name: "to", Type: "address"},
-				{Name: "value", Type: "uint256"},
-				{Name: "data", Type: "bytes"},
-				{Name: "operation", Type: "uint8"},
-				{Name: "safeTxGas", Type: "uint256"},
-				{Name: "baseGas", Type: "uint256"},
-				{Name: "gasPrice", Type: "uint256"},
-				{Name: "gasToken", Type: "address"},
-				{Name: "refundReceiver", Type: "address"},
-				{Name: "nonce", Type: "uint256"},
-			},
-		},
-		Domain:      domainSeparator,
-		PrimaryType: "SafeTx",
-		Message: apitypes.TypedDataMessage{
-			"to":             txData.To,
-			"value":          txData.Value,
-			"data":           "0x" + txData.Data,
-			"operation":      fmt.Sprintf("%d", txData.Operation),
-			"safeTxGas":      fmt.Sprintf("%d", txData.SafeTxGas),
-			"baseGas":        fmt.Sprintf("%d", txData.BaseGas),
-			"gasPrice":       txData.GasPrice,
-			"gasToken":       txData.GasToken,
-			"refundReceiver": txData.RefundReceiver,
-			"nonce":          fmt.Sprintf("%d", txData.Nonce),
-		},
-	}
-
-	typedDataHash, _, err := apitypes.TypedDataAndHash(typedData)
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to hash typed data: %v", err)
-	}
-
-	return common.BytesToHash(typedDataHash), nil
+// Calculates the SafeTxHash of txData for a Safe of the given version, with the EIP-712 domain and SafeTx layout
+// that version uses. See safetx.Hash.
+func CalculateSafeTxHash(safeAddress common.Address, txData SafeTransactionData, chainID *big.Int, safeVersion string) (common.Hash, error) {
+	return safetx.Hash(safeAddress, safetx.SafeTx{
+		To:             txData.To,
+		Value:          txData.Value,
+		Data:           txData.Data,
+		Operation:      uint8(txData.Operation),
+		SafeTxGas:      txData.SafeTxGas,
+		BaseGas:        txData.BaseGas,
+		GasPrice:       txData.GasPrice,
+		GasToken:       txData.GasToken,
+		RefundReceiver: txData.RefundReceiver,
+		Nonce:          txData.Nonce,
+	}, chainID, safeVersion)
 }
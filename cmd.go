@@ -42,7 +42,13 @@ func CreateRootCommand() *cobra.Command {
 
 	proposalCmd := CreateSafeProposalCmd()
 
-	rootCmd.AddCommand(completionCmd, versionCmd, singletonCmd, singletonL2Cmd, proxyCmd, factoryCmd, delegateCmd, proposalCmd)
+	signaturesCmd := CreateSignaturesCmd()
+
+	safeCmd := CreateSafeCmd()
+
+	signingServerCmd := CreateSigningServerCmd()
+
+	rootCmd.AddCommand(completionCmd, versionCmd, singletonCmd, singletonL2Cmd, proxyCmd, factoryCmd, delegateCmd, proposalCmd, signaturesCmd, safeCmd, signingServerCmd)
 
 	// By default, cobra Command objects write to stderr. We have to forcibly set them to output to
 	// stdout.
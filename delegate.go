@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/G7DAO/safes/signer"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
@@ -50,13 +51,13 @@ func createAddDelegateCmd() *cobra.Command {
 			}
 
 			if keyfile == "" {
-				return fmt.Errorf("--keyfile not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -91,8 +92,8 @@ func createAddDelegateCmd() *cobra.Command {
 	addDelegateCmd.Flags().StringVar(&safe, "safe", "", "Safe address")
 	addDelegateCmd.Flags().StringVar(&delegate, "delegate", "", "Delegate address")
 	addDelegateCmd.Flags().StringVarP(&label, "label", "l", "", "Label for the delegate")
-	addDelegateCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Path to the keystore file")
-	addDelegateCmd.Flags().StringVarP(&password, "password", "p", "", "Password for the keystore file")
+	addDelegateCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer: "+signer.SpecUsage)
+	addDelegateCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
 	addDelegateCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL to retrieve chain ID")
 	addDelegateCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe API URL")
 	addDelegateCmd.MarkFlagRequired("keyfile")
@@ -188,7 +189,7 @@ func createRemoveDelegateCmd() *cobra.Command {
 			}
 
 			if keyfile == "" {
-				return fmt.Errorf("--keyfile not specified (this should be a path to an Ethereum account keystore file)")
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
 			}
 
 			return nil
@@ -197,7 +198,7 @@ func createRemoveDelegateCmd() *cobra.Command {
 			checksumSafe := common.HexToAddress(safe).Hex()
 			checksumDelegate := common.HexToAddress(delegate).Hex()
 
-			key, keyErr := KeyFromFile(keyfile, password)
+			key, keyErr := signer.Open(keyfile, password)
 			if keyErr != nil {
 				return keyErr
 			}
@@ -228,8 +229,8 @@ func createRemoveDelegateCmd() *cobra.Command {
 
 	removeDelegateCmd.Flags().StringVar(&safe, "safe", "", "Safe address")
 	removeDelegateCmd.Flags().StringVar(&delegate, "delegate", "", "Delegate address to remove")
-	removeDelegateCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Path to the keystore file")
-	removeDelegateCmd.Flags().StringVarP(&password, "password", "p", "", "Password for the keystore file")
+	removeDelegateCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer: "+signer.SpecUsage)
+	removeDelegateCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
 	removeDelegateCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL to retrieve chain ID")
 	removeDelegateCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe API URL")
 	removeDelegateCmd.MarkFlagRequired("safe")
@@ -9,16 +9,12 @@ import (
 	"net/url"
 	"time"
 
-	"os"
-
 	"io"
 
-	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/G7DAO/safes/signer"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
-	"golang.org/x/crypto/ssh/terminal"
 )
 
 type DelegateResponse struct {
@@ -28,14 +24,14 @@ type DelegateResponse struct {
 	Label     string `json:"label"`
 }
 
-func AddDelegate(safeAddress, delegateAddress, label string, chainID *big.Int, key *keystore.Key, apiURL string) error {
+func AddDelegate(safeAddress, delegateAddress, label string, chainID *big.Int, key signer.Signer, apiURL string) error {
 	// Generate TOTP (Time-based One-Time Password)
 	totp := big.NewInt(time.Now().Unix() / 3600)
 
 	// Convert addresses to checksum format
 	checksumSafe := common.HexToAddress(safeAddress).Hex()
 	checksumDelegate := common.HexToAddress(delegateAddress).Hex()
-	checksumSigner := key.Address.Hex()
+	checksumSigner := key.Address().Hex()
 
 	// Create EIP-712 message
 	typedData := apitypes.TypedData{
@@ -62,20 +58,12 @@ func AddDelegate(safeAddress, delegateAddress, label string, chainID *big.Int, k
 		},
 	}
 
-	typedDataHash, _, err := apitypes.TypedDataAndHash(typedData)
-	if err != nil {
-		return fmt.Errorf("failed to hash typed data: %v", err)
-	}
-
-	// Sign the typedDataHash
-	signature, err := crypto.Sign(common.BytesToHash(typedDataHash).Bytes(), key.PrivateKey)
+	// Sign the typed data
+	signature, err := key.SignTypedData(typedData)
 	if err != nil {
-		return fmt.Errorf("failed to sign typed data hash: %v", err)
+		return fmt.Errorf("failed to sign typed data: %v", err)
 	}
 
-	// Adjust V value for Ethereum's replay protection
-	signature[64] += 27
-
 	// Convert signature to hex
 	senderSignature := "0x" + common.Bytes2Hex(signature)
 
@@ -172,14 +160,14 @@ func GetDelegates(safe, delegate, delegator, label string, limit, offset int, ch
 	return response.Results, nil
 }
 
-func RemoveDelegate(safeAddress, delegateAddress string, chainID *big.Int, key *keystore.Key, apiURL string) error {
+func RemoveDelegate(safeAddress, delegateAddress string, chainID *big.Int, key signer.Signer, apiURL string) error {
 	// Generate TOTP (Time-based One-Time Password)
 	totp := big.NewInt(time.Now().Unix() / 3600)
 
 	// Convert addresses to checksum format
 	checksumSafe := common.HexToAddress(safeAddress).Hex()
 	checksumDelegate := common.HexToAddress(delegateAddress).Hex()
-	checksumSigner := key.Address.Hex()
+	checksumSigner := key.Address().Hex()
 
 	// Create EIP-712 message
 	typedData := apitypes.TypedData{
@@ -206,20 +194,12 @@ func RemoveDelegate(safeAddress, delegateAddress string, chainID *big.Int, key *
 		},
 	}
 
-	typedDataHash, _, err := apitypes.TypedDataAndHash(typedData)
-	if err != nil {
-		return fmt.Errorf("failed to hash typed data: %v", err)
-	}
-
-	// Sign the SafeTxHash
-	signature, err := crypto.Sign(common.BytesToHash(typedDataHash).Bytes(), key.PrivateKey)
+	// Sign the typed data
+	signature, err := key.SignTypedData(typedData)
 	if err != nil {
-		return fmt.Errorf("failed to sign SafeTxHash: %v", err)
+		return fmt.Errorf("failed to sign typed data: %v", err)
 	}
 
-	// Adjust V value for Ethereum's replay protection
-	signature[64] += 27
-
 	// Convert signature to hex
 	senderSignature := "0x" + common.Bytes2Hex(signature)
 
@@ -259,25 +239,3 @@ func RemoveDelegate(safeAddress, delegateAddress string, chainID *big.Int, key *
 
 	return nil
 }
-
-func KeyFromFile(keystoreFile string, password string) (*keystore.Key, error) {
-	var emptyKey *keystore.Key
-	keystoreContent, readErr := os.ReadFile(keystoreFile)
-	if readErr != nil {
-		return emptyKey, readErr
-	}
-
-	// If password is "", prompt user for password.
-	if password == "" {
-		fmt.Printf("Please provide a password for keystore (%s): ", keystoreFile)
-		passwordRaw, inputErr := terminal.ReadPassword(int(os.Stdin.Fd()))
-		if inputErr != nil {
-			return emptyKey, fmt.Errorf("error reading password: %s", inputErr.Error())
-		}
-		fmt.Print("\n")
-		password = string(passwordRaw)
-	}
-
-	key, err := keystore.DecryptKey(keystoreContent, password)
-	return key, err
-}
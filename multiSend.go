@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const multiSendABI = `[{"inputs":[{"internalType":"bytes","name":"transactions","type":"bytes"}],"name":"multiSend","outputs":[],"stateMutability":"payable","type":"function"}]`
+
+// MultiSendDeployment holds the addresses of the MultiSend and MultiSendCallOnly libraries for a Safe version.
+type MultiSendDeployment struct {
+	MultiSend         common.Address
+	MultiSendCallOnly common.Address
+}
+
+// Canonical MultiSend deployments, by Safe version. Versions with more than one entry were deployed at different
+// addresses on different chains (canonical and EIP-155 deployments), so the first one with code is used.
+var MultiSendDeployments = map[string][]MultiSendDeployment{
+	"1.3.0": {
+		{
+			MultiSend:         common.HexToAddress("0xA238CBeb142c10Ef7Ad8442C6D1f9E89e07e7761"),
+			MultiSendCallOnly: common.HexToAddress("0x40A2aCCbd92BCA938b02010E17A5b8929b49130D"),
+		},
+		{
+			MultiSend:         common.HexToAddress("0x998739BFdAAdde7C933B942a68053933098f9EDa"),
+			MultiSendCallOnly: common.HexToAddress("0xA1dabEF33b3B82c7814B6D82A79e50F4AC44102B"),
+		},
+	},
+	"1.4.1": {
+		{
+			MultiSend:         common.HexToAddress("0x38869bf66a61cF6bDB996A6aE40D5853Fd43B526"),
+			MultiSendCallOnly: common.HexToAddress("0x9641d764fc13c8B624c04430C7356C1C7C8102e2"),
+		},
+	},
+	"1.5.0": {
+		{
+			MultiSend:         common.HexToAddress("0x218543288004CD07832472D464648173c77D7eB7"),
+			MultiSendCallOnly: common.HexToAddress("0xA83c336B20401Af773B6219BA5027174338D1836"),
+		},
+	},
+}
+
+// Chain-specific MultiSend deployments which take precedence over MultiSendDeployments, by chain ID and Safe version.
+var ChainMultiSendDeployments = map[string]map[string]MultiSendDeployment{
+	// zkSync Era
+	"324": {
+		"1.3.0": {
+			MultiSend:         common.HexToAddress("0x0dFcccB95225ffB03c6FBB2559B530C2B7C8A912"),
+			MultiSendCallOnly: common.HexToAddress("0xf220D3b4DFb23C4ade8C88E526C1353AbAcbC38F"),
+		},
+	},
+}
+
+// MultiSendCall is a single call inside a MultiSend batch.
+type MultiSendCall struct {
+	To        common.Address
+	Value     *big.Int
+	Data      []byte
+	Operation uint8
+}
+
+type multiSendCallJSON struct {
+	To        string `json:"to"`
+	Value     string `json:"value"`
+	Data      string `json:"data"`
+	Operation uint8  `json:"operation"`
+}
+
+// Returns the MultiSend (or MultiSendCallOnly) library to use for a Safe of the given version on the given chain,
+// checking that it has code deployed.
+func ResolveMultiSendAddress(client *ethclient.Client, chainID *big.Int, safeVersion string, callOnly bool) (common.Address, error) {
+	var candidates []MultiSendDeployment
+	if deployment, ok := ChainMultiSendDeployments[chainID.String()][safeVersion]; ok {
+		candidates = append(candidates, deployment)
+	}
+	candidates = append(candidates, MultiSendDeployments[safeVersion]...)
+
+	if len(candidates) == 0 {
+		return common.Address{}, fmt.Errorf("no known MultiSend deployment for Safe version %s, please pass --multisend", safeVersion)
+	}
+
+	for _, candidate := range candidates {
+		address := candidate.MultiSend
+		if callOnly {
+			address = candidate.MultiSendCallOnly
+		}
+
+		code, err := client.CodeAt(context.Background(), address, nil)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("failed to fetch code at %s: %w", address.Hex(), err)
+		}
+		if len(code) > 0 {
+			return address, nil
+		}
+	}
+
+	return common.Address{}, fmt.Errorf("no MultiSend deployment for Safe version %s found on chain %s, please pass --multisend", safeVersion, chainID.String())
+}
+
+// Resolves the MultiSend library matching the version of the given Safe.
+func ResolveSafeMultiSendAddress(safeAddress common.Address, chainID *big.Int, client *ethclient.Client, callOnly bool) (common.Address, error) {
+	safeInstance, err := Safe.NewSafe(safeAddress, client)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to create Safe instance: %w", err)
+	}
+
+	version, err := safeInstance.VERSION(&bind.CallOpts{})
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to fetch Safe version: %w", err)
+	}
+
+	return ResolveMultiSendAddress(client, chainID, version, callOnly)
+}
+
+// Encodes calls into the packed transactions format and wraps them in a multiSend(bytes) call.
+func EncodeMultiSend(calls []MultiSendCall) ([]byte, error) {
+	var packed []byte
+	for i, call := range calls {
+		value := call.Value
+		if value == nil {
+			value = big.NewInt(0)
+		}
+		if value.Sign() < 0 || value.BitLen() > 256 {
+			return nil, fmt.Errorf("call %d has value %s, which is not a uint256", i, value.String())
+		}
+		packed = append(packed, call.Operation)
+		packed = append(packed, call.To.Bytes()...)
+		packed = append(packed, common.LeftPadBytes(value.Bytes(), 32)...)
+		packed = append(packed, common.LeftPadBytes(big.NewInt(int64(len(call.Data))).Bytes(), 32)...)
+		packed = append(packed, call.Data...)
+	}
+
+	multiSendAbi, err := abi.JSON(strings.NewReader(multiSendABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse MultiSend ABI: %w", err)
+	}
+
+	return multiSendAbi.Pack("multiSend", packed)
+}
+
+// Whether any call in the batch is a DelegateCall, which MultiSendCallOnly does not allow.
+func MultiSendNeedsDelegateCall(calls []MultiSendCall) bool {
+	for _, call := range calls {
+		if call.Operation != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func parseMultiSendCall(raw multiSendCallJSON) (MultiSendCall, error) {
+	if !common.IsHexAddress(raw.To) {
+		return MultiSendCall{}, fmt.Errorf("invalid to address: %s", raw.To)
+	}
+
+	value := new(big.Int)
+	if raw.Value != "" {
+		parsed, ok := ParseInteger(raw.Value)
+		if !ok || parsed.Sign() < 0 {
+			return MultiSendCall{}, fmt.Errorf("invalid value: %s", raw.Value)
+		}
+		value = parsed
+	}
+
+	data, err := hex.DecodeString(strings.TrimPrefix(raw.Data, "0x"))
+	if err != nil {
+		return MultiSendCall{}, fmt.Errorf("invalid data: %w", err)
+	}
+
+	if raw.Operation > 1 {
+		return MultiSendCall{}, fmt.Errorf("operation must be 0 (Call) or 1 (DelegateCall)")
+	}
+
+	return MultiSendCall{
+		To:        common.HexToAddress(raw.To),
+		Value:     value,
+		Data:      data,
+		Operation: raw.Operation,
+	}, nil
+}
+
+// Reads a batch of calls from a JSON file containing an array of {"to", "value", "data", "operation"} objects.
+func ReadMultiSendCalls(path string) ([]MultiSendCall, error) {
+	var raw []multiSendCallJSON
+	if err := readJSONFile(path, &raw); err != nil {
+		return nil, err
+	}
+
+	calls := make([]MultiSendCall, 0, len(raw))
+	for i, r := range raw {
+		call, err := parseMultiSendCall(r)
+		if err != nil {
+			return nil, fmt.Errorf("call %d in %s: %w", i, path, err)
+		}
+		calls = append(calls, call)
+	}
+
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("%s contains no calls", path)
+	}
+
+	return calls, nil
+}
+
+// Builds a batch of calls by prompting for each one on stdin. An empty "to" finishes the batch.
+func PromptMultiSendCalls() ([]MultiSendCall, error) {
+	var calls []MultiSendCall
+
+	for {
+		fmt.Printf("Call %d\n", len(calls))
+		to, err := promptLine("  To (leave empty to finish): ")
+		if err != nil {
+			return nil, err
+		}
+		if to == "" {
+			break
+		}
+		value, err := promptLine("  Value [0]: ")
+		if err != nil {
+			return nil, err
+		}
+		data, err := promptLine("  Calldata [0x]: ")
+		if err != nil {
+			return nil, err
+		}
+
+		call, err := parseMultiSendCall(multiSendCallJSON{To: to, Value: value, Data: data})
+		if err != nil {
+			fmt.Printf("  Invalid call, please try again: %v\n", err)
+			continue
+		}
+		calls = append(calls, call)
+	}
+
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("no calls entered")
+	}
+
+	return calls, nil
+}
+
+// Prints every call in a batch, with its decoded calldata, so that it can be reviewed before signing.
+func PrintMultiSendCalls(calls []MultiSendCall) {
+	DefaultSignatureRegistry().PrintCalls(calls, "  ")
+}
+
+// Decodes multiSend(bytes) calldata back into the calls of the batch.
+func DecodeMultiSend(calldata []byte) ([]MultiSendCall, error) {
+	multiSendAbi, err := abi.JSON(strings.NewReader(multiSendABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse MultiSend ABI: %w", err)
+	}
+
+	method := multiSendAbi.Methods["multiSend"]
+	if len(calldata) < 4 || !bytes.Equal(calldata[:4], method.ID) {
+		return nil, fmt.Errorf("calldata is not a multiSend call")
+	}
+
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack multiSend calldata: %w", err)
+	}
+	packed := args[0].([]byte)
+
+	var calls []MultiSendCall
+	for offset := 0; offset < len(packed); {
+		if len(packed)-offset < 85 {
+			return nil, fmt.Errorf("truncated multiSend transaction at offset %d", offset)
+		}
+		call := MultiSendCall{
+			Operation: packed[offset],
+			To:        common.BytesToAddress(packed[offset+1 : offset+21]),
+			Value:     new(big.Int).SetBytes(packed[offset+21 : offset+53]),
+		}
+		dataLength := new(big.Int).SetBytes(packed[offset+53 : offset+85])
+		offset += 85
+		if !dataLength.IsInt64() || dataLength.Int64() > int64(len(packed)-offset) {
+			return nil, fmt.Errorf("invalid multiSend data length at offset %d", offset-32)
+		}
+		call.Data = packed[offset : offset+int(dataLength.Int64())]
+		offset += int(dataLength.Int64())
+		calls = append(calls, call)
+	}
+
+	return calls, nil
+}
+
+// Encodes a batch as a MultiSend transaction from the given Safe. Unless multiSendOverride is set, the MultiSend
+// library is chosen to match the Safe's version, preferring MultiSendCallOnly when no call needs a DelegateCall.
+func BuildMultiSendTransaction(safeAddress common.Address, chainID *big.Int, client *ethclient.Client, calls []MultiSendCall, multiSendOverride string) (common.Address, []byte, error) {
+	multiSendAddress := common.HexToAddress(multiSendOverride)
+	if multiSendOverride == "" {
+		var err error
+		multiSendAddress, err = ResolveSafeMultiSendAddress(safeAddress, chainID, client, !MultiSendNeedsDelegateCall(calls))
+		if err != nil {
+			return common.Address{}, nil, err
+		}
+	}
+
+	multiSendData, err := EncodeMultiSend(calls)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("failed to encode batch: %w", err)
+	}
+
+	return multiSendAddress, multiSendData, nil
+}
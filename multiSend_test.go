@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestEncodeMultiSend(t *testing.T) {
+	calls := []MultiSendCall{
+		{To: common.HexToAddress("0x000000000000000000000000000000000000dEaD"), Value: big.NewInt(1000), Data: nil},
+		{To: common.HexToAddress("0x000000000000000000000000000000000000bEEF"), Data: common.FromHex("0xa9059cbb"), Operation: 1},
+	}
+
+	encoded, err := EncodeMultiSend(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// multiSend(bytes) selector, offset, length, then operation (1 byte), to (20), value (32), data length (32), data.
+	want := common.FromHex("0x8d80ff0a" +
+		"0000000000000000000000000000000000000000000000000000000000000020" +
+		"00000000000000000000000000000000000000000000000000000000000000ae" +
+		"00" + "000000000000000000000000000000000000dead" +
+		"00000000000000000000000000000000000000000000000000000000000003e8" +
+		"0000000000000000000000000000000000000000000000000000000000000000" +
+		"01" + "000000000000000000000000000000000000beef" +
+		"0000000000000000000000000000000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"a9059cbb" +
+		"000000000000000000000000000000000000")
+	if !bytes.Equal(encoded, want) {
+		t.Errorf("EncodeMultiSend = %x, want %x", encoded, want)
+	}
+
+	decoded, err := DecodeMultiSend(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(calls) {
+		t.Fatalf("decoded %d calls, want %d", len(decoded), len(calls))
+	}
+	for i, call := range calls {
+		value := call.Value
+		if value == nil {
+			value = big.NewInt(0)
+		}
+		got := decoded[i]
+		if got.To != call.To || got.Value.Cmp(value) != 0 || !bytes.Equal(got.Data, call.Data) || got.Operation != call.Operation {
+			t.Errorf("call %d decoded as %+v, want %+v", i, got, call)
+		}
+	}
+}
+
+func TestEncodeMultiSendRejectsInvalidValues(t *testing.T) {
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	for _, value := range []*big.Int{big.NewInt(-1), new(big.Int).Lsh(big.NewInt(1), 256)} {
+		if _, err := EncodeMultiSend([]MultiSendCall{{To: to, Value: value}}); err == nil {
+			t.Errorf("EncodeMultiSend accepted value %s", value.String())
+		}
+	}
+}
+
+func TestParseMultiSendCall(t *testing.T) {
+	call, err := parseMultiSendCall(multiSendCallJSON{To: "0x000000000000000000000000000000000000dEaD", Value: "0x10", Data: "0xa9059cbb", Operation: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if call.Value.Cmp(big.NewInt(16)) != 0 || !bytes.Equal(call.Data, common.FromHex("0xa9059cbb")) || call.Operation != 1 {
+		t.Errorf("parseMultiSendCall = %+v", call)
+	}
+
+	// A leading zero does not make a value octal.
+	if call, err := parseMultiSendCall(multiSendCallJSON{To: "0x000000000000000000000000000000000000dEaD", Value: "010"}); err != nil || call.Value.Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("value 010 parsed as %v, %v", call.Value, err)
+	}
+
+	invalid := []multiSendCallJSON{
+		{To: "0x000000000000000000000000000000000000dEaD", Value: "-1"},
+		{To: "0x000000000000000000000000000000000000dEaD", Value: "-0x10"},
+		{To: "0x000000000000000000000000000000000000dEaD", Value: "ten"},
+		{To: "0x000000000000000000000000000000000000dEaD", Value: "0b1"},
+		{To: "0x000000000000000000000000000000000000dEaD", Data: "0xzz"},
+		{To: "0x000000000000000000000000000000000000dEaD", Operation: 2},
+		{To: "dead"},
+	}
+	for _, raw := range invalid {
+		if _, err := parseMultiSendCall(raw); err == nil {
+			t.Errorf("parseMultiSendCall accepted %+v", raw)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Shared so that buffered input is not lost between successive prompts.
+var stdinReader = bufio.NewReader(os.Stdin)
+
+func promptLine(prompt string) (string, error) {
+	fmt.Print(prompt)
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("error reading input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// Asks the user a yes/no question on stdin. Anything other than "y" or "yes" is treated as no.
+func ConfirmPrompt(question string) (bool, error) {
+	answer, err := promptLine(question + " [y/N]: ")
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes", nil
+}
@@ -9,6 +9,11 @@ import (
 	"strings"
 
 	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/safetx"
+	"github.com/G7DAO/safes/signer"
+	"github.com/G7DAO/seer/bindings/GnosisSafe"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/spf13/cobra"
@@ -22,6 +27,16 @@ func CreateSafeProposalCmd() *cobra.Command {
 	}
 
 	proposalCmd.AddCommand(createSafeProposalCmd())
+	proposalCmd.AddCommand(createListSafeProposalsCmd())
+	proposalCmd.AddCommand(createSafeProposalQueueCmd())
+	proposalCmd.AddCommand(createRejectSafeProposalCmd())
+	proposalCmd.AddCommand(createVerifySafeProposalCmd())
+	proposalCmd.AddCommand(createConfirmSafeProposalCmd())
+	proposalCmd.AddCommand(createExecuteSafeProposalCmd())
+	proposalCmd.AddCommand(createExportSafeProposalCmd())
+	proposalCmd.AddCommand(createSignSafeProposalCmd())
+	proposalCmd.AddCommand(createCombineSafeProposalCmd())
+	proposalCmd.AddCommand(createTransferSafeProposalCmd())
 	proposalCmd.SetOut(os.Stdout)
 
 	return proposalCmd
@@ -36,6 +51,21 @@ func createSafeProposalCmd() *cobra.Command {
 		value             string
 		keyfile           string
 		password          string
+		batchFile         string
+		txBuilderFile     string
+		interactive       bool
+		abiFile           string
+		methodName        string
+		methodArgs        []string
+		multiSendRaw      string
+		yes               bool
+		gasFlags          safeTxGasFlags
+		gasParams         SafeTxGasParams
+		simulate          bool
+		estimateSafeTxGas bool
+		accessorRaw       string
+		safeNonceRaw      string
+		safeNonce         *big.Int
 	)
 
 	createProposalCmd := &cobra.Command{
@@ -47,11 +77,53 @@ func createSafeProposalCmd() *cobra.Command {
 			} else if !common.IsHexAddress(safe) {
 				return fmt.Errorf("invalid safe address: %s", safe)
 			}
+			var err error
+			gasParams, err = gasFlags.Parse()
+			if err != nil {
+				return err
+			}
+			if estimateSafeTxGas && cmd.Flags().Changed("safe-tx-gas") {
+				return fmt.Errorf("only one of --safe-tx-gas or --estimate-safe-tx-gas may be specified")
+			}
+			if accessorRaw != "" && !common.IsHexAddress(accessorRaw) {
+				return fmt.Errorf("invalid SimulateTxAccessor address: %s", accessorRaw)
+			}
+			if safeNonceRaw != "" {
+				var err error
+				safeNonce, err = parseSafeNonce("safe-nonce", safeNonceRaw)
+				if err != nil {
+					return err
+				}
+			}
+			batchSources := 0
+			for _, set := range []bool{batchFile != "", txBuilderFile != "", interactive} {
+				if set {
+					batchSources++
+				}
+			}
+			if batchSources > 0 {
+				if batchSources > 1 {
+					return fmt.Errorf("only one of --batch, --from-tx-builder or --interactive may be specified")
+				}
+				if to != "" || calldata != "" || value != "" || methodName != "" {
+					return fmt.Errorf("--to, --value, --calldata and --method cannot be used with --batch, --from-tx-builder or --interactive")
+				}
+				if multiSendRaw != "" && !common.IsHexAddress(multiSendRaw) {
+					return fmt.Errorf("invalid multisend address: %s", multiSendRaw)
+				}
+				return nil
+			}
 			if to == "" {
 				return fmt.Errorf("--to not specified")
 			} else if !common.IsHexAddress(to) {
 				return fmt.Errorf("invalid to address: %s", to)
 			}
+			if methodName != "" && calldata != "" {
+				return fmt.Errorf("only one of --calldata or --method may be specified")
+			}
+			if methodName == "" && (abiFile != "" || len(methodArgs) > 0) {
+				return fmt.Errorf("--abi and --arg require --method")
+			}
 			if calldata != "" {
 				if !strings.HasPrefix(calldata, "0x") {
 					calldata = "0x" + calldata
@@ -60,17 +132,14 @@ func createSafeProposalCmd() *cobra.Command {
 					return fmt.Errorf("invalid calldata hex: %v", err)
 				}
 			}
+			if value == "" {
+				value = "0"
+			}
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			toAddr := common.HexToAddress(to).Hex()
 			safeAddr := common.HexToAddress(safe).Hex()
 
-			key, keyErr := KeyFromFile(keyfile, password)
-			if keyErr != nil {
-				return keyErr
-			}
-
 			client, err := ethclient.Dial(rpcURL)
 			if err != nil {
 				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
@@ -81,11 +150,125 @@ func createSafeProposalCmd() *cobra.Command {
 				return fmt.Errorf("failed to get chain ID: %v", err)
 			}
 
+			if err := gasParams.Validate(client); err != nil {
+				return err
+			}
+
+			if batchFile != "" || txBuilderFile != "" || interactive {
+				var calls []MultiSendCall
+				switch {
+				case interactive:
+					calls, err = PromptMultiSendCalls()
+				case txBuilderFile != "":
+					var batch *TxBuilderBatch
+					batch, calls, err = ReadTxBuilderBatch(txBuilderFile)
+					if err == nil && batch.ChainID != "" && batch.ChainID != chainID.String() {
+						err = fmt.Errorf("%s was built for chain %s, but the RPC is on chain %s", txBuilderFile, batch.ChainID, chainID.String())
+					}
+				default:
+					calls, err = ReadMultiSendCalls(batchFile)
+				}
+				if err != nil {
+					return err
+				}
+
+				multiSendAddress, multiSendData, err := BuildMultiSendTransaction(common.HexToAddress(safeAddr), chainID, client, calls, multiSendRaw)
+				if err != nil {
+					return err
+				}
+
+				fmt.Printf("Batch of %d calls via MultiSend at %s (DelegateCall):\n", len(calls), multiSendAddress.Hex())
+				PrintMultiSendCalls(calls)
+				if !yes {
+					ok, err := ConfirmPrompt("Sign and propose this batch?")
+					if err != nil {
+						return err
+					}
+					if !ok {
+						return fmt.Errorf("batch not confirmed")
+					}
+				}
+
+				to = multiSendAddress.Hex()
+				value = "0"
+				calldata = common.Bytes2Hex(multiSendData)
+				safeOperationType = uint8(Safe.DelegateCall)
+			}
+
+			if methodName != "" {
+				var method abi.Method
+				if abiFile != "" {
+					contractABI, err := LoadABI(abiFile)
+					if err != nil {
+						return err
+					}
+					method, err = FindABIMethod(contractABI, methodName)
+					if err != nil {
+						return err
+					}
+				} else {
+					method, err = ParseMethodSignature(methodName)
+					if err != nil {
+						return err
+					}
+				}
+
+				encoded, err := EncodeMethodCall(method, methodArgs)
+				if err != nil {
+					return err
+				}
+				calldata = "0x" + common.Bytes2Hex(encoded)
+
+				fmt.Println("Encoded calldata:", calldata)
+				fmt.Println("Decoded calldata:")
+				if err := PrintDecodedCall(method, encoded, "  "); err != nil {
+					return err
+				}
+			}
+
+			toAddr := common.HexToAddress(to).Hex()
+
 			parsedValue := new(big.Int)
 			if _, ok := parsedValue.SetString(value, 10); !ok {
 				return fmt.Errorf("invalid value: %s", value)
 			}
 
+			if simulate || estimateSafeTxGas {
+				accessor := common.HexToAddress(accessorRaw)
+				if accessorRaw == "" {
+					accessor, err = ResolveSimulateTxAccessor(common.HexToAddress(safeAddr), client)
+					if err != nil {
+						return err
+					}
+				}
+
+				simulation, err := SimulateSafeTransaction(common.HexToAddress(safeAddr), common.HexToAddress(toAddr), parsedValue, common.FromHex(calldata), safeOperationType, accessor, client)
+				if err != nil {
+					return err
+				}
+				PrintSafeTxSimulation(simulation)
+
+				if !simulation.Success && !yes {
+					ok, err := ConfirmPrompt("The simulated call reverted. Propose it anyway?")
+					if err != nil {
+						return err
+					}
+					if !ok {
+						return fmt.Errorf("proposal not confirmed")
+					}
+				}
+
+				if estimateSafeTxGas {
+					gasParams.SafeTxGas = simulation.SafeTxGas()
+					fmt.Printf("Using safeTxGas %d\n", gasParams.SafeTxGas)
+				}
+			}
+
+			key, keyErr := signer.Open(keyfile, password)
+			if keyErr != nil {
+				return keyErr
+			}
+
 			if safeAPIURL == "" {
 				safeAPIURL = fmt.Sprintf("https://safe-client.safe.global/v1/chains/%s/transactions/%s/propose", chainID.String(), safeAddr)
 				fmt.Println("safe-api is not set, using default: ", safeAPIURL)
@@ -93,7 +276,12 @@ func createSafeProposalCmd() *cobra.Command {
 				fmt.Println("Using custom safe-api URL: ", safeAPIURL)
 			}
 
-			err = CreateSafeProposal(common.HexToAddress(safeAddr), toAddr, parsedValue.String(), calldata, Safe.SafeOperationType(safeOperationType), chainID, key, client, safeAPIURL)
+			nonce, err := SelectSafeNonce(common.HexToAddress(safeAddr), chainID, client, SafeClientBaseURLFromEndpoint(safeAPIURL), safeNonce)
+			if err != nil {
+				return err
+			}
+
+			err = CreateSafeProposal(common.HexToAddress(safeAddr), toAddr, parsedValue.String(), calldata, Safe.SafeOperationType(safeOperationType), gasParams, nonce, chainID, key, client, safeAPIURL)
 			if err != nil {
 				cmd.Printf("Error creating proposal: %v\n", err)
 				return fmt.Errorf("error creating proposal: %v", err)
@@ -106,15 +294,836 @@ func createSafeProposalCmd() *cobra.Command {
 
 	createProposalCmd.Flags().StringVar(&safe, "safe", "", "Safe address")
 	createProposalCmd.Flags().StringVar(&to, "to", "", "Recipient address")
-	createProposalCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Path to the keystore file")
-	createProposalCmd.Flags().StringVarP(&password, "password", "p", "", "Password for the keystore file")
+	createProposalCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer: "+signer.SpecUsage)
+	createProposalCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
 	createProposalCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL to retrieve chain ID")
 	createProposalCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe API URL")
 	createProposalCmd.Flags().StringVar(&value, "value", "", "Value to send with the transaction")
 	createProposalCmd.Flags().StringVar(&calldata, "calldata", "", "Hex-encoded ABI calldata to be sent with the transaction (e.g., function selector and arguments).")
 	createProposalCmd.Flags().Uint8Var(&safeOperationType, "safe-operation", 0, "Safe operation type: 0 (Call) or 1 (DelegateCall)")
+	createProposalCmd.Flags().StringVar(&abiFile, "abi", "", "Path to the ABI (or Hardhat/Foundry artifact) of the contract being called")
+	createProposalCmd.Flags().StringVar(&methodName, "method", "", "Method to call, by name or signature (e.g. \"transfer(address,uint256)\"); without --abi a full signature is required")
+	createProposalCmd.Flags().StringArrayVar(&methodArgs, "arg", nil, "Argument for --method, repeated once per argument in order; arrays and tuples are given as JSON arrays")
+	createProposalCmd.Flags().StringVar(&batchFile, "batch", "", "Path to a JSON array of {to, value, data, operation} calls to propose as a single MultiSend transaction")
+	createProposalCmd.Flags().StringVar(&txBuilderFile, "from-tx-builder", "", "Path to a Safe Transaction Builder JSON file to propose as a single MultiSend transaction")
+	createProposalCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Enter the calls of a MultiSend batch interactively")
+	createProposalCmd.Flags().StringVar(&multiSendRaw, "multisend", "", "Override the MultiSend contract used for batches")
+	createProposalCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation before signing a batch or a call that reverts in simulation")
+	addSafeTxGasFlags(createProposalCmd, &gasFlags)
+	createProposalCmd.Flags().BoolVar(&simulate, "simulate", false, "Simulate the call from the Safe before signing and report gas used, success, return data and revert reason")
+	createProposalCmd.Flags().BoolVar(&estimateSafeTxGas, "estimate-safe-tx-gas", false, "Simulate the call and use the measured gas (plus a margin) as safeTxGas")
+	createProposalCmd.Flags().StringVar(&accessorRaw, "simulate-tx-accessor", "", "Override the SimulateTxAccessor contract used for simulation")
+	createProposalCmd.Flags().StringVar(&safeNonceRaw, "safe-nonce", "", "Safe nonce for the proposal (defaults to the next nonce not taken by a queued proposal)")
 	createProposalCmd.MarkFlagRequired("keyfile")
 	createProposalCmd.MarkFlagRequired("safe")
 
 	return createProposalCmd
 }
+
+func createListSafeProposalsCmd() *cobra.Command {
+	var (
+		safe          string
+		keyfile       string
+		needsMySig    bool
+		minNonceRaw   string
+		maxNonceRaw   string
+		minNonce      *big.Int
+		maxNonce      *big.Int
+		signerAddress common.Address
+	)
+
+	listProposalsCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the queued proposals for a Safe",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !common.IsHexAddress(safe) {
+				return fmt.Errorf("invalid safe address: %s", safe)
+			}
+			var err error
+			if minNonceRaw != "" {
+				if minNonce, err = parseSafeNonce("min-nonce", minNonceRaw); err != nil {
+					return err
+				}
+			}
+			if maxNonceRaw != "" {
+				if maxNonce, err = parseSafeNonce("max-nonce", maxNonceRaw); err != nil {
+					return err
+				}
+			}
+			if needsMySig {
+				if keyfile == "" {
+					return fmt.Errorf("--needs-signature requires --keyfile")
+				}
+				address, err := signer.AddressFromSpec(keyfile, "")
+				if err != nil {
+					return err
+				}
+				signerAddress = address
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			if safeAPIURL == "" {
+				fmt.Println("safe-api is not set, using default: ", DefaultSafeClientURL)
+			}
+
+			proposals, err := ListSafeProposals(common.HexToAddress(safe), chainID, client, SafeClientBaseURL(safeAPIURL))
+			if err != nil {
+				return fmt.Errorf("error listing proposals: %v", err)
+			}
+
+			registry := DefaultSignatureRegistry()
+			shown := 0
+			for _, p := range proposals {
+				if minNonce != nil && p.Tx.Nonce.Cmp(minNonce) < 0 {
+					continue
+				}
+				if maxNonce != nil && p.Tx.Nonce.Cmp(maxNonce) > 0 {
+					continue
+				}
+				if needsMySig && !p.NeedsSignatureFrom(signerAddress) {
+					continue
+				}
+				shown++
+
+				calldata := common.FromHex(p.Tx.Data)
+
+				cmd.Printf("Nonce: %s\n", p.Tx.Nonce.String())
+				cmd.Printf("  SafeTxHash:    %s\n", p.SafeTxHash.Hex())
+				cmd.Printf("  To:            %s\n", p.Tx.To)
+				cmd.Printf("  Value:         %s\n", p.Tx.Value)
+				cmd.Printf("  Operation:     %s\n", p.Tx.Operation.String())
+				cmd.Println("  Call:")
+				registry.PrintCalldata(calldata, "    ")
+				if _, _, ok := registry.Decode(calldata); !ok && len(calldata) > 0 && p.Method != "" {
+					cmd.Printf("    The Safe service decodes this as %s, which could not be checked locally\n", p.Method)
+				}
+				cmd.Printf("  Confirmations: %d/%s\n", len(p.Confirmations), p.Threshold.String())
+				if big.NewInt(int64(len(p.Confirmations))).Cmp(p.Threshold) < 0 {
+					for _, signer := range p.MissingSigners {
+						cmd.Printf("  Awaiting:      %s\n", signer.Hex())
+					}
+				}
+			}
+
+			if shown == 0 {
+				cmd.Println("No queued proposals found")
+			}
+			return nil
+		},
+	}
+
+	listProposalsCmd.Flags().StringVar(&safe, "safe", "", "Safe address")
+	listProposalsCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer whose signature is checked (used with --needs-signature): "+signer.SpecUsage)
+	listProposalsCmd.Flags().BoolVar(&needsMySig, "needs-signature", false, "Only show proposals still awaiting a signature from the keyfile address")
+	listProposalsCmd.Flags().StringVar(&minNonceRaw, "min-nonce", "", "Only show proposals with a nonce greater than or equal to this value")
+	listProposalsCmd.Flags().StringVar(&maxNonceRaw, "max-nonce", "", "Only show proposals with a nonce less than or equal to this value")
+	listProposalsCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL to retrieve chain ID and Safe state")
+	listProposalsCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway URL")
+	listProposalsCmd.MarkFlagRequired("safe")
+	listProposalsCmd.MarkFlagRequired("rpc")
+
+	return listProposalsCmd
+}
+
+func createSafeProposalQueueCmd() *cobra.Command {
+	var safe string
+
+	queueCmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Show a Safe's queued proposals grouped by nonce, flagging conflicts and gaps",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !common.IsHexAddress(safe) {
+				return fmt.Errorf("invalid safe address: %s", safe)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			safeAddress := common.HexToAddress(safe)
+			safeInstance, err := GnosisSafe.NewGnosisSafe(safeAddress, client)
+			if err != nil {
+				return fmt.Errorf("failed to create GnosisSafe instance: %v", err)
+			}
+			onChainNonce, err := safeInstance.Nonce(&bind.CallOpts{})
+			if err != nil {
+				return fmt.Errorf("failed to fetch nonce: %v", err)
+			}
+
+			proposals, err := ListSafeProposals(safeAddress, chainID, client, SafeClientBaseURL(safeAPIURL))
+			if err != nil {
+				return fmt.Errorf("error listing proposals: %v", err)
+			}
+
+			queue, groups := GroupSafeProposalsByNonce(proposals, onChainNonce.Uint64())
+			cmd.Printf("On-chain nonce: %d\n", queue.OnChain)
+			if len(groups) == 0 {
+				cmd.Println("No queued proposals found")
+				return nil
+			}
+
+			registry := DefaultSignatureRegistry()
+			next := queue.OnChain
+			for _, nonce := range queue.Nonces() {
+				if nonce > next {
+					gap := SafeNonceRange{From: next, To: nonce - 1}
+					if gap.Len() == 1 {
+						cmd.Printf("Nonce %s: GAP, no proposal queued; later nonces cannot execute until one is\n", gap)
+					} else {
+						cmd.Printf("Nonces %s: GAP, no proposals queued for %d nonces; later nonces cannot execute until they are\n", gap, gap.Len())
+					}
+				}
+				next = nonce + 1
+
+				group := groups[nonce]
+				switch {
+				case len(group) > 1:
+					cmd.Printf("Nonce %d: CONFLICT, %d proposals compete and only one can execute\n", nonce, len(group))
+				default:
+					cmd.Printf("Nonce %d:\n", nonce)
+				}
+
+				for _, p := range group {
+					status := "awaiting signatures"
+					if big.NewInt(int64(len(p.Confirmations))).Cmp(p.Threshold) >= 0 {
+						status = "ready to execute"
+					}
+					cmd.Printf("  %s  %d/%s %s\n", p.SafeTxHash.Hex(), len(p.Confirmations), p.Threshold.String(), status)
+					cmd.Printf("    To %s, value %s\n", p.Tx.To, p.Tx.Value)
+					registry.PrintCalldata(common.FromHex(p.Tx.Data), "      ")
+				}
+			}
+
+			return nil
+		},
+	}
+
+	queueCmd.Flags().StringVar(&safe, "safe", "", "Safe address")
+	queueCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL to retrieve chain ID and Safe state")
+	queueCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway base URL")
+	queueCmd.MarkFlagRequired("safe")
+	queueCmd.MarkFlagRequired("rpc")
+
+	return queueCmd
+}
+
+func createRejectSafeProposalCmd() *cobra.Command {
+	var (
+		safe     string
+		nonceRaw string
+		nonce    *big.Int
+		keyfile  string
+		password string
+		yes      bool
+	)
+
+	rejectProposalCmd := &cobra.Command{
+		Use:   "reject",
+		Short: "Propose an on-chain rejection of every proposal queued at a nonce",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !common.IsHexAddress(safe) {
+				return fmt.Errorf("invalid safe address: %s", safe)
+			}
+			var err error
+			if nonce, err = parseSafeNonce("nonce", nonceRaw); err != nil {
+				return err
+			}
+			if keyfile == "" {
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, keyErr := signer.Open(keyfile, password)
+			if keyErr != nil {
+				return keyErr
+			}
+
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			if safeAPIURL == "" {
+				fmt.Println("safe-api is not set, using default: ", DefaultSafeClientURL)
+			}
+
+			err = RejectSafeNonce(common.HexToAddress(safe), nonce, chainID, key, client, SafeClientBaseURL(safeAPIURL), yes)
+			if err != nil {
+				return fmt.Errorf("error rejecting nonce %s: %v", nonce.String(), err)
+			}
+
+			return nil
+		},
+	}
+
+	rejectProposalCmd.Flags().StringVar(&safe, "safe", "", "Safe address")
+	rejectProposalCmd.Flags().StringVar(&nonceRaw, "nonce", "", "Safe nonce to reject")
+	rejectProposalCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer: "+signer.SpecUsage)
+	rejectProposalCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	rejectProposalCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL to retrieve chain ID and Safe state")
+	rejectProposalCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway URL")
+	rejectProposalCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Do not ask for confirmation before signing the rejection")
+	rejectProposalCmd.MarkFlagRequired("safe")
+	rejectProposalCmd.MarkFlagRequired("nonce")
+	rejectProposalCmd.MarkFlagRequired("keyfile")
+	rejectProposalCmd.MarkFlagRequired("rpc")
+
+	return rejectProposalCmd
+}
+
+func createVerifySafeProposalCmd() *cobra.Command {
+	var safeTxHash string
+
+	verifyProposalCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Independently verify a proposal and its confirmations as returned by the Safe service",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			parsedSafeTxHash, err := parseSafeTxHash(safeTxHash)
+			if err != nil {
+				return err
+			}
+			safeTxHash = parsedSafeTxHash.Hex()
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			if safeAPIURL == "" {
+				fmt.Println("safe-api is not set, using default: ", DefaultSafeClientURL)
+			}
+
+			return VerifySafeProposal(common.HexToHash(safeTxHash), chainID, client, SafeClientBaseURL(safeAPIURL))
+		},
+	}
+
+	verifyProposalCmd.Flags().StringVar(&safeTxHash, "safe-tx-hash", "", "SafeTxHash of the proposal to verify")
+	verifyProposalCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL to retrieve chain ID and Safe state")
+	verifyProposalCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway URL")
+	verifyProposalCmd.MarkFlagRequired("safe-tx-hash")
+	verifyProposalCmd.MarkFlagRequired("rpc")
+
+	return verifyProposalCmd
+}
+
+func createConfirmSafeProposalCmd() *cobra.Command {
+	var (
+		safeTxHash string
+		keyfile    string
+		password   string
+	)
+
+	confirmProposalCmd := &cobra.Command{
+		Use:   "confirm",
+		Short: "Add your signature to an existing proposal",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			parsedSafeTxHash, err := parseSafeTxHash(safeTxHash)
+			if err != nil {
+				return err
+			}
+			safeTxHash = parsedSafeTxHash.Hex()
+			if keyfile == "" {
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, keyErr := signer.Open(keyfile, password)
+			if keyErr != nil {
+				return keyErr
+			}
+
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			if safeAPIURL == "" {
+				fmt.Println("safe-api is not set, using default: ", DefaultSafeClientURL)
+			}
+
+			err = ConfirmSafeProposal(common.HexToHash(safeTxHash), chainID, key, client, SafeClientBaseURL(safeAPIURL))
+			if err != nil {
+				return fmt.Errorf("error confirming proposal: %v", err)
+			}
+
+			cmd.Printf("Confirmed %s as %s\n", common.HexToHash(safeTxHash).Hex(), key.Address().Hex())
+			return nil
+		},
+	}
+
+	confirmProposalCmd.Flags().StringVar(&safeTxHash, "safe-tx-hash", "", "SafeTxHash of the proposal to confirm")
+	confirmProposalCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer: "+signer.SpecUsage)
+	confirmProposalCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	confirmProposalCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL to retrieve chain ID and Safe state")
+	confirmProposalCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway URL")
+	confirmProposalCmd.MarkFlagRequired("safe-tx-hash")
+	confirmProposalCmd.MarkFlagRequired("keyfile")
+	confirmProposalCmd.MarkFlagRequired("rpc")
+
+	return confirmProposalCmd
+}
+
+func createExecuteSafeProposalCmd() *cobra.Command {
+	var (
+		safeTxHash string
+		keyfile    string
+		password   string
+	)
+
+	executeProposalCmd := &cobra.Command{
+		Use:   "execute",
+		Short: "Execute a proposal that has collected enough confirmations",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			parsedSafeTxHash, err := parseSafeTxHash(safeTxHash)
+			if err != nil {
+				return err
+			}
+			safeTxHash = parsedSafeTxHash.Hex()
+			if keyfile == "" {
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, keyErr := signer.Open(keyfile, password)
+			if keyErr != nil {
+				return keyErr
+			}
+
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			if safeAPIURL == "" {
+				fmt.Println("safe-api is not set, using default: ", DefaultSafeClientURL)
+			}
+
+			err = ExecuteSafeProposal(common.HexToHash(safeTxHash), chainID, key, client, SafeClientBaseURL(safeAPIURL))
+			if err != nil {
+				return fmt.Errorf("error executing proposal: %v", err)
+			}
+
+			return nil
+		},
+	}
+
+	executeProposalCmd.Flags().StringVar(&safeTxHash, "safe-tx-hash", "", "SafeTxHash of the proposal to execute")
+	executeProposalCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer of the account submitting the transaction: "+signer.SpecUsage)
+	executeProposalCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	executeProposalCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL used to check signatures and submit the transaction")
+	executeProposalCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway URL")
+	executeProposalCmd.MarkFlagRequired("safe-tx-hash")
+	executeProposalCmd.MarkFlagRequired("keyfile")
+	executeProposalCmd.MarkFlagRequired("rpc")
+
+	return executeProposalCmd
+}
+
+func createExportSafeProposalCmd() *cobra.Command {
+	var (
+		calldata          string
+		safe              string
+		safeOperationType uint8
+		to                string
+		value             string
+		batchFile         string
+		safeTxHashRaw     string
+		multiSendRaw      string
+		outfile           string
+		typedDataFile     string
+		txBuilderOutfile  string
+		gasFlags          safeTxGasFlags
+		gasParams         SafeTxGasParams
+		safeNonceRaw      string
+		safeNonce         *big.Int
+	)
+
+	exportProposalCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export an unsigned SafeTx to a file for offline signing, or as a Transaction Builder batch",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if outfile == "" && typedDataFile == "" && txBuilderOutfile == "" {
+				return fmt.Errorf("at least one of --out, --typed-data or --tx-builder must be specified")
+			}
+			var err error
+			gasParams, err = gasFlags.Parse()
+			if err != nil {
+				return err
+			}
+			if safeTxHashRaw != "" {
+				if batchFile != "" || to != "" {
+					return fmt.Errorf("--safe-tx-hash cannot be used with --batch or --to")
+				}
+				if _, err := parseSafeTxHash(safeTxHashRaw); err != nil {
+					return err
+				}
+				for _, name := range []string{"safe-tx-gas", "base-gas", "gas-price", "gas-token", "refund-receiver", "safe-nonce"} {
+					if cmd.Flags().Changed(name) {
+						return fmt.Errorf("--%s cannot change a proposal exported with --safe-tx-hash", name)
+					}
+				}
+				return nil
+			}
+			if !common.IsHexAddress(safe) {
+				return fmt.Errorf("invalid safe address: %s", safe)
+			}
+			if safeNonceRaw != "" {
+				var err error
+				safeNonce, err = parseSafeNonce("safe-nonce", safeNonceRaw)
+				if err != nil {
+					return err
+				}
+			}
+			if batchFile != "" {
+				if to != "" || calldata != "" {
+					return fmt.Errorf("--to and --calldata cannot be used with --batch")
+				}
+				if multiSendRaw != "" && !common.IsHexAddress(multiSendRaw) {
+					return fmt.Errorf("invalid multisend address: %s", multiSendRaw)
+				}
+				return nil
+			}
+			if !common.IsHexAddress(to) {
+				return fmt.Errorf("invalid to address: %s", to)
+			}
+			if calldata != "" && !IsValidHex(calldata) {
+				return fmt.Errorf("invalid calldata hex: %s", calldata)
+			}
+			if Safe.SafeOperationType(safeOperationType).String() == "Unknown" {
+				return fmt.Errorf("--safe-operation must be 0 (Call) or 1 (DelegateCall)")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			var safeAddress common.Address
+			var txData Safe.SafeTransactionData
+			var calls []MultiSendCall
+			var nonce *big.Int
+			if safeTxHashRaw == "" {
+				nonce, err = SelectSafeNonce(common.HexToAddress(safe), chainID, client, SafeClientBaseURL(safeAPIURL), safeNonce)
+				if err != nil {
+					return err
+				}
+			}
+			switch {
+			case safeTxHashRaw != "":
+				proposal, err := FetchVerifiedSafeProposal(common.HexToHash(safeTxHashRaw), chainID, client, SafeClientBaseURL(safeAPIURL))
+				if err != nil {
+					return err
+				}
+				safeAddress = proposal.SafeAddress
+				txData = proposal.Tx
+			case batchFile != "":
+				safeAddress = common.HexToAddress(safe)
+				calls, err = ReadMultiSendCalls(batchFile)
+				if err != nil {
+					return err
+				}
+				multiSendAddress, multiSendData, err := BuildMultiSendTransaction(safeAddress, chainID, client, calls, multiSendRaw)
+				if err != nil {
+					return err
+				}
+				txData, err = NewSafeTransactionData(safeAddress, multiSendAddress.Hex(), "0", common.Bytes2Hex(multiSendData), Safe.DelegateCall, nonce, client)
+				if err != nil {
+					return err
+				}
+			default:
+				parsedValue := new(big.Int)
+				if _, ok := parsedValue.SetString(value, 10); !ok {
+					return fmt.Errorf("invalid value: %s", value)
+				}
+				safeAddress = common.HexToAddress(safe)
+				txData, err = NewSafeTransactionData(safeAddress, common.HexToAddress(to).Hex(), parsedValue.String(), calldata, Safe.SafeOperationType(safeOperationType), nonce, client)
+				if err != nil {
+					return err
+				}
+			}
+
+			if safeTxHashRaw == "" {
+				if err := gasParams.Validate(client); err != nil {
+					return err
+				}
+				gasParams.Apply(&txData)
+			}
+
+			if outfile != "" {
+				txFile, err := ExportSafeTransaction(safeAddress, txData, chainID, client)
+				if err != nil {
+					return fmt.Errorf("error exporting proposal: %v", err)
+				}
+				if err := WriteJSONFile(outfile, txFile); err != nil {
+					return err
+				}
+				cmd.Printf("SafeTx %s written to %s\n", txFile.SafeTxHash, outfile)
+			}
+
+			if typedDataFile != "" {
+				safeInstance, err := Safe.NewSafe(safeAddress, client)
+				if err != nil {
+					return fmt.Errorf("failed to create Safe instance: %v", err)
+				}
+				version, err := safeInstance.VERSION(&bind.CallOpts{})
+				if err != nil {
+					return fmt.Errorf("failed to fetch Safe version: %v", err)
+				}
+				typedData, err := safetx.TypedData(safeAddress, SafeTxFromData(txData), chainID, version)
+				if err != nil {
+					return err
+				}
+				if err := WriteJSONFile(typedDataFile, typedData); err != nil {
+					return err
+				}
+				cmd.Printf("eth_signTypedData_v4 payload written to %s\n", typedDataFile)
+			}
+
+			if txBuilderOutfile != "" {
+				if calls == nil {
+					calls, err = SafeTransactionCalls(txData)
+					if err != nil {
+						return err
+					}
+				}
+				batch, err := NewTxBuilderBatch(chainID, safeAddress, fmt.Sprintf("Safe transaction nonce %s", txData.Nonce.String()), calls)
+				if err != nil {
+					return err
+				}
+				if err := WriteJSONFile(txBuilderOutfile, batch); err != nil {
+					return err
+				}
+				cmd.Printf("Transaction Builder batch of %d calls written to %s\n", len(calls), txBuilderOutfile)
+			}
+
+			return nil
+		},
+	}
+
+	exportProposalCmd.Flags().StringVar(&safe, "safe", "", "Safe address")
+	exportProposalCmd.Flags().StringVar(&to, "to", "", "Recipient address")
+	exportProposalCmd.Flags().StringVar(&value, "value", "0", "Value to send with the transaction")
+	exportProposalCmd.Flags().StringVar(&calldata, "calldata", "", "Hex-encoded ABI calldata to be sent with the transaction")
+	exportProposalCmd.Flags().Uint8Var(&safeOperationType, "safe-operation", 0, "Safe operation type: 0 (Call) or 1 (DelegateCall)")
+	exportProposalCmd.Flags().StringVar(&batchFile, "batch", "", "Export a JSON array of {to, value, data, operation} calls as a single MultiSend transaction")
+	exportProposalCmd.Flags().StringVar(&multiSendRaw, "multisend", "", "Override the MultiSend contract used for batches")
+	exportProposalCmd.Flags().StringVar(&safeTxHashRaw, "safe-tx-hash", "", "Export a proposal already queued on the Safe Transaction Service")
+	exportProposalCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway base URL")
+	exportProposalCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL to retrieve chain ID, nonce and Safe version")
+	exportProposalCmd.Flags().StringVarP(&outfile, "out", "o", "", "Path to write the unsigned SafeTx file to")
+	exportProposalCmd.Flags().StringVar(&typedDataFile, "typed-data", "", "Also write the eth_signTypedData_v4 payload to this path")
+	exportProposalCmd.Flags().StringVar(&txBuilderOutfile, "tx-builder", "", "Also write the calls as a Safe Transaction Builder JSON batch to this path")
+	addSafeTxGasFlags(exportProposalCmd, &gasFlags)
+	exportProposalCmd.Flags().StringVar(&safeNonceRaw, "safe-nonce", "", "Safe nonce for the proposal (defaults to the next nonce not taken by a queued proposal)")
+	exportProposalCmd.MarkFlagRequired("rpc")
+
+	return exportProposalCmd
+}
+
+func createSignSafeProposalCmd() *cobra.Command {
+	var (
+		txfile    string
+		keyfile   string
+		password  string
+		signature string
+		outfile   string
+	)
+
+	signProposalCmd := &cobra.Command{
+		Use:   "sign",
+		Short: "Sign an exported SafeTx file offline, or import a signature produced by an external wallet",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if keyfile == "" && signature == "" {
+				return fmt.Errorf("one of --keyfile or --signature must be specified")
+			}
+			if keyfile != "" && signature != "" {
+				return fmt.Errorf("only one of --keyfile or --signature may be specified")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txFile, safeTxHash, err := ReadSafeTxFile(txfile)
+			if err != nil {
+				return err
+			}
+
+			var sigFile *SafeTxSignatureFile
+			if signature != "" {
+				sigFile, err = ImportSafeTxSignature(txFile, safeTxHash, signature)
+				if err != nil {
+					return fmt.Errorf("error importing signature: %v", err)
+				}
+			} else {
+				cmd.Printf("Signing SafeTxHash %s for Safe %s (version %s) on chain %s:\n", safeTxHash.Hex(), txFile.Safe, txFile.SafeVersion, txFile.ChainID)
+				PrintSafeTransactionData(txFile.Tx)
+
+				key, keyErr := signer.Open(keyfile, password)
+				if keyErr != nil {
+					return keyErr
+				}
+
+				sigFile, err = SignSafeTxFile(txFile, safeTxHash, key)
+				if err != nil {
+					return fmt.Errorf("error signing proposal: %v", err)
+				}
+			}
+
+			if err := WriteJSONFile(outfile, sigFile); err != nil {
+				return err
+			}
+			cmd.Printf("Signature from %s written to %s\n", sigFile.Signer, outfile)
+			return nil
+		},
+	}
+
+	signProposalCmd.Flags().StringVarP(&txfile, "file", "f", "", "Path to the SafeTx file produced by proposal export")
+	signProposalCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer: "+signer.SpecUsage)
+	signProposalCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	signProposalCmd.Flags().StringVar(&signature, "signature", "", "Import a hex-encoded signature produced by an external wallet instead of signing")
+	signProposalCmd.Flags().StringVarP(&outfile, "out", "o", "", "Path to write the signature file to")
+	signProposalCmd.MarkFlagRequired("file")
+	signProposalCmd.MarkFlagRequired("out")
+
+	return signProposalCmd
+}
+
+func createCombineSafeProposalCmd() *cobra.Command {
+	var (
+		txfile         string
+		signatureFiles []string
+		submit         bool
+		execute        bool
+		keyfile        string
+		password       string
+	)
+
+	combineProposalCmd := &cobra.Command{
+		Use:   "combine",
+		Short: "Merge signature files for a SafeTx and submit them or pack them for execution",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(signatureFiles) == 0 {
+				return fmt.Errorf("--signature-file not specified")
+			}
+			if execute && (keyfile == "" || rpcURL == "") {
+				return fmt.Errorf("--execute requires --keyfile and --rpc")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			txFile, safeTxHash, err := ReadSafeTxFile(txfile)
+			if err != nil {
+				return err
+			}
+
+			signatures, err := ReadSafeTxSignatureFiles(signatureFiles, safeTxHash)
+			if err != nil {
+				return err
+			}
+
+			packed, err := PackSafeSignatures(signatures, big.NewInt(int64(len(signatures))))
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Packed signatures (%d): 0x%s\n", len(signatures), common.Bytes2Hex(packed))
+
+			if submit {
+				if safeAPIURL == "" {
+					fmt.Println("safe-api is not set, using default: ", DefaultSafeClientURL)
+				}
+				if err := SubmitSafeTxSignatures(txFile, safeTxHash, signatures, SafeClientBaseURL(safeAPIURL)); err != nil {
+					return fmt.Errorf("error submitting signatures: %v", err)
+				}
+			}
+
+			if execute {
+				key, keyErr := signer.Open(keyfile, password)
+				if keyErr != nil {
+					return keyErr
+				}
+
+				client, err := ethclient.Dial(rpcURL)
+				if err != nil {
+					return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+				}
+
+				chainID, err := client.ChainID(context.Background())
+				if err != nil {
+					return fmt.Errorf("failed to get chain ID: %v", err)
+				}
+				if chainID.String() != txFile.ChainID {
+					return fmt.Errorf("RPC is on chain %s but the SafeTx is for chain %s", chainID.String(), txFile.ChainID)
+				}
+
+				err = ExecuteSafeTransaction(common.HexToAddress(txFile.Safe), txFile.Tx, safeTxHash, packed, chainID, key, client)
+				if err != nil {
+					return fmt.Errorf("error executing proposal: %v", err)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	combineProposalCmd.Flags().StringVarP(&txfile, "file", "f", "", "Path to the SafeTx file produced by proposal export")
+	combineProposalCmd.Flags().StringSliceVarP(&signatureFiles, "signature-file", "s", nil, "Path to a signature file produced by proposal sign (may be repeated)")
+	combineProposalCmd.Flags().BoolVar(&submit, "submit", false, "Submit the signatures to the Safe client gateway")
+	combineProposalCmd.Flags().BoolVar(&execute, "execute", false, "Execute the SafeTx on-chain with the packed signatures")
+	combineProposalCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer of the account submitting the transaction (used with --execute): "+signer.SpecUsage)
+	combineProposalCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	combineProposalCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL used to submit the transaction (used with --execute)")
+	combineProposalCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway URL")
+	combineProposalCmd.MarkFlagRequired("file")
+
+	return combineProposalCmd
+}
@@ -2,38 +2,89 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
-	"io"
 	"math/big"
-	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/safetx"
+	"github.com/G7DAO/safes/signer"
 	"github.com/G7DAO/seer/bindings/GnosisSafe"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
-	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-func CreateSafeProposal(safeAddress common.Address, to string, value string, calldata string, safeOperationType Safe.SafeOperationType, chainID *big.Int, key *keystore.Key, client *ethclient.Client, safeApi string) error {
-	safeInstance, err := GnosisSafe.NewGnosisSafe(safeAddress, client)
+func CreateSafeProposal(safeAddress common.Address, to string, value string, calldata string, safeOperationType Safe.SafeOperationType, gasParams SafeTxGasParams, safeNonce *big.Int, chainID *big.Int, key signer.Signer, client *ethclient.Client, safeApi string) error {
+	txData, err := NewSafeTransactionData(safeAddress, to, value, calldata, safeOperationType, safeNonce, client)
 	if err != nil {
-		return fmt.Errorf("failed to create GnosisSafe instance: %w", err)
+		return err
 	}
+	gasParams.Apply(&txData)
 
-	nonce, err := safeInstance.Nonce(&bind.CallOpts{})
+	return SignAndProposeSafeTransaction(safeAddress, txData, chainID, key, client, safeApi)
+}
+
+// Signs a SafeTx built by the caller and proposes it to the Safe Transaction Service.
+func SignAndProposeSafeTransaction(safeAddress common.Address, txData Safe.SafeTransactionData, chainID *big.Int, key signer.Signer, client *ethclient.Client, safeApi string) error {
+	// Compute the hash of the transaction for signing, as the Safe's version expects it
+	safeTxHash, safeVersion, err := safetx.HashForSafe(client, safeAddress, SafeTxFromData(txData), chainID)
 	if err != nil {
-		return fmt.Errorf("failed to fetch nonce: %w", err)
+		return fmt.Errorf("failed to calculate SafeTxHash: %w", err)
 	}
 
-	txData := Safe.SafeTransactionData{
+	fmt.Printf("Proposing SafeTx %s (Safe version %s):\n", safeTxHash.Hex(), safeVersion)
+	PrintSafeTransactionData(txData)
+
+	// Sign the SafeTx's typed data with the configured signer
+	senderSignature, err := SignSafeTx(safeAddress, txData, chainID, safeVersion, key)
+	if err != nil {
+		return err
+	}
+
+	if err := ProposeSafeTransaction(safeApi, txData, safeTxHash, key.Address(), senderSignature); err != nil {
+		return err
+	}
+
+	fmt.Println("Safe proposal created successfully")
+	return nil
+}
+
+// Signs and proposes a call from the Safe built by a dedicated command, such as a transfer or an owner change. The
+// nonce is chosen with SelectSafeNonce unless safeNonce is set.
+func ProposeSafeCall(safeAddress common.Address, to common.Address, value *big.Int, calldata []byte, gasParams SafeTxGasParams, safeNonce *big.Int, chainID *big.Int, key signer.Signer, client *ethclient.Client, safeClientURL string) error {
+	nonce, err := SelectSafeNonce(safeAddress, chainID, client, safeClientURL, safeNonce)
+	if err != nil {
+		return err
+	}
+
+	proposeURL := fmt.Sprintf("%s/v1/chains/%s/transactions/%s/propose", safeClientURL, chainID.String(), safeAddress.Hex())
+	return CreateSafeProposal(safeAddress, to.Hex(), value.String(), common.Bytes2Hex(calldata), Safe.Call, gasParams, nonce, chainID, key, client, proposeURL)
+}
+
+// Builds an unsigned SafeTx with no refund parameters at the given nonce, or at the Safe's current on-chain nonce if
+// nonce is nil.
+func NewSafeTransactionData(safeAddress common.Address, to string, value string, calldata string, safeOperationType Safe.SafeOperationType, nonce *big.Int, client *ethclient.Client) (Safe.SafeTransactionData, error) {
+	if nonce == nil {
+		safeInstance, err := GnosisSafe.NewGnosisSafe(safeAddress, client)
+		if err != nil {
+			return Safe.SafeTransactionData{}, fmt.Errorf("failed to create GnosisSafe instance: %w", err)
+		}
+
+		nonce, err = safeInstance.Nonce(&bind.CallOpts{})
+		if err != nil {
+			return Safe.SafeTransactionData{}, fmt.Errorf("failed to fetch nonce: %w", err)
+		}
+	}
+
+	return Safe.SafeTransactionData{
 		To:             to,
 		Value:          value,
-		Data:           calldata,
+		Data:           strings.TrimPrefix(calldata, "0x"),
 		Operation:      safeOperationType,
 		SafeTxGas:      0,
 		BaseGas:        0,
@@ -41,24 +92,11 @@ func CreateSafeProposal(safeAddress common.Address, to string, value string, cal
 		GasToken:       Safe.NativeTokenAddress,
 		RefundReceiver: Safe.NativeTokenAddress,
 		Nonce:          nonce,
-	}
-
-	// Compute the hash of the transaction for signing
-	safeTxHash, err := Safe.CalculateSafeTxHash(safeAddress, txData, chainID)
-	if err != nil {
-		return fmt.Errorf("failed to calculate SafeTxHash: %w", err)
-	}
-
-	// Sign the hash with the user's private key
-	signature, err := crypto.Sign(safeTxHash.Bytes(), key.PrivateKey)
-	if err != nil {
-		return fmt.Errorf("failed to sign SafeTxHash: %w", err)
-	}
-
-	// Adjust the V value for Ethereum signature replay protection
-	signature[64] += 27
-	senderSignature := "0x" + common.Bytes2Hex(signature)
+	}, nil
+}
 
+// Submits a signed SafeTx to the propose endpoint of the Safe client gateway.
+func ProposeSafeTransaction(safeApi string, txData Safe.SafeTransactionData, safeTxHash common.Hash, sender common.Address, senderSignature string) error {
 	requestBody := map[string]interface{}{
 		"to":             txData.To,
 		"value":          txData.Value,
@@ -71,40 +109,46 @@ func CreateSafeProposal(safeAddress common.Address, to string, value string, cal
 		"refundReceiver": txData.RefundReceiver,
 		"nonce":          fmt.Sprintf("%d", txData.Nonce),
 		"safeTxHash":     safeTxHash.Hex(),
-		"sender":         key.Address.Hex(),
+		"sender":         sender.Hex(),
 		"signature":      senderSignature,
 		"origin":         fmt.Sprintf("{\"url\":\"%s\",\"name\":\"SafeProposal Creation\"}", safeApi),
 	}
 
-	jsonBody, err := json.Marshal(requestBody)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request body: %w", err)
-	}
+	return safeClientPost(safeApi, requestBody)
+}
 
-	req, err := http.NewRequest("POST", safeApi, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+// Prints the fields of a SafeTx so that they can be reviewed before signing.
+func PrintSafeTransactionData(txData Safe.SafeTransactionData) {
+	data := "0x" + txData.Data
+	fmt.Printf("  To:             %s\n", txData.To)
+	fmt.Printf("  Value:          %s\n", txData.Value)
+	fmt.Printf("  Data:           %s\n", data)
+	if calldata, err := hex.DecodeString(txData.Data); err == nil {
+		fmt.Println("  Decoded:")
+		DefaultSignatureRegistry().PrintCalldata(calldata, "    ")
 	}
-	req.Header.Set("Content-Type", "application/json")
+	fmt.Printf("  Operation:      %s\n", txData.Operation.String())
+	fmt.Printf("  SafeTxGas:      %d\n", txData.SafeTxGas)
+	fmt.Printf("  BaseGas:        %d\n", txData.BaseGas)
+	fmt.Printf("  GasPrice:       %s\n", txData.GasPrice)
+	fmt.Printf("  GasToken:       %s\n", txData.GasToken)
+	fmt.Printf("  RefundReceiver: %s\n", txData.RefundReceiver)
+	fmt.Printf("  Max refund:     %s\n", formatMaxSafeTxRefund(txData))
+	fmt.Printf("  Nonce:          %s\n", txData.Nonce.String())
+}
 
-	resp, err := http.DefaultClient.Do(req)
+// Signs a SafeTx as EIP-712 typed data for a Safe of the given version, returning the hex-encoded signature in the
+// format the Safe expects.
+func SignSafeTx(safeAddress common.Address, txData Safe.SafeTransactionData, chainID *big.Int, safeVersion string, key signer.Signer) (string, error) {
+	typedData, err := safetx.TypedData(safeAddress, SafeTxFromData(txData), chainID, safeVersion)
 	if err != nil {
-		return fmt.Errorf("failed to send HTTP request: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		var jsonErr interface{}
-		if err := json.Unmarshal(body, &jsonErr); err != nil {
-			return fmt.Errorf("HTTP %d, failed to parse error body: %s", resp.StatusCode, string(body))
-		}
-		formatted, _ := json.MarshalIndent(jsonErr, "", "  ")
-		return fmt.Errorf("HTTP %d, error response:\n%s", resp.StatusCode, formatted)
+	signature, err := key.SignTypedData(typedData)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign SafeTx: %w", err)
 	}
-
-	fmt.Println("Safe proposal created successfully")
-	return nil
+	return "0x" + common.Bytes2Hex(signature), nil
 }
 
 func IsValidHex(s string) bool {
@@ -123,3 +167,449 @@ func IsValidHex(s string) bool {
 	_, err := hex.DecodeString(s)
 	return err == nil
 }
+
+// SafeProposal is a queued multisig transaction together with its on-chain signing status.
+type SafeProposal struct {
+	Tx             Safe.SafeTransactionData
+	SafeTxHash     common.Hash
+	Method         string
+	Threshold      *big.Int
+	Confirmations  []common.Address
+	MissingSigners []common.Address
+}
+
+// Selector returns the 4-byte method selector of the proposal's calldata, or "" if it has none.
+func (p SafeProposal) Selector() string {
+	if len(p.Tx.Data) < 8 {
+		return ""
+	}
+	return "0x" + p.Tx.Data[:8]
+}
+
+// Whether the given owner has yet to sign a proposal that still needs signatures.
+func (p SafeProposal) NeedsSignatureFrom(owner common.Address) bool {
+	if big.NewInt(int64(len(p.Confirmations))).Cmp(p.Threshold) >= 0 {
+		return false
+	}
+	for _, signer := range p.MissingSigners {
+		if signer == owner {
+			return true
+		}
+	}
+	return false
+}
+
+func ListSafeProposals(safeAddress common.Address, chainID *big.Int, client *ethclient.Client, safeClientURL string) ([]SafeProposal, error) {
+	safeInstance, err := Safe.NewSafe(safeAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Safe instance: %w", err)
+	}
+
+	threshold, err := safeInstance.GetThreshold(&bind.CallOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch threshold: %w", err)
+	}
+
+	owners, err := safeInstance.GetOwners(&bind.CallOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch owners: %w", err)
+	}
+
+	ids, err := GetQueuedTransactionIDs(safeClientURL, chainID, safeAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	proposals := make([]SafeProposal, 0, len(ids))
+	for _, id := range ids {
+		details, err := GetTransactionDetails(safeClientURL, chainID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		txData, err := details.SafeTransactionData()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse transaction %s: %w", id, err)
+		}
+
+		proposal := SafeProposal{
+			Tx:         txData,
+			SafeTxHash: common.HexToHash(details.DetailedExecutionInfo.SafeTxHash),
+			Threshold:  threshold,
+		}
+		if details.TxData.DataDecoded != nil {
+			proposal.Method = details.TxData.DataDecoded.Method
+		}
+
+		confirmed := make(map[common.Address]bool)
+		for _, confirmation := range details.DetailedExecutionInfo.Confirmations {
+			signer := common.HexToAddress(confirmation.Signer.Value)
+			confirmed[signer] = true
+			proposal.Confirmations = append(proposal.Confirmations, signer)
+		}
+		for _, owner := range owners {
+			if !confirmed[owner] {
+				proposal.MissingSigners = append(proposal.MissingSigners, owner)
+			}
+		}
+
+		proposals = append(proposals, proposal)
+	}
+
+	return proposals, nil
+}
+
+// VerifiedSafeProposal is a proposal fetched from the service whose SafeTxHash has been recomputed locally.
+type VerifiedSafeProposal struct {
+	SafeAddress common.Address
+	Tx          Safe.SafeTransactionData
+	SafeTxHash  common.Hash
+	SafeVersion string
+	Details     *SafeClientTransactionDetails
+}
+
+// Fetches a proposal from the service and recomputes its SafeTxHash locally, failing if the two disagree.
+func FetchVerifiedSafeProposal(safeTxHash common.Hash, chainID *big.Int, client *ethclient.Client, safeClientURL string) (*VerifiedSafeProposal, error) {
+	details, err := GetTransactionDetails(safeClientURL, chainID, safeTxHash.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	txData, err := details.SafeTransactionData()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transaction: %w", err)
+	}
+
+	if !common.IsHexAddress(details.SafeAddress) {
+		return nil, fmt.Errorf("service returned an invalid safe address: %s", details.SafeAddress)
+	}
+	safeAddress := common.HexToAddress(details.SafeAddress)
+
+	recomputed, safeVersion, err := safetx.HashForSafe(client, safeAddress, SafeTxFromData(txData), chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate SafeTxHash: %w", err)
+	}
+
+	if recomputed != common.HexToHash(txData.SafeTxHash) || recomputed != safeTxHash {
+		return nil, fmt.Errorf("SafeTxHash mismatch: requested %s, service reported %s, recomputed %s", safeTxHash.Hex(), txData.SafeTxHash, recomputed.Hex())
+	}
+
+	return &VerifiedSafeProposal{
+		SafeAddress: safeAddress,
+		Tx:          txData,
+		SafeTxHash:  recomputed,
+		SafeVersion: safeVersion,
+		Details:     details,
+	}, nil
+}
+
+func ConfirmSafeProposal(safeTxHash common.Hash, chainID *big.Int, key signer.Signer, client *ethclient.Client, safeClientURL string) error {
+	proposal, err := FetchVerifiedSafeProposal(safeTxHash, chainID, client, safeClientURL)
+	if err != nil {
+		return err
+	}
+	safeAddress := proposal.SafeAddress
+
+	fmt.Printf("Signing SafeTxHash %s for Safe %s:\n", safeTxHash.Hex(), safeAddress.Hex())
+	PrintSafeTransactionData(proposal.Tx)
+
+	safeInstance, err := Safe.NewSafe(safeAddress, client)
+	if err != nil {
+		return fmt.Errorf("failed to create Safe instance: %w", err)
+	}
+
+	isOwner, err := safeInstance.IsOwner(&bind.CallOpts{}, key.Address())
+	if err != nil {
+		return fmt.Errorf("failed to check ownership: %w", err)
+	}
+	if !isOwner {
+		return fmt.Errorf("%s is not an owner of Safe %s", key.Address().Hex(), safeAddress.Hex())
+	}
+
+	signature, err := SignSafeTx(safeAddress, proposal.Tx, chainID, proposal.SafeVersion, key)
+	if err != nil {
+		return err
+	}
+
+	if err := ConfirmTransaction(safeClientURL, chainID, safeTxHash, signature); err != nil {
+		return fmt.Errorf("failed to submit confirmation: %w", err)
+	}
+
+	fmt.Println("Safe proposal confirmed successfully")
+	return nil
+}
+
+// Selects the confirmations that can be packed for execution: each signature is recovered and kept only if it is
+// from the signer the service claims and that signer is one of owners. Approved-hash signatures name their owner
+// instead of being recovered. Contract signatures are skipped, since PackSafeSignatures cannot pack their dynamic
+// part. The reasons for skipping confirmations are returned alongside.
+func OwnerConfirmationSignatures(safeTxHash common.Hash, confirmations []SafeClientConfirmation, owners []common.Address) (map[common.Address][]byte, []string) {
+	isOwner := make(map[common.Address]bool)
+	for _, owner := range owners {
+		isOwner[owner] = true
+	}
+
+	signatures := make(map[common.Address][]byte)
+	var skipped []string
+	for _, confirmation := range confirmations {
+		claimed := common.HexToAddress(confirmation.Signer.Value)
+		if confirmation.Signature == nil {
+			skipped = append(skipped, fmt.Sprintf("%s: no signature", claimed.Hex()))
+			continue
+		}
+		signature, err := hex.DecodeString(strings.TrimPrefix(*confirmation.Signature, "0x"))
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: invalid signature hex: %v", claimed.Hex(), err))
+			continue
+		}
+		signer, kind, err := ParseSafeSignature(safeTxHash, signature)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", claimed.Hex(), err))
+			continue
+		}
+		if signer != claimed {
+			skipped = append(skipped, fmt.Sprintf("%s: %s signature is from %s", claimed.Hex(), kind, signer.Hex()))
+			continue
+		}
+		if !isOwner[signer] {
+			skipped = append(skipped, fmt.Sprintf("%s: not currently an owner", signer.Hex()))
+			continue
+		}
+		if kind == SafeSignatureContract {
+			skipped = append(skipped, fmt.Sprintf("%s: %s signatures cannot be packed for execution", signer.Hex(), kind))
+			continue
+		}
+		signatures[signer] = signature
+	}
+	return signatures, skipped
+}
+
+// Concatenates 65-byte owner signatures in ascending order of owner address, as checkNSignatures requires.
+// Only the first threshold signatures are included.
+func PackSafeSignatures(signatures map[common.Address][]byte, threshold *big.Int) ([]byte, error) {
+	if big.NewInt(int64(len(signatures))).Cmp(threshold) < 0 {
+		return nil, fmt.Errorf("not enough signatures: have %d, need %s", len(signatures), threshold.String())
+	}
+
+	owners := make([]common.Address, 0, len(signatures))
+	for owner := range signatures {
+		owners = append(owners, owner)
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		return bytes.Compare(owners[i].Bytes(), owners[j].Bytes()) < 0
+	})
+
+	var packed []byte
+	for i, owner := range owners {
+		if int64(i) >= threshold.Int64() {
+			break
+		}
+		signature := signatures[owner]
+		if len(signature) != 65 {
+			return nil, fmt.Errorf("signature from %s has length %d, only 65-byte signatures are supported", owner.Hex(), len(signature))
+		}
+		if signature[64] == 0 {
+			return nil, fmt.Errorf("signature from %s is a contract signature, which is not supported", owner.Hex())
+		}
+		packed = append(packed, signature...)
+	}
+
+	return packed, nil
+}
+
+// SafeTransactionArgs holds the SafeTx fields in the types expected by the Safe contract bindings.
+type SafeTransactionArgs struct {
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          *big.Int
+}
+
+func NewSafeTransactionArgs(txData Safe.SafeTransactionData) (SafeTransactionArgs, error) {
+	value, ok := new(big.Int).SetString(txData.Value, 10)
+	if !ok {
+		return SafeTransactionArgs{}, fmt.Errorf("invalid value: %s", txData.Value)
+	}
+	gasPrice, ok := new(big.Int).SetString(txData.GasPrice, 10)
+	if !ok {
+		return SafeTransactionArgs{}, fmt.Errorf("invalid gas price: %s", txData.GasPrice)
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(txData.Data, "0x"))
+	if err != nil {
+		return SafeTransactionArgs{}, fmt.Errorf("invalid data: %w", err)
+	}
+
+	return SafeTransactionArgs{
+		To:             common.HexToAddress(txData.To),
+		Value:          value,
+		Data:           data,
+		Operation:      uint8(txData.Operation),
+		SafeTxGas:      new(big.Int).SetUint64(txData.SafeTxGas),
+		BaseGas:        new(big.Int).SetUint64(txData.BaseGas),
+		GasPrice:       gasPrice,
+		GasToken:       common.HexToAddress(txData.GasToken),
+		RefundReceiver: common.HexToAddress(txData.RefundReceiver),
+		Nonce:          txData.Nonce,
+	}, nil
+}
+
+// Submits execTransaction for the given SafeTx and packed signatures, after checking them with an eth_call to
+// checkSignatures. It waits for the receipt and reports whether ExecutionSuccess or ExecutionFailure was emitted.
+func ExecuteSafeTransaction(safeAddress common.Address, txData Safe.SafeTransactionData, safeTxHash common.Hash, signatures []byte, chainID *big.Int, key signer.Signer, client *ethclient.Client) error {
+	safeInstance, err := Safe.NewSafe(safeAddress, client)
+	if err != nil {
+		return fmt.Errorf("failed to create Safe instance: %w", err)
+	}
+
+	args, err := NewSafeTransactionArgs(txData)
+	if err != nil {
+		return err
+	}
+
+	nonce, err := safeInstance.Nonce(&bind.CallOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch nonce: %w", err)
+	}
+	if nonce.Cmp(args.Nonce) != 0 {
+		return fmt.Errorf("proposal nonce %s does not match the Safe's current nonce %s", args.Nonce.String(), nonce.String())
+	}
+
+	if err := safeInstance.CheckSignatures(&bind.CallOpts{From: key.Address()}, safeTxHash, []byte{}, signatures); err != nil {
+		return fmt.Errorf("signatures rejected by checkSignatures: %w", err)
+	}
+
+	transactOpts, err := signer.NewTransactor(key, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to create transactor: %w", err)
+	}
+
+	tx, err := safeInstance.ExecTransaction(transactOpts, args.To, args.Value, args.Data, args.Operation, args.SafeTxGas, args.BaseGas, args.GasPrice, args.GasToken, args.RefundReceiver, signatures)
+	if err != nil {
+		return fmt.Errorf("failed to submit execTransaction: %w", err)
+	}
+	fmt.Println("Transaction submitted:", tx.Hash().Hex())
+
+	receipt, err := bind.WaitMined(context.Background(), client, tx)
+	if err != nil {
+		return fmt.Errorf("failed waiting for transaction receipt: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+
+	for _, log := range receipt.Logs {
+		if log.Address != safeAddress {
+			continue
+		}
+		if event, err := safeInstance.ParseExecutionSuccess(*log); err == nil && event.TxHash == safeTxHash {
+			fmt.Printf("ExecutionSuccess: SafeTx %s executed in transaction %s (payment: %s)\n", safeTxHash.Hex(), tx.Hash().Hex(), event.Payment.String())
+			return nil
+		}
+		if event, err := safeInstance.ParseExecutionFailure(*log); err == nil && event.TxHash == safeTxHash {
+			return fmt.Errorf("ExecutionFailure: SafeTx %s failed in transaction %s (payment: %s)", safeTxHash.Hex(), tx.Hash().Hex(), event.Payment.String())
+		}
+	}
+
+	return fmt.Errorf("neither ExecutionSuccess nor ExecutionFailure was emitted by transaction %s", tx.Hash().Hex())
+}
+
+func ExecuteSafeProposal(safeTxHash common.Hash, chainID *big.Int, key signer.Signer, client *ethclient.Client, safeClientURL string) error {
+	proposal, err := FetchVerifiedSafeProposal(safeTxHash, chainID, client, safeClientURL)
+	if err != nil {
+		return err
+	}
+
+	safeInstance, err := Safe.NewSafe(proposal.SafeAddress, client)
+	if err != nil {
+		return fmt.Errorf("failed to create Safe instance: %w", err)
+	}
+
+	threshold, err := safeInstance.GetThreshold(&bind.CallOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch threshold: %w", err)
+	}
+
+	owners, err := safeInstance.GetOwners(&bind.CallOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch owners: %w", err)
+	}
+
+	signatures, skipped := OwnerConfirmationSignatures(safeTxHash, proposal.Details.DetailedExecutionInfo.Confirmations, owners)
+	for _, reason := range skipped {
+		fmt.Println("Ignoring confirmation:", reason)
+	}
+
+	packed, err := PackSafeSignatures(signatures, threshold)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Executing SafeTxHash %s on Safe %s:\n", safeTxHash.Hex(), proposal.SafeAddress.Hex())
+	PrintSafeTransactionData(proposal.Tx)
+
+	return ExecuteSafeTransaction(proposal.SafeAddress, proposal.Tx, safeTxHash, packed, chainID, key, client)
+}
+
+// Proposes the standard rejection of a nonce: a zero-value call from the Safe to itself with empty data. Once it
+// executes, every other proposal queued at that nonce can no longer be executed. The proposals it invalidates are
+// printed and, unless skipConfirmation is set, the user is asked to confirm before signing.
+func RejectSafeNonce(safeAddress common.Address, nonce *big.Int, chainID *big.Int, key signer.Signer, client *ethclient.Client, safeClientURL string, skipConfirmation bool) error {
+	nonce, err := SelectSafeNonce(safeAddress, chainID, client, safeClientURL, nonce)
+	if err != nil {
+		return err
+	}
+
+	proposals, err := ListSafeProposals(safeAddress, chainID, client, safeClientURL)
+	if err != nil {
+		return fmt.Errorf("failed to list queued proposals: %w", err)
+	}
+
+	var rejected []SafeProposal
+	for _, proposal := range proposals {
+		if proposal.Tx.Nonce.Cmp(nonce) == 0 {
+			rejected = append(rejected, proposal)
+		}
+	}
+
+	if len(rejected) == 0 {
+		fmt.Printf("No proposals are queued at nonce %s, the rejection will only consume the nonce\n", nonce.String())
+	} else {
+		fmt.Printf("Executing this rejection will make these proposals at nonce %s unexecutable:\n", nonce.String())
+		registry := DefaultSignatureRegistry()
+		for _, proposal := range rejected {
+			fmt.Printf("  %s  to %s, value %s, %s (%d/%s confirmations)\n", proposal.SafeTxHash.Hex(), proposal.Tx.To, proposal.Tx.Value, registry.MethodName(common.FromHex(proposal.Tx.Data)), len(proposal.Confirmations), proposal.Threshold.String())
+		}
+	}
+
+	if !skipConfirmation {
+		ok, err := ConfirmPrompt(fmt.Sprintf("Sign and propose a rejection of nonce %s?", nonce.String()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("rejection not confirmed")
+		}
+	}
+
+	proposeURL := fmt.Sprintf("%s/v1/chains/%s/transactions/%s/propose", safeClientURL, chainID.String(), safeAddress.Hex())
+	return SignAndProposeSafeTransaction(safeAddress, NewRejectionSafeTransactionData(safeAddress, nonce), chainID, key, client, proposeURL)
+}
+
+// Builds the SafeTx rejecting a nonce: a call from the Safe to itself with no value, no data and no refund.
+func NewRejectionSafeTransactionData(safeAddress common.Address, nonce *big.Int) Safe.SafeTransactionData {
+	txData := Safe.SafeTransactionData{
+		To:        safeAddress.Hex(),
+		Value:     "0",
+		Data:      "",
+		Operation: Safe.Call,
+		Nonce:     nonce,
+	}
+	SafeTxGasParams{}.Apply(&txData)
+	return txData
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/safetx"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func sortedAddresses(addresses []common.Address) []common.Address {
+	sorted := append([]common.Address{}, addresses...)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i].Bytes(), sorted[j].Bytes()) < 0 })
+	return sorted
+}
+
+func TestPackSafeSignatures(t *testing.T) {
+	keys, addresses := testKeys(t, 3)
+	safeTxHash := crypto.Keccak256Hash([]byte("SafeTx"))
+	signatures := make(map[common.Address][]byte)
+	for i, key := range keys {
+		signatures[addresses[i]] = signSafeTxHash(t, key, safeTxHash)
+	}
+	sorted := sortedAddresses(addresses)
+
+	packed, err := PackSafeSignatures(signatures, big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []byte
+	for _, owner := range sorted {
+		want = append(want, signatures[owner]...)
+	}
+	if !bytes.Equal(packed, want) {
+		t.Errorf("PackSafeSignatures did not order signatures by owner address")
+	}
+
+	packed, err = PackSafeSignatures(signatures, big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(packed, want[:130]) {
+		t.Errorf("PackSafeSignatures did not keep the signatures of the two lowest owners")
+	}
+
+	if _, err := PackSafeSignatures(signatures, big.NewInt(4)); err == nil {
+		t.Error("PackSafeSignatures accepted fewer signatures than the threshold")
+	}
+
+	contract := append(common.LeftPadBytes(sorted[0].Bytes(), 32), make([]byte, 33)...)
+	if _, err := PackSafeSignatures(map[common.Address][]byte{sorted[0]: contract}, big.NewInt(1)); err == nil {
+		t.Error("PackSafeSignatures accepted a contract signature")
+	}
+	if _, err := PackSafeSignatures(map[common.Address][]byte{sorted[0]: make([]byte, 64)}, big.NewInt(1)); err == nil {
+		t.Error("PackSafeSignatures accepted a 64-byte signature")
+	}
+}
+
+func confirmation(signer common.Address, signature []byte) SafeClientConfirmation {
+	encoded := "0x" + common.Bytes2Hex(signature)
+	return SafeClientConfirmation{Signer: SafeClientAddress{Value: signer.Hex()}, Signature: &encoded}
+}
+
+// A confirmation from a removed owner must not take the place of a current owner's signature, even when its address
+// sorts first.
+func TestOwnerConfirmationSignatures(t *testing.T) {
+	chain := newTestChain(t)
+	keys, addresses := testKeys(t, 4)
+	// The lowest address stands in for a removed owner, so that it would be packed first.
+	order := make([]int, len(addresses))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return bytes.Compare(addresses[order[i]].Bytes(), addresses[order[j]].Bytes()) < 0
+	})
+	removed, owners := order[0], []common.Address{addresses[order[1]], addresses[order[2]], addresses[order[3]]}
+	safeAddress := chain.deploySafe(t, owners, 2)
+
+	txData := Safe.SafeTransactionData{
+		To:             owners[0].Hex(),
+		Value:          "0",
+		GasPrice:       "0",
+		GasToken:       Safe.NativeTokenAddress,
+		RefundReceiver: Safe.NativeTokenAddress,
+		Nonce:          big.NewInt(0),
+	}
+	preimage, err := SafeTxHashPreimage(safeAddress, txData, simulatedChainID, "1.4.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	safeTxHash := crypto.Keccak256Hash(preimage)
+
+	confirmations := []SafeClientConfirmation{
+		confirmation(addresses[removed], signSafeTxHash(t, keys[removed], safeTxHash)),
+		// Claims to be an owner, but is signed by the removed owner.
+		confirmation(owners[2], signSafeTxHash(t, keys[removed], safeTxHash)),
+		confirmation(owners[0], signSafeTxHash(t, keys[order[1]], safeTxHash)),
+		confirmation(owners[1], signSafeTxHash(t, keys[order[2]], safeTxHash)),
+		{Signer: SafeClientAddress{Value: owners[2].Hex()}},
+	}
+
+	signatures, skipped := OwnerConfirmationSignatures(safeTxHash, confirmations, owners)
+	if len(signatures) != 2 || signatures[owners[0]] == nil || signatures[owners[1]] == nil {
+		t.Fatalf("kept signatures from %v, want %s and %s", signatures, owners[0].Hex(), owners[1].Hex())
+	}
+	if len(skipped) != 3 {
+		t.Errorf("skipped %d confirmations, want 3: %v", len(skipped), skipped)
+	}
+
+	packed, err := PackSafeSignatures(signatures, big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	safeInstance, err := Safe.NewSafe(safeAddress, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := safeInstance.CheckNSignatures(&bind.CallOpts{}, safeTxHash, preimage, packed, big.NewInt(2)); err != nil {
+		t.Errorf("packed signatures rejected by checkNSignatures: %v", err)
+	}
+}
+
+// A contract signature among the confirmations is skipped rather than packed, so the ECDSA, eth_sign and
+// approved-hash signatures of the other owners still reach the threshold.
+func TestOwnerConfirmationSignaturesMixed(t *testing.T) {
+	chain := newTestChain(t)
+	keys, addresses := testKeys(t, 4)
+	order := make([]int, len(addresses))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return bytes.Compare(addresses[order[i]].Bytes(), addresses[order[j]].Bytes()) < 0
+	})
+	owners := make([]common.Address, len(order))
+	for i, index := range order {
+		owners[i] = addresses[index]
+	}
+	safeAddress := chain.deploySafe(t, owners, 3)
+
+	txData := Safe.SafeTransactionData{
+		To:             owners[0].Hex(),
+		Value:          "0",
+		GasPrice:       "0",
+		GasToken:       Safe.NativeTokenAddress,
+		RefundReceiver: Safe.NativeTokenAddress,
+		Nonce:          big.NewInt(0),
+	}
+	preimage, err := SafeTxHashPreimage(safeAddress, txData, simulatedChainID, "1.4.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	safeTxHash := crypto.Keccak256Hash(preimage)
+
+	// The owner with the lowest address signs with a contract signature, which would be packed first.
+	contract := append(common.LeftPadBytes(owners[0].Bytes(), 32), common.LeftPadBytes(big.NewInt(65).Bytes(), 32)...)
+	contract = append(contract, 0)
+	ethSign, err := crypto.Sign(accounts.TextHash(safeTxHash.Bytes()), keys[order[2]])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethSign[64] += 31
+	// The executor's own approval is accepted by checkNSignatures without an approveHash transaction.
+	approved := append(common.LeftPadBytes(owners[3].Bytes(), 32), make([]byte, 32)...)
+	approved = append(approved, 1)
+
+	confirmations := []SafeClientConfirmation{
+		confirmation(owners[0], contract),
+		confirmation(owners[1], signSafeTxHash(t, keys[order[1]], safeTxHash)),
+		confirmation(owners[2], ethSign),
+		confirmation(owners[3], approved),
+	}
+	signatures, skipped := OwnerConfirmationSignatures(safeTxHash, confirmations, owners)
+	if len(signatures) != 3 || signatures[owners[0]] != nil {
+		t.Fatalf("kept signatures from %v, want every owner except %s", signatures, owners[0].Hex())
+	}
+	if len(skipped) != 1 {
+		t.Errorf("skipped %d confirmations, want 1: %v", len(skipped), skipped)
+	}
+
+	packed, err := PackSafeSignatures(signatures, big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append(append(append([]byte{}, signatures[owners[1]]...), ethSign...), approved...)
+	if !bytes.Equal(packed, want) {
+		t.Errorf("packed signatures are not in ascending owner order")
+	}
+
+	safeInstance, err := Safe.NewSafe(safeAddress, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	callOpts := &bind.CallOpts{From: owners[3]}
+	if err := safeInstance.CheckNSignatures(callOpts, safeTxHash, preimage, packed, big.NewInt(3)); err != nil {
+		t.Errorf("packed signatures rejected by checkNSignatures: %v", err)
+	}
+}
+
+func TestNewRejectionSafeTransactionData(t *testing.T) {
+	chain := newTestChain(t)
+	_, owners := testKeys(t, 1)
+	safeAddress := chain.deploySafe(t, owners, 1)
+	nonce := big.NewInt(7)
+
+	txData := NewRejectionSafeTransactionData(safeAddress, nonce)
+	if common.HexToAddress(txData.To) != safeAddress {
+		t.Errorf("rejection sent to %s, want the Safe %s", txData.To, safeAddress.Hex())
+	}
+	if txData.Value != "0" || txData.Data != "" || txData.Operation != Safe.Call {
+		t.Errorf("rejection has value %q, data %q and operation %d, want a plain call with no value or data", txData.Value, txData.Data, txData.Operation)
+	}
+	if txData.SafeTxGas != 0 || txData.BaseGas != 0 || txData.GasPrice != "0" ||
+		common.HexToAddress(txData.GasToken) != (common.Address{}) || common.HexToAddress(txData.RefundReceiver) != (common.Address{}) {
+		t.Errorf("rejection has gas fields %+v, want no refund", txData)
+	}
+	if txData.Nonce.Cmp(nonce) != 0 {
+		t.Errorf("rejection at nonce %s, want %s", txData.Nonce, nonce)
+	}
+
+	// The payload must hash as the Safe itself hashes a rejection.
+	safeCaller, err := Safe.NewSafeCaller(safeAddress, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := safeCaller.GetTransactionHash(&bind.CallOpts{}, safeAddress, big.NewInt(0), nil, uint8(Safe.Call), big.NewInt(0), big.NewInt(0), big.NewInt(0), common.Address{}, common.Address{}, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := safetx.Hash(safeAddress, SafeTxFromData(txData), simulatedChainID, "1.4.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != common.Hash(want) {
+		t.Errorf("rejection hashes to %s, the Safe computes %s", got.Hex(), common.Hash(want).Hex())
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+func CreateSafeCmd() *cobra.Command {
+	safeCmd := &cobra.Command{
+		Use:   "safe",
+		Short: "Inspect and manage Safes",
+		Long: `Inspect Safes and the transactions they sign, and propose changes to their owners, modules, guards and
+fallback handler. Safe settings can only be changed by the Safe itself, so these changes are proposed as Safe
+transactions for the owners to sign.`,
+	}
+
+	safeCmd.AddCommand(createSafeCreateCmd())
+	safeCmd.AddCommand(createSafePredictCmd())
+	safeCmd.AddCommand(createSafeCounterfactualCmd())
+	safeCmd.AddCommand(createSafeReplicateCmd())
+	safeCmd.AddCommand(createSafeHashCmd())
+	safeCmd.AddCommand(createSafeOwnersCmd())
+	safeCmd.AddCommand(createSafeModulesCmd())
+	safeCmd.AddCommand(createSafeGuardCmd())
+	safeCmd.AddCommand(createSafeHandlerCmd())
+	safeCmd.SetOut(os.Stdout)
+
+	return safeCmd
+}
+
+func createSafeHashCmd() *cobra.Command {
+	var (
+		calldata          string
+		safe              string
+		safeOperationType uint8
+		to                string
+		value             string
+		safeTxHashRaw     string
+		gasFlags          safeTxGasFlags
+		gasParams         SafeTxGasParams
+		safeNonceRaw      string
+		safeNonce         *big.Int
+	)
+
+	hashCmd := &cobra.Command{
+		Use:   "hash",
+		Short: "Show every component of a SafeTx hash and check it against the Safe",
+		Long: `Show the EIP-712 domain separator, SafeTx struct hash, final SafeTxHash and typed data of a SafeTx, so that the
+values displayed by a hardware wallet can be checked. The SafeTx is either given field by field or fetched from the Safe
+Transaction Service with --safe-tx-hash. The results are checked against the Safe's domainSeparator and
+getTransactionHash.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			gasParams, err = gasFlags.Parse()
+			if err != nil {
+				return err
+			}
+			if safeTxHashRaw != "" {
+				if to != "" || safe != "" {
+					return fmt.Errorf("--safe-tx-hash cannot be used with --safe or --to")
+				}
+				if _, err := parseSafeTxHash(safeTxHashRaw); err != nil {
+					return err
+				}
+				for _, name := range []string{"value", "calldata", "safe-operation", "safe-tx-gas", "base-gas", "gas-price", "gas-token", "refund-receiver", "safe-nonce"} {
+					if cmd.Flags().Changed(name) {
+						return fmt.Errorf("--%s cannot be used with --safe-tx-hash", name)
+					}
+				}
+				return nil
+			}
+			if !common.IsHexAddress(safe) {
+				return fmt.Errorf("invalid safe address: %s", safe)
+			}
+			if !common.IsHexAddress(to) {
+				return fmt.Errorf("invalid to address: %s", to)
+			}
+			if calldata != "" && !IsValidHex(calldata) {
+				return fmt.Errorf("invalid calldata hex: %s", calldata)
+			}
+			if Safe.SafeOperationType(safeOperationType).String() == "Unknown" {
+				return fmt.Errorf("--safe-operation must be 0 (Call) or 1 (DelegateCall)")
+			}
+			if safeNonceRaw != "" {
+				var err error
+				safeNonce, err = parseSafeNonce("safe-nonce", safeNonceRaw)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			var safeAddress common.Address
+			var txData Safe.SafeTransactionData
+			if safeTxHashRaw != "" {
+				details, err := GetTransactionDetails(SafeClientBaseURL(safeAPIURL), chainID, common.HexToHash(safeTxHashRaw).Hex())
+				if err != nil {
+					return err
+				}
+				txData, err = details.SafeTransactionData()
+				if err != nil {
+					return fmt.Errorf("failed to parse transaction: %v", err)
+				}
+				if !common.IsHexAddress(details.SafeAddress) {
+					return fmt.Errorf("service returned an invalid safe address: %s", details.SafeAddress)
+				}
+				safeAddress = common.HexToAddress(details.SafeAddress)
+			} else {
+				parsedValue := new(big.Int)
+				if _, ok := parsedValue.SetString(value, 10); !ok {
+					return fmt.Errorf("invalid value: %s", value)
+				}
+				safeAddress = common.HexToAddress(safe)
+				txData, err = NewSafeTransactionData(safeAddress, common.HexToAddress(to).Hex(), parsedValue.String(), calldata, Safe.SafeOperationType(safeOperationType), safeNonce, client)
+				if err != nil {
+					return err
+				}
+				gasParams.Apply(&txData)
+			}
+
+			safeInstance, err := Safe.NewSafe(safeAddress, client)
+			if err != nil {
+				return fmt.Errorf("failed to create Safe instance: %v", err)
+			}
+			version, err := safeInstance.VERSION(&bind.CallOpts{})
+			if err != nil {
+				return fmt.Errorf("failed to fetch Safe version: %v", err)
+			}
+
+			cmd.Printf("Safe %s (version %s) on chain %s\n", safeAddress.Hex(), version, chainID.String())
+			PrintSafeTransactionData(txData)
+
+			components, err := ComputeSafeTxHashComponents(safeAddress, txData, chainID, version)
+			if err != nil {
+				return err
+			}
+			if err := PrintSafeTxHashComponents(components); err != nil {
+				return err
+			}
+
+			if safeTxHashRaw != "" && components.SafeTxHash != common.HexToHash(safeTxHashRaw) {
+				return fmt.Errorf("the transaction returned by the service hashes to %s, not %s", components.SafeTxHash.Hex(), common.HexToHash(safeTxHashRaw).Hex())
+			}
+			if err := CheckSafeTxHashComponents(safeAddress, txData, components, client); err != nil {
+				return err
+			}
+			cmd.Println("Domain separator and SafeTxHash match the Safe")
+			return nil
+		},
+	}
+
+	hashCmd.Flags().StringVar(&safe, "safe", "", "Safe address")
+	hashCmd.Flags().StringVar(&to, "to", "", "Recipient address")
+	hashCmd.Flags().StringVar(&value, "value", "0", "Value sent with the transaction")
+	hashCmd.Flags().StringVar(&calldata, "calldata", "", "Hex-encoded ABI calldata sent with the transaction")
+	hashCmd.Flags().Uint8Var(&safeOperationType, "safe-operation", 0, "Safe operation type: 0 (Call) or 1 (DelegateCall)")
+	addSafeTxGasFlags(hashCmd, &gasFlags)
+	hashCmd.Flags().StringVar(&safeNonceRaw, "safe-nonce", "", "Safe nonce of the transaction (defaults to the Safe's current nonce)")
+	hashCmd.Flags().StringVar(&safeTxHashRaw, "safe-tx-hash", "", "Hash a proposal queued on the Safe Transaction Service instead")
+	hashCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway base URL")
+	hashCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of the Safe's chain")
+	hashCmd.MarkFlagRequired("rpc")
+
+	return hashCmd
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const DefaultSafeClientURL = "https://safe-client.safe.global"
+
+// SafeClientAddress is the address wrapper used throughout the Safe client gateway responses.
+type SafeClientAddress struct {
+	Value string `json:"value"`
+}
+
+type SafeClientConfirmation struct {
+	Signer      SafeClientAddress `json:"signer"`
+	Signature   *string           `json:"signature"`
+	SubmittedAt int64             `json:"submittedAt"`
+}
+
+type SafeClientDataDecoded struct {
+	Method string `json:"method"`
+}
+
+type SafeClientTxData struct {
+	HexData     *string                `json:"hexData"`
+	DataDecoded *SafeClientDataDecoded `json:"dataDecoded"`
+	To          SafeClientAddress      `json:"to"`
+	Value       string                 `json:"value"`
+	Operation   uint8                  `json:"operation"`
+}
+
+type SafeClientExecutionInfo struct {
+	Type                  string                   `json:"type"`
+	Nonce                 uint64                   `json:"nonce"`
+	SafeTxGas             string                   `json:"safeTxGas"`
+	BaseGas               string                   `json:"baseGas"`
+	GasPrice              string                   `json:"gasPrice"`
+	GasToken              string                   `json:"gasToken"`
+	RefundReceiver        SafeClientAddress        `json:"refundReceiver"`
+	SafeTxHash            string                   `json:"safeTxHash"`
+	Signers               []SafeClientAddress      `json:"signers"`
+	ConfirmationsRequired int                      `json:"confirmationsRequired"`
+	Confirmations         []SafeClientConfirmation `json:"confirmations"`
+}
+
+type SafeClientTransactionDetails struct {
+	SafeAddress           string                   `json:"safeAddress"`
+	TxID                  string                   `json:"txId"`
+	TxStatus              string                   `json:"txStatus"`
+	TxData                *SafeClientTxData        `json:"txData"`
+	DetailedExecutionInfo *SafeClientExecutionInfo `json:"detailedExecutionInfo"`
+}
+
+type safeClientQueuedItem struct {
+	Type        string `json:"type"`
+	Transaction *struct {
+		ID            string `json:"id"`
+		ExecutionInfo *struct {
+			Nonce uint64 `json:"nonce"`
+		} `json:"executionInfo"`
+	} `json:"transaction"`
+}
+
+// SafeClientQueuedTransaction is the summary of a queued transaction as listed by the client gateway.
+type SafeClientQueuedTransaction struct {
+	ID    string
+	Nonce uint64
+}
+
+type safeClientPage struct {
+	Count   int                    `json:"count"`
+	Next    *string                `json:"next"`
+	Results []safeClientQueuedItem `json:"results"`
+}
+
+// Returns the base URL of the Safe client gateway, falling back to DefaultSafeClientURL.
+func SafeClientBaseURL(override string) string {
+	if override == "" {
+		return DefaultSafeClientURL
+	}
+	return strings.TrimSuffix(override, "/")
+}
+
+// Recovers the client gateway base URL from a full propose endpoint, as passed to --safe-api by proposal create.
+func SafeClientBaseURLFromEndpoint(endpoint string) string {
+	if i := strings.Index(endpoint, "/v1/chains/"); i >= 0 {
+		return endpoint[:i]
+	}
+	return SafeClientBaseURL(endpoint)
+}
+
+// SafeClientStatusError is returned when the Safe client gateway answers with an unexpected HTTP status.
+type SafeClientStatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *SafeClientStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// Reports whether err is the Safe client gateway answering that the requested resource does not exist. Network
+// failures and other error statuses are not.
+func IsSafeClientNotFound(err error) bool {
+	var statusErr *SafeClientStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+func safeClientGet(endpoint string, out interface{}) error {
+	resp, err := http.Get(endpoint)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return &SafeClientStatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("error unmarshaling response: %w", err)
+	}
+
+	return nil
+}
+
+func safeClientPost(endpoint string, payload interface{}) error {
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		var jsonErr interface{}
+		if err := json.Unmarshal(body, &jsonErr); err != nil {
+			return fmt.Errorf("HTTP %d, failed to parse error body: %s", resp.StatusCode, string(body))
+		}
+		formatted, _ := json.MarshalIndent(jsonErr, "", "  ")
+		return fmt.Errorf("HTTP %d, error response:\n%s", resp.StatusCode, formatted)
+	}
+
+	return nil
+}
+
+// Returns the IDs of every transaction in the Safe's queue, following pagination to the end.
+func GetQueuedTransactionIDs(baseURL string, chainID *big.Int, safeAddress common.Address) ([]string, error) {
+	queued, err := GetQueuedTransactions(baseURL, chainID, safeAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(queued))
+	for i, tx := range queued {
+		ids[i] = tx.ID
+	}
+	return ids, nil
+}
+
+// Returns the ID and nonce of every transaction in the Safe's queue, following pagination to the end.
+func GetQueuedTransactions(baseURL string, chainID *big.Int, safeAddress common.Address) ([]SafeClientQueuedTransaction, error) {
+	var queued []SafeClientQueuedTransaction
+
+	endpoint := fmt.Sprintf("%s/v1/chains/%s/safes/%s/transactions/queued", baseURL, chainID.String(), safeAddress.Hex())
+	for endpoint != "" {
+		var page safeClientPage
+		if err := safeClientGet(endpoint, &page); err != nil {
+			return nil, fmt.Errorf("failed to fetch queued transactions: %w", err)
+		}
+
+		for _, item := range page.Results {
+			if item.Type == "TRANSACTION" && item.Transaction != nil && item.Transaction.ExecutionInfo != nil {
+				queued = append(queued, SafeClientQueuedTransaction{
+					ID:    item.Transaction.ID,
+					Nonce: item.Transaction.ExecutionInfo.Nonce,
+				})
+			}
+		}
+
+		endpoint = ""
+		if page.Next != nil {
+			endpoint = *page.Next
+		}
+	}
+
+	return queued, nil
+}
+
+// Fetches the details of a transaction by its client gateway ID or by its safeTxHash.
+func GetTransactionDetails(baseURL string, chainID *big.Int, txID string) (*SafeClientTransactionDetails, error) {
+	endpoint := fmt.Sprintf("%s/v1/chains/%s/transactions/%s", baseURL, chainID.String(), txID)
+
+	var details SafeClientTransactionDetails
+	if err := safeClientGet(endpoint, &details); err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction %s: %w", txID, err)
+	}
+
+	if details.TxData == nil || details.DetailedExecutionInfo == nil || details.DetailedExecutionInfo.Type != "MULTISIG" {
+		return nil, fmt.Errorf("transaction %s is not a multisig transaction", txID)
+	}
+
+	return &details, nil
+}
+
+// Converts the service representation of a multisig transaction into SafeTransactionData.
+func (d *SafeClientTransactionDetails) SafeTransactionData() (Safe.SafeTransactionData, error) {
+	info := d.DetailedExecutionInfo
+
+	safeTxGas, ok := new(big.Int).SetString(info.SafeTxGas, 10)
+	if !ok || !safeTxGas.IsUint64() {
+		return Safe.SafeTransactionData{}, fmt.Errorf("invalid safeTxGas: %s", info.SafeTxGas)
+	}
+	baseGas, ok := new(big.Int).SetString(info.BaseGas, 10)
+	if !ok || !baseGas.IsUint64() {
+		return Safe.SafeTransactionData{}, fmt.Errorf("invalid baseGas: %s", info.BaseGas)
+	}
+
+	data := ""
+	if d.TxData.HexData != nil {
+		data = strings.TrimPrefix(*d.TxData.HexData, "0x")
+	}
+
+	value := d.TxData.Value
+	if value == "" {
+		value = "0"
+	}
+
+	return Safe.SafeTransactionData{
+		To:             common.HexToAddress(d.TxData.To.Value).Hex(),
+		Value:          value,
+		Data:           data,
+		Operation:      Safe.SafeOperationType(d.TxData.Operation),
+		SafeTxGas:      safeTxGas.Uint64(),
+		BaseGas:        baseGas.Uint64(),
+		GasPrice:       info.GasPrice,
+		GasToken:       common.HexToAddress(info.GasToken).Hex(),
+		RefundReceiver: common.HexToAddress(info.RefundReceiver.Value).Hex(),
+		Nonce:          new(big.Int).SetUint64(info.Nonce),
+		SafeTxHash:     info.SafeTxHash,
+	}, nil
+}
+
+// Submits an owner's signature over safeTxHash as a confirmation of the corresponding transaction.
+func ConfirmTransaction(baseURL string, chainID *big.Int, safeTxHash common.Hash, signature string) error {
+	endpoint := fmt.Sprintf("%s/v1/chains/%s/transactions/%s/confirmations", baseURL, chainID.String(), safeTxHash.Hex())
+	return safeClientPost(endpoint, map[string]string{"signedSafeTxHash": signature})
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+
+	"github.com/G7DAO/safes/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// Environment variable overriding the location of the counterfactual Safe store.
+const CounterfactualSafesEnvVar = "SAFES_COUNTERFACTUAL"
+
+// CounterfactualSafe is a Safe whose address has been predicted but which may not be deployed yet, with every
+// parameter needed to deploy it at that address.
+type CounterfactualSafe struct {
+	Address         common.Address   `json:"address"`
+	ChainID         string           `json:"chainId"`
+	Label           string           `json:"label,omitempty"`
+	Factory         common.Address   `json:"factory"`
+	Singleton       common.Address   `json:"singleton"`
+	SaltNonce       string           `json:"saltNonce"`
+	Callback        common.Address   `json:"callback"`
+	ChainSpecific   bool             `json:"chainSpecific"`
+	Owners          []common.Address `json:"owners"`
+	Threshold       uint64           `json:"threshold"`
+	To              common.Address   `json:"to"`
+	Data            hexutil.Bytes    `json:"data"`
+	FallbackHandler common.Address   `json:"fallbackHandler"`
+	PaymentToken    common.Address   `json:"paymentToken"`
+	Payment         string           `json:"payment"`
+	PaymentReceiver common.Address   `json:"paymentReceiver"`
+}
+
+func NewCounterfactualSafe(creation SafeCreation, address common.Address, chainID *big.Int, label string) CounterfactualSafe {
+	payment := creation.Setup.Payment
+	if payment == nil {
+		payment = big.NewInt(0)
+	}
+	return CounterfactualSafe{
+		Address:         address,
+		ChainID:         chainID.String(),
+		Label:           label,
+		Factory:         creation.Factory,
+		Singleton:       creation.Singleton,
+		SaltNonce:       creation.SaltNonce.String(),
+		Callback:        creation.Callback,
+		ChainSpecific:   creation.ChainSpecific,
+		Owners:          creation.Setup.Owners,
+		Threshold:       creation.Setup.Threshold,
+		To:              creation.Setup.To,
+		Data:            creation.Setup.Data,
+		FallbackHandler: creation.Setup.FallbackHandler,
+		PaymentToken:    creation.Setup.PaymentToken,
+		Payment:         payment.String(),
+		PaymentReceiver: creation.Setup.PaymentReceiver,
+	}
+}
+
+func (safe CounterfactualSafe) Creation() (SafeCreation, error) {
+	saltNonce, ok := new(big.Int).SetString(safe.SaltNonce, 10)
+	if !ok {
+		return SafeCreation{}, fmt.Errorf("invalid salt nonce for %s: %s", safe.Address.Hex(), safe.SaltNonce)
+	}
+	payment, ok := new(big.Int).SetString(safe.Payment, 10)
+	if !ok {
+		return SafeCreation{}, fmt.Errorf("invalid payment for %s: %s", safe.Address.Hex(), safe.Payment)
+	}
+	return SafeCreation{
+		Factory:       safe.Factory,
+		Singleton:     safe.Singleton,
+		SaltNonce:     saltNonce,
+		Callback:      safe.Callback,
+		ChainSpecific: safe.ChainSpecific,
+		Setup: SafeSetupParams{
+			Owners:          safe.Owners,
+			Threshold:       safe.Threshold,
+			To:              safe.To,
+			Data:            safe.Data,
+			FallbackHandler: safe.FallbackHandler,
+			PaymentToken:    safe.PaymentToken,
+			Payment:         payment,
+			PaymentReceiver: safe.PaymentReceiver,
+		},
+	}, nil
+}
+
+// Returns the path of the counterfactual Safe store: $SAFES_COUNTERFACTUAL if set, otherwise counterfactual.json in
+// the user's config directory.
+func CounterfactualSafesPath() (string, error) {
+	if path := os.Getenv(CounterfactualSafesEnvVar); path != "" {
+		return path, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory, please set %s: %w", CounterfactualSafesEnvVar, err)
+	}
+	return filepath.Join(configDir, "safes", "counterfactual.json"), nil
+}
+
+func LoadCounterfactualSafes() ([]CounterfactualSafe, error) {
+	path, err := CounterfactualSafesPath()
+	if err != nil {
+		return nil, err
+	}
+
+	var safes []CounterfactualSafe
+	if err := readJSONFile(path, &safes); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return safes, nil
+}
+
+func SaveCounterfactualSafes(safes []CounterfactualSafe) (string, error) {
+	path, err := CounterfactualSafesPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if safes == nil {
+		safes = []CounterfactualSafe{}
+	}
+	return path, WriteJSONFile(path, safes)
+}
+
+// Adds a counterfactual Safe to the store, replacing any entry for the same address and chain.
+func AddCounterfactualSafe(safe CounterfactualSafe) (string, error) {
+	safes, err := LoadCounterfactualSafes()
+	if err != nil {
+		return "", err
+	}
+
+	replaced := false
+	for i, existing := range safes {
+		if existing.Address == safe.Address && existing.ChainID == safe.ChainID {
+			safes[i] = safe
+			replaced = true
+		}
+	}
+	if !replaced {
+		safes = append(safes, safe)
+	}
+	return SaveCounterfactualSafes(safes)
+}
+
+// Removes the counterfactual Safes with the given address, on the given chain or on every chain if chainID is "".
+// It returns the number of entries removed.
+func RemoveCounterfactualSafe(address common.Address, chainID string) (int, string, error) {
+	safes, err := LoadCounterfactualSafes()
+	if err != nil {
+		return 0, "", err
+	}
+
+	var kept []CounterfactualSafe
+	for _, existing := range safes {
+		if existing.Address == address && (chainID == "" || existing.ChainID == chainID) {
+			continue
+		}
+		kept = append(kept, existing)
+	}
+
+	path, err := SaveCounterfactualSafes(kept)
+	return len(safes) - len(kept), path, err
+}
+
+// Finds the counterfactual Safe with the given address, preferring the entry recorded for the given chain. Entries
+// that are not chain-specific can be deployed at the same address on any chain with the same factory.
+func FindCounterfactualSafe(address common.Address, chainID string) (*CounterfactualSafe, error) {
+	safes, err := LoadCounterfactualSafes()
+	if err != nil {
+		return nil, err
+	}
+
+	var match *CounterfactualSafe
+	for i, existing := range safes {
+		if existing.Address != address {
+			continue
+		}
+		if existing.ChainID == chainID {
+			return &safes[i], nil
+		}
+		if match == nil && !existing.ChainSpecific {
+			match = &safes[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no counterfactual Safe %s recorded for chain %s", address.Hex(), chainID)
+	}
+	return match, nil
+}
+
+func createSafeCounterfactualCmd() *cobra.Command {
+	counterfactualCmd := &cobra.Command{
+		Use:   "counterfactual",
+		Short: "Manage predicted Safes that have not been deployed yet",
+		Long: fmt.Sprintf(`Manage counterfactual Safes: Safes whose address was predicted with "safe predict --save" and which can be
+deployed later at that address. They are stored in counterfactual.json in the user's config directory, or at the path
+given by $%s.`, CounterfactualSafesEnvVar),
+	}
+
+	counterfactualCmd.AddCommand(createListCounterfactualSafesCmd())
+	counterfactualCmd.AddCommand(createDeployCounterfactualSafeCmd())
+	counterfactualCmd.AddCommand(createRemoveCounterfactualSafeCmd())
+
+	return counterfactualCmd
+}
+
+func createListCounterfactualSafesCmd() *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List counterfactual Safes, and whether they are deployed if --rpc is given",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			safes, err := LoadCounterfactualSafes()
+			if err != nil {
+				return err
+			}
+			if len(safes) == 0 {
+				cmd.Println("No counterfactual Safes recorded")
+				return nil
+			}
+
+			var client *ethclient.Client
+			var chainID string
+			if rpcURL != "" {
+				client, err = ethclient.Dial(rpcURL)
+				if err != nil {
+					return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+				}
+				id, err := client.ChainID(context.Background())
+				if err != nil {
+					return fmt.Errorf("failed to get chain ID: %v", err)
+				}
+				chainID = id.String()
+			}
+
+			for _, safe := range safes {
+				status := ""
+				if client != nil && (safe.ChainID == chainID || !safe.ChainSpecific) {
+					deployed, err := hasCode(safe.Address, client)
+					if err != nil {
+						return err
+					}
+					status = " (not deployed)"
+					if deployed {
+						status = " (deployed)"
+					}
+				}
+				label := ""
+				if safe.Label != "" {
+					label = " " + safe.Label
+				}
+				cmd.Printf("%s chain %s%s: threshold %d of %d owners, salt nonce %s%s\n", safe.Address.Hex(), safe.ChainID, label, safe.Threshold, len(safe.Owners), safe.SaltNonce, status)
+			}
+			return nil
+		},
+	}
+
+	listCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of a chain on which to check whether the Safes are deployed")
+
+	return listCmd
+}
+
+func createDeployCounterfactualSafeCmd() *cobra.Command {
+	var (
+		address  string
+		keyfile  string
+		password string
+		yes      bool
+	)
+
+	deployCmd := &cobra.Command{
+		Use:   "deploy",
+		Short: "Deploy a counterfactual Safe at its predicted address",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !common.IsHexAddress(address) {
+				return fmt.Errorf("invalid address: %s", address)
+			}
+			if keyfile == "" {
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			safeAddress := common.HexToAddress(address)
+			counterfactual, err := FindCounterfactualSafe(safeAddress, chainID.String())
+			if err != nil {
+				return err
+			}
+			creation, err := counterfactual.Creation()
+			if err != nil {
+				return err
+			}
+
+			if err := CheckSafeCreationContracts(creation, client); err != nil {
+				return err
+			}
+			predicted, err := PredictSafeAddressOnChain(creation, client)
+			if err != nil {
+				return err
+			}
+			if predicted != safeAddress {
+				return fmt.Errorf("the recorded parameters give %s on chain %s, not %s", predicted.Hex(), chainID.String(), safeAddress.Hex())
+			}
+			deployed, err := hasCode(safeAddress, client)
+			if err != nil {
+				return err
+			}
+			if deployed {
+				return fmt.Errorf("%s is already deployed on chain %s", safeAddress.Hex(), chainID.String())
+			}
+
+			PrintSafeCreation(creation)
+			cmd.Println("Safe address:", safeAddress.Hex())
+			if !yes {
+				ok, err := ConfirmPrompt("Deploy this Safe?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("creation not confirmed")
+				}
+			}
+
+			key, err := signer.Open(keyfile, password)
+			if err != nil {
+				return err
+			}
+
+			created, err := CreateSafe(creation, key, chainID, client)
+			if err != nil {
+				return err
+			}
+			if created != safeAddress {
+				return fmt.Errorf("Safe was deployed at %s, not at the predicted %s", created.Hex(), safeAddress.Hex())
+			}
+
+			if counterfactual.ChainID == chainID.String() {
+				if _, _, err := RemoveCounterfactualSafe(safeAddress, chainID.String()); err != nil {
+					return fmt.Errorf("Safe deployed but failed to update the counterfactual Safe store: %v", err)
+				}
+			}
+			return nil
+		},
+	}
+
+	deployCmd.Flags().StringVar(&address, "address", "", "Predicted address of the counterfactual Safe")
+	deployCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer of the account submitting the creation: "+signer.SpecUsage)
+	deployCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	deployCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of the chain to deploy on")
+	deployCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Deploy without asking for confirmation")
+	deployCmd.MarkFlagRequired("address")
+	deployCmd.MarkFlagRequired("keyfile")
+	deployCmd.MarkFlagRequired("rpc")
+
+	return deployCmd
+}
+
+func createRemoveCounterfactualSafeCmd() *cobra.Command {
+	var address, chainID string
+
+	removeCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Forget a counterfactual Safe",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !common.IsHexAddress(address) {
+				return fmt.Errorf("invalid address: %s", address)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			removed, path, err := RemoveCounterfactualSafe(common.HexToAddress(address), chainID)
+			if err != nil {
+				return err
+			}
+			if removed == 0 {
+				return fmt.Errorf("no counterfactual Safe %s recorded", address)
+			}
+			cmd.Printf("Removed %d entries from %s\n", removed, path)
+			return nil
+		},
+	}
+
+	removeCmd.Flags().StringVar(&address, "address", "", "Address of the counterfactual Safe")
+	removeCmd.Flags().StringVar(&chainID, "chain-id", "", "Only remove the entry for this chain")
+	removeCmd.MarkFlagRequired("address")
+
+	return removeCmd
+}
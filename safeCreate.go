@@ -0,0 +1,500 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/G7DAO/safes/bindings/SafeProxyFactory"
+	"github.com/G7DAO/safes/signer"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// Canonical Safe 1.4.1 deployments, used by default when creating Safes.
+var (
+	DefaultSafeSingletonAddress    = common.HexToAddress("0x29fcb43b46531bca003ddc8fcb67ffe91900c762")
+	DefaultSafeL2SingletonAddress  = common.HexToAddress("0x41675C099F32341bf84BFc5382aF534df5C7461a")
+	DefaultFallbackHandlerAddress  = common.HexToAddress("0xfd0732Dc9E303f09fCEf3a7388Ad10A83459Ec99")
+	DefaultSafeProxyFactoryAddress = common.HexToAddress("0x4e1DCf7AD4e460CfD30791CCC4F9c8a4f820ec67")
+)
+
+// Topic of ProxyCreation(address proxy, address singleton), emitted by the factory for every new Safe. Factories
+// from 1.4.0 index the proxy, while the 1.3.0 factory logs both addresses in the data; the topic is the same.
+var ProxyCreationTopic = common.HexToHash("0x4f51faf6c4561ff95f067657e43439f0f856d97c04d9ec9070a6199ad418e235")
+
+// SafeSetupParams are the arguments of the Safe's setup call, which initializes a new proxy.
+type SafeSetupParams struct {
+	Owners          []common.Address
+	Threshold       uint64
+	To              common.Address
+	Data            []byte
+	FallbackHandler common.Address
+	PaymentToken    common.Address
+	Payment         *big.Int
+	PaymentReceiver common.Address
+}
+
+func (params SafeSetupParams) Validate() error {
+	if len(params.Owners) == 0 {
+		return fmt.Errorf("at least one owner is required")
+	}
+	seen := make(map[common.Address]bool)
+	for _, owner := range params.Owners {
+		if owner == (common.Address{}) || owner == SafeSentinelAddress {
+			return fmt.Errorf("%s cannot be an owner", owner.Hex())
+		}
+		if seen[owner] {
+			return fmt.Errorf("duplicate owner %s", owner.Hex())
+		}
+		seen[owner] = true
+	}
+	if params.Threshold < 1 || params.Threshold > uint64(len(params.Owners)) {
+		return fmt.Errorf("threshold %d is invalid, it must be between 1 and the number of owners (%d)", params.Threshold, len(params.Owners))
+	}
+	return nil
+}
+
+// Encodes the setup call used as the proxy's initializer.
+func (params SafeSetupParams) Initializer() ([]byte, error) {
+	payment := params.Payment
+	if payment == nil {
+		payment = big.NewInt(0)
+	}
+	data := params.Data
+	if data == nil {
+		data = []byte{}
+	}
+	return packSafeCall("setup", params.Owners, new(big.Int).SetUint64(params.Threshold), params.To, data, params.FallbackHandler, params.PaymentToken, payment, params.PaymentReceiver)
+}
+
+// SafeCreation describes a Safe to be deployed by a SafeProxyFactory. If Callback is set the Safe is created with
+// createProxyWithCallback, if ChainSpecific is set with createChainSpecificProxyWithNonce, whose address also depends
+// on the chain ID, and otherwise with createProxyWithNonce.
+type SafeCreation struct {
+	Factory       common.Address
+	Singleton     common.Address
+	Setup         SafeSetupParams
+	SaltNonce     *big.Int
+	Callback      common.Address
+	ChainSpecific bool
+}
+
+// Checks that the factory, singleton and fallback handler of a creation are deployed on the client's chain.
+func CheckSafeCreationContracts(creation SafeCreation, client *ethclient.Client) error {
+	contracts := []struct {
+		name    string
+		address common.Address
+	}{
+		{"factory", creation.Factory},
+		{"singleton", creation.Singleton},
+		{"fallback handler", creation.Setup.FallbackHandler},
+	}
+	for _, contract := range contracts {
+		if contract.address == (common.Address{}) {
+			continue
+		}
+		code, err := hasCode(contract.address, client)
+		if err != nil {
+			return err
+		}
+		if !code {
+			return fmt.Errorf("%s %s has no code on this chain", contract.name, contract.address.Hex())
+		}
+	}
+	return nil
+}
+
+func PrintSafeCreation(creation SafeCreation) {
+	fmt.Println("Factory:", creation.Factory.Hex())
+	fmt.Println("Singleton:", creation.Singleton.Hex())
+	fmt.Println("Salt nonce:", creation.SaltNonce.String())
+	if creation.Callback != (common.Address{}) {
+		fmt.Println("Callback:", creation.Callback.Hex())
+	}
+	if creation.ChainSpecific {
+		fmt.Println("Chain-specific address: yes")
+	}
+	fmt.Printf("Threshold %d of %d owners\n", creation.Setup.Threshold, len(creation.Setup.Owners))
+	for _, owner := range creation.Setup.Owners {
+		fmt.Println("  " + owner.Hex())
+	}
+	fmt.Println("Fallback handler:", creation.Setup.FallbackHandler.Hex())
+	if creation.Setup.Payment != nil && creation.Setup.Payment.Sign() > 0 {
+		fmt.Printf("Setup payment: %s of %s to %s\n", creation.Setup.Payment.String(), creation.Setup.PaymentToken.Hex(), creation.Setup.PaymentReceiver.Hex())
+	}
+}
+
+// Deploys a Safe through its factory and returns the address of the new proxy, read from the ProxyCreation event.
+// The owners, threshold and fallback handler of the new Safe are read back and checked against the setup.
+func CreateSafe(creation SafeCreation, key signer.Signer, chainID *big.Int, client *ethclient.Client) (common.Address, error) {
+	initializer, err := creation.Setup.Initializer()
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	factory, err := SafeProxyFactory.NewSafeProxyFactory(creation.Factory, client)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to create SafeProxyFactory instance: %w", err)
+	}
+
+	transactOpts, err := signer.NewTransactor(key, chainID)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to create transactor: %w", err)
+	}
+
+	var tx *types.Transaction
+	switch {
+	case creation.Callback != (common.Address{}):
+		tx, err = factory.CreateProxyWithCallback(transactOpts, creation.Singleton, initializer, creation.SaltNonce, creation.Callback)
+	case creation.ChainSpecific:
+		tx, err = factory.CreateChainSpecificProxyWithNonce(transactOpts, creation.Singleton, initializer, creation.SaltNonce)
+	default:
+		tx, err = factory.CreateProxyWithNonce(transactOpts, creation.Singleton, initializer, creation.SaltNonce)
+	}
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to submit Safe creation: %w", err)
+	}
+	fmt.Println("Transaction submitted:", tx.Hash().Hex())
+
+	receipt, err := bind.WaitMined(context.Background(), client, tx)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed waiting for transaction receipt: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return common.Address{}, fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+
+	safeAddress, err := SafeAddressFromReceipt(receipt, creation.Factory)
+	if err != nil {
+		return common.Address{}, err
+	}
+	fmt.Println("Safe deployed at:", safeAddress.Hex())
+
+	if err := VerifySafeSetup(safeAddress, creation.Setup, client); err != nil {
+		return safeAddress, err
+	}
+	return safeAddress, nil
+}
+
+// Returns the address of the proxy created in a transaction, from the ProxyCreation event emitted by the factory.
+func SafeAddressFromReceipt(receipt *types.Receipt, factoryAddress common.Address) (common.Address, error) {
+	for _, log := range receipt.Logs {
+		if log.Address != factoryAddress || len(log.Topics) == 0 || log.Topics[0] != ProxyCreationTopic {
+			continue
+		}
+		return ProxyCreationAddress(log)
+	}
+	return common.Address{}, fmt.Errorf("ProxyCreation event not found in transaction %s", receipt.TxHash.Hex())
+}
+
+// Returns the proxy address of a ProxyCreation event, in either the indexed layout of factories from 1.4.0 or the
+// unindexed layout of the 1.3.0 factory.
+func ProxyCreationAddress(log *types.Log) (common.Address, error) {
+	if len(log.Topics) == 0 || log.Topics[0] != ProxyCreationTopic {
+		return common.Address{}, fmt.Errorf("log is not a ProxyCreation event")
+	}
+
+	var word []byte
+	switch {
+	case len(log.Topics) == 2 && len(log.Data) == 32:
+		word = log.Topics[1].Bytes()
+	case len(log.Topics) == 1 && len(log.Data) == 64:
+		word = log.Data[:32]
+	default:
+		return common.Address{}, fmt.Errorf("ProxyCreation event with %d topics and %d bytes of data has an unknown layout", len(log.Topics), len(log.Data))
+	}
+	if !bytes.Equal(word[:12], make([]byte, 12)) {
+		return common.Address{}, fmt.Errorf("ProxyCreation event has an invalid proxy address 0x%x", word)
+	}
+	return common.BytesToAddress(word), nil
+}
+
+// Checks that a deployed Safe has the owners, threshold and fallback handler of the given setup.
+func VerifySafeSetup(safeAddress common.Address, setup SafeSetupParams, client *ethclient.Client) error {
+	state, err := GetSafeOwnerState(safeAddress, client)
+	if err != nil {
+		return err
+	}
+
+	if len(state.Owners) != len(setup.Owners) {
+		return fmt.Errorf("Safe %s has %d owners, expected %d", safeAddress.Hex(), len(state.Owners), len(setup.Owners))
+	}
+	for i, owner := range setup.Owners {
+		if state.Owners[i] != owner {
+			return fmt.Errorf("Safe %s has owner %s at position %d, expected %s", safeAddress.Hex(), state.Owners[i].Hex(), i, owner.Hex())
+		}
+	}
+	if state.Threshold != setup.Threshold {
+		return fmt.Errorf("Safe %s has threshold %d, expected %d", safeAddress.Hex(), state.Threshold, setup.Threshold)
+	}
+
+	handler, err := readSafeAddressSlot(safeAddress, SafeFallbackHandlerStorageSlot, client)
+	if err != nil {
+		return err
+	}
+	if handler != setup.FallbackHandler {
+		return fmt.Errorf("Safe %s has fallback handler %s, expected %s", safeAddress.Hex(), handler.Hex(), setup.FallbackHandler.Hex())
+	}
+
+	fmt.Printf("Verified Safe %s: threshold %d of %d owners\n", safeAddress.Hex(), state.Threshold, len(state.Owners))
+	return nil
+}
+
+// safeCreationFlags are the flags describing a new Safe, shared by the commands that create or predict Safes.
+type safeCreationFlags struct {
+	owners          []string
+	threshold       uint64
+	saltNonce       string
+	singleton       string
+	l2              bool
+	fallbackHandler string
+	factory         string
+	callback        string
+	paymentToken    string
+	payment         string
+	paymentReceiver string
+	chainSpecific   bool
+}
+
+func addSafeCreationFlags(cmd *cobra.Command, flags *safeCreationFlags) {
+	cmd.Flags().StringSliceVar(&flags.owners, "owners", nil, "Comma-separated owner addresses")
+	cmd.Flags().Uint64Var(&flags.threshold, "threshold", 1, "Number of owner signatures required to execute a transaction")
+	cmd.Flags().StringVar(&flags.saltNonce, "salt-nonce", "0", "Salt nonce passed to the factory; the same setup and salt nonce give the same address")
+	cmd.Flags().StringVar(&flags.singleton, "singleton", "", fmt.Sprintf("Safe singleton (defaults to %s, or %s with --l2)", DefaultSafeSingletonAddress.Hex(), DefaultSafeL2SingletonAddress.Hex()))
+	cmd.Flags().BoolVar(&flags.l2, "l2", false, "Use the SafeL2 singleton, which emits events for every transaction")
+	cmd.Flags().StringVar(&flags.fallbackHandler, "fallback-handler", DefaultFallbackHandlerAddress.Hex(), "Fallback handler, or 0x0000000000000000000000000000000000000000 for none")
+	cmd.Flags().StringVar(&flags.factory, "factory", DefaultSafeProxyFactoryAddress.Hex(), "SafeProxyFactory address")
+	cmd.Flags().StringVar(&flags.callback, "callback", "", "Create the Safe with createProxyWithCallback, notifying this IProxyCreationCallback")
+	cmd.Flags().StringVar(&flags.paymentToken, "payment-token", "", "Token for the setup payment (defaults to the native currency)")
+	cmd.Flags().StringVar(&flags.payment, "payment", "", "Setup payment in whole units of the payment token (for example 0.01); the Safe pays it from its own balance, so its address must be funded before creation")
+	cmd.Flags().StringVar(&flags.paymentReceiver, "payment-receiver", "", "Receiver of the setup payment (defaults to the account submitting the creation)")
+	cmd.Flags().BoolVar(&flags.chainSpecific, "chain-specific", false, "Create the Safe with createChainSpecificProxyWithNonce, so that its address also depends on the chain ID")
+	cmd.MarkFlagRequired("owners")
+}
+
+// Checks the flags that can be validated without a chain connection.
+func (flags *safeCreationFlags) Validate() error {
+	for _, owner := range flags.owners {
+		if !common.IsHexAddress(owner) {
+			return fmt.Errorf("invalid owner address: %s", owner)
+		}
+	}
+	if _, ok := new(big.Int).SetString(flags.saltNonce, 0); !ok {
+		return fmt.Errorf("--salt-nonce is not a valid big integer")
+	}
+	if flags.singleton != "" && flags.l2 {
+		return fmt.Errorf("--singleton and --l2 cannot be used together")
+	}
+	addresses := []struct {
+		name  string
+		value string
+	}{
+		{"singleton", flags.singleton},
+		{"fallback-handler", flags.fallbackHandler},
+		{"factory", flags.factory},
+		{"callback", flags.callback},
+		{"payment-token", flags.paymentToken},
+		{"payment-receiver", flags.paymentReceiver},
+	}
+	for _, address := range addresses {
+		if address.value != "" && !common.IsHexAddress(address.value) {
+			return fmt.Errorf("invalid --%s address: %s", address.name, address.value)
+		}
+	}
+	if flags.chainSpecific && flags.callback != "" {
+		return fmt.Errorf("--chain-specific and --callback cannot be used together")
+	}
+	if flags.payment == "" && (flags.paymentToken != "" || flags.paymentReceiver != "") {
+		return fmt.Errorf("--payment-token and --payment-receiver require --payment")
+	}
+	return nil
+}
+
+// Builds the SafeCreation described by the flags. The client is used to look up the payment token's decimals.
+func (flags *safeCreationFlags) Creation(client *ethclient.Client) (SafeCreation, error) {
+	saltNonce, _ := new(big.Int).SetString(flags.saltNonce, 0)
+	creation := SafeCreation{
+		Factory:       common.HexToAddress(flags.factory),
+		Singleton:     DefaultSafeSingletonAddress,
+		SaltNonce:     saltNonce,
+		ChainSpecific: flags.chainSpecific,
+		Setup: SafeSetupParams{
+			Threshold:       flags.threshold,
+			FallbackHandler: common.HexToAddress(flags.fallbackHandler),
+			Payment:         big.NewInt(0),
+		},
+	}
+	if flags.l2 {
+		creation.Singleton = DefaultSafeL2SingletonAddress
+	}
+	if flags.singleton != "" {
+		creation.Singleton = common.HexToAddress(flags.singleton)
+	}
+	if flags.callback != "" {
+		creation.Callback = common.HexToAddress(flags.callback)
+	}
+	for _, owner := range flags.owners {
+		creation.Setup.Owners = append(creation.Setup.Owners, common.HexToAddress(owner))
+	}
+
+	if flags.payment != "" {
+		decimals := uint8(18)
+		if flags.paymentToken != "" {
+			creation.Setup.PaymentToken = common.HexToAddress(flags.paymentToken)
+			var err error
+			decimals, err = TokenDecimals(creation.Setup.PaymentToken, client)
+			if err != nil {
+				return SafeCreation{}, err
+			}
+		}
+		payment, err := ParseTokenAmount(flags.payment, decimals)
+		if err != nil {
+			return SafeCreation{}, fmt.Errorf("invalid --payment: %w", err)
+		}
+		creation.Setup.Payment = payment
+		if flags.paymentReceiver != "" {
+			creation.Setup.PaymentReceiver = common.HexToAddress(flags.paymentReceiver)
+		}
+	}
+
+	return creation, creation.Setup.Validate()
+}
+
+func createSafeCreateCmd() *cobra.Command {
+	var (
+		creationFlags safeCreationFlags
+		keyfile       string
+		password      string
+		yes           bool
+		chains        []string
+	)
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Deploy a new Safe through a SafeProxyFactory",
+		Long: `Deploy a new Safe proxy with createProxyWithNonce (or createProxyWithCallback with --callback), initialized
+with the given owners and threshold. The new Safe's address is read from the factory's ProxyCreation event, and its
+owners, threshold and fallback handler are read back to confirm the setup.
+
+With --chains, the same Safe is created on several chains. The factory and singleton must have the same bytecode on
+every chain, and the Safe is only deployed on chains where it gets the same address and is not deployed yet. Chains
+where the address would differ are reported instead. With --chain-specific the address depends on the chain ID, so
+it is expected to differ. Once deployed, the owners and threshold are checked on every chain.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if keyfile == "" {
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
+			}
+			if (rpcURL == "") == (len(chains) == 0) {
+				return fmt.Errorf("exactly one of --rpc and --chains must be specified")
+			}
+			return creationFlags.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(chains) > 0 {
+				deployments := PlanMultiChainSafeCreation(chains, &creationFlags)
+				for _, deployment := range deployments {
+					if deployment.Problem == "" {
+						PrintSafeCreation(deployment.Creation)
+						break
+					}
+				}
+				PrintMultiChainSafeCreation(deployments)
+
+				pending := false
+				for _, deployment := range deployments {
+					pending = pending || (deployment.Problem == "" && !deployment.Deployed)
+				}
+				if pending {
+					if !yes {
+						ok, err := ConfirmPrompt("Deploy this Safe on the chains above?")
+						if err != nil {
+							return err
+						}
+						if !ok {
+							return fmt.Errorf("creation not confirmed")
+						}
+					}
+
+					key, err := signer.Open(keyfile, password)
+					if err != nil {
+						return err
+					}
+					ExecuteMultiChainSafeCreation(deployments, key)
+					PrintMultiChainSafeCreation(deployments)
+				}
+				return MultiChainSafeCreationError(deployments)
+			}
+
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			creation, err := creationFlags.Creation(client)
+			if err != nil {
+				return err
+			}
+			if err := CheckSafeCreationContracts(creation, client); err != nil {
+				return err
+			}
+
+			predicted, err := PredictSafeAddressOnChain(creation, client)
+			if err != nil {
+				return err
+			}
+			deployed, err := hasCode(predicted, client)
+			if err != nil {
+				return err
+			}
+			if deployed {
+				return fmt.Errorf("a Safe with these parameters is already deployed at %s, use another --salt-nonce", predicted.Hex())
+			}
+
+			PrintSafeCreation(creation)
+			cmd.Println("Predicted Safe address:", predicted.Hex())
+			if !yes {
+				ok, err := ConfirmPrompt("Deploy this Safe?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("creation not confirmed")
+				}
+			}
+
+			key, err := signer.Open(keyfile, password)
+			if err != nil {
+				return err
+			}
+
+			safeAddress, err := CreateSafe(creation, key, chainID, client)
+			if err != nil {
+				return err
+			}
+			if safeAddress != predicted {
+				return fmt.Errorf("Safe was deployed at %s, not at the predicted %s", safeAddress.Hex(), predicted.Hex())
+			}
+			return nil
+		},
+	}
+
+	addSafeCreationFlags(createCmd, &creationFlags)
+	createCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer of the account submitting the creation: "+signer.SpecUsage)
+	createCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	createCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of the chain to deploy on")
+	createCmd.Flags().StringSliceVar(&chains, "chains", nil, "Comma-separated chains to deploy the same Safe on, as RPC URLs or known chain names (g7, g7-testnet)")
+	createCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Deploy without asking for confirmation")
+	createCmd.MarkFlagRequired("keyfile")
+
+	return createCmd
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/G7DAO/safes/bindings/SafeProxyFactory"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestProxyCreationAddress(t *testing.T) {
+	proxy := common.HexToAddress("0x5aFE3855358E112B5647B952709E6165e1c1eEEe")
+	singleton := common.HexToAddress("0xd9Db270c1B5E3Bd161E8c8503c55cEABeE709552")
+	proxyWord := common.LeftPadBytes(proxy.Bytes(), 32)
+	singletonWord := common.LeftPadBytes(singleton.Bytes(), 32)
+
+	valid := map[string]*types.Log{
+		"1.4.1 indexed proxy": {Topics: []common.Hash{ProxyCreationTopic, common.BytesToHash(proxyWord)}, Data: singletonWord},
+		"1.3.0 proxy in data": {Topics: []common.Hash{ProxyCreationTopic}, Data: append(append([]byte{}, proxyWord...), singletonWord...)},
+	}
+	for name, log := range valid {
+		got, err := ProxyCreationAddress(log)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+		} else if got != proxy {
+			t.Errorf("%s: got %s, want %s", name, got.Hex(), proxy.Hex())
+		}
+	}
+
+	dirty := append([]byte{}, proxyWord...)
+	dirty[0] = 1
+	invalid := map[string]*types.Log{
+		"other event":   {Topics: []common.Hash{common.HexToHash("0x01"), common.BytesToHash(proxyWord)}, Data: singletonWord},
+		"no topics":     {Data: singletonWord},
+		"short data":    {Topics: []common.Hash{ProxyCreationTopic}, Data: proxyWord},
+		"extra topic":   {Topics: []common.Hash{ProxyCreationTopic, common.BytesToHash(proxyWord), common.BytesToHash(singletonWord)}},
+		"dirty address": {Topics: []common.Hash{ProxyCreationTopic, common.BytesToHash(dirty)}, Data: singletonWord},
+	}
+	for name, log := range invalid {
+		if got, err := ProxyCreationAddress(log); err == nil {
+			t.Errorf("%s: accepted as %s", name, got.Hex())
+		}
+	}
+}
+
+func TestSafeAddressFromReceipt(t *testing.T) {
+	chain := newTestChain(t)
+	factoryAddress, singleton := chain.deploySafeFactory(t)
+	_, owners := testKeys(t, 1)
+
+	initializer, err := SafeSetupParams{Owners: owners, Threshold: 1}.Initializer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory, err := SafeProxyFactory.NewSafeProxyFactory(factoryAddress, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saltNonce := big.NewInt(42)
+	tx, err := factory.CreateProxyWithNonce(chain.deployer, singleton, initializer, saltNonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.backend.Commit()
+	receipt, err := chain.client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	proxyCreationCode, err := factory.ProxyCreationCode(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := PredictSafeAddress(SafeCreation{Factory: factoryAddress, Singleton: singleton, Setup: SafeSetupParams{Owners: owners, Threshold: 1}, SaltNonce: saltNonce}, proxyCreationCode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := SafeAddressFromReceipt(receipt, factoryAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("SafeAddressFromReceipt = %s, want %s", got.Hex(), want.Hex())
+	}
+
+	if _, err := SafeAddressFromReceipt(receipt, singleton); err == nil {
+		t.Error("found a ProxyCreation event emitted by another contract")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/safetx"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Parses a --safe-tx-hash flag: 32 bytes of hex, with or without a 0x prefix.
+func parseSafeTxHash(raw string) (common.Hash, error) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(raw, "0x"), "0X")
+	decoded, err := hex.DecodeString(digits)
+	if err != nil || len(decoded) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid safe-tx-hash %q, expected 32 bytes of hex", raw)
+	}
+	return common.BytesToHash(decoded), nil
+}
+
+// SafeTxHashComponents are the intermediate values of the EIP-712 hash of a SafeTx, as shown by hardware wallets
+// and other signing tools.
+type SafeTxHashComponents struct {
+	DomainSeparator common.Hash
+	StructHash      common.Hash
+	SafeTxHash      common.Hash
+	TypedData       apitypes.TypedData
+}
+
+// Converts the binding representation of a SafeTx into the one hashed by the safetx package.
+func SafeTxFromData(txData Safe.SafeTransactionData) safetx.SafeTx {
+	return safetx.SafeTx{
+		To:             txData.To,
+		Value:          txData.Value,
+		Data:           txData.Data,
+		Operation:      uint8(txData.Operation),
+		SafeTxGas:      txData.SafeTxGas,
+		BaseGas:        txData.BaseGas,
+		GasPrice:       txData.GasPrice,
+		GasToken:       txData.GasToken,
+		RefundReceiver: txData.RefundReceiver,
+		Nonce:          txData.Nonce,
+	}
+}
+
+func ComputeSafeTxHashComponents(safeAddress common.Address, txData Safe.SafeTransactionData, chainID *big.Int, safeVersion string) (*SafeTxHashComponents, error) {
+	typedData, err := safetx.TypedData(safeAddress, SafeTxFromData(txData), chainID, safeVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash EIP-712 domain: %w", err)
+	}
+	structHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash SafeTx: %w", err)
+	}
+
+	return &SafeTxHashComponents{
+		DomainSeparator: common.BytesToHash(domainSeparator),
+		StructHash:      common.BytesToHash(structHash),
+		SafeTxHash:      crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator, structHash),
+		TypedData:       typedData,
+	}, nil
+}
+
+// Checks locally computed hash components against the Safe's domainSeparator and getTransactionHash, returning an
+// error describing every mismatch.
+func CheckSafeTxHashComponents(safeAddress common.Address, txData Safe.SafeTransactionData, components *SafeTxHashComponents, client *ethclient.Client) error {
+	safeInstance, err := Safe.NewSafe(safeAddress, client)
+	if err != nil {
+		return fmt.Errorf("failed to create Safe instance: %w", err)
+	}
+
+	var mismatches []string
+
+	domainSeparator, err := safeInstance.DomainSeparator(&bind.CallOpts{})
+	if err != nil {
+		mismatches = append(mismatches, fmt.Sprintf("domainSeparator call failed: %v", err))
+	} else if common.Hash(domainSeparator) != components.DomainSeparator {
+		mismatches = append(mismatches, fmt.Sprintf("domain separator: Safe returns %s", common.Hash(domainSeparator).Hex()))
+	}
+
+	args, err := NewSafeTransactionArgs(txData)
+	if err != nil {
+		return err
+	}
+	onChainHash, err := safeInstance.GetTransactionHash(&bind.CallOpts{}, args.To, args.Value, args.Data, args.Operation, args.SafeTxGas, args.BaseGas, args.GasPrice, args.GasToken, args.RefundReceiver, args.Nonce)
+	if err != nil {
+		mismatches = append(mismatches, fmt.Sprintf("getTransactionHash call failed: %v", err))
+	} else if common.Hash(onChainHash) != components.SafeTxHash {
+		mismatches = append(mismatches, fmt.Sprintf("safeTxHash: Safe returns %s", common.Hash(onChainHash).Hex()))
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("local hash computation does not match the Safe: %v", mismatches)
+	}
+	return nil
+}
+
+func PrintSafeTxHashComponents(components *SafeTxHashComponents) error {
+	typedData, err := json.MarshalIndent(components.TypedData, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode typed data: %w", err)
+	}
+
+	fmt.Printf("Domain separator: %s\n", components.DomainSeparator.Hex())
+	fmt.Printf("Struct hash:      %s\n", components.StructHash.Hex())
+	fmt.Printf("SafeTxHash:       %s (keccak256(0x1901 || domain separator || struct hash))\n", components.SafeTxHash.Hex())
+	fmt.Printf("Typed data (eth_signTypedData_v4):\n%s\n", typedData)
+	return nil
+}
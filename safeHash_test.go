@@ -0,0 +1,35 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestParseSafeTxHash(t *testing.T) {
+	want := common.HexToHash("0x" + strings.Repeat("ab", 32))
+
+	for _, raw := range []string{want.Hex(), strings.TrimPrefix(want.Hex(), "0x"), "0X" + strings.Repeat("AB", 32)} {
+		got, err := parseSafeTxHash(raw)
+		if err != nil {
+			t.Errorf("parseSafeTxHash(%q): %v", raw, err)
+		} else if got != want {
+			t.Errorf("parseSafeTxHash(%q) = %s, want %s", raw, got, want)
+		}
+	}
+
+	for _, raw := range []string{
+		"",
+		"0x",
+		"0x" + strings.Repeat("ab", 31),
+		"0x" + strings.Repeat("ab", 33),
+		"0x" + strings.Repeat("a", 63),
+		"0x" + strings.Repeat("zz", 32),
+		"0x0x" + strings.Repeat("ab", 31),
+	} {
+		if _, err := parseSafeTxHash(raw); err == nil {
+			t.Errorf("parseSafeTxHash(%q) succeeded, want an error", raw)
+		}
+	}
+}
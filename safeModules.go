@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/safetx"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// Storage slots in which a Safe keeps its guards and fallback handler, as defined in GuardManager, ModuleManager
+// and FallbackManager.
+var (
+	SafeGuardStorageSlot           = common.HexToHash("0x4a204f620c8c5ccdca3fd54d003badd85ba500436a431f0cbda4f558c93c34c8")
+	SafeModuleGuardStorageSlot     = common.HexToHash("0xb104e0b93118902c651344349b610029d694cfdec91c589c91ebafbcd0289947")
+	SafeFallbackHandlerStorageSlot = common.HexToHash("0x6c9a6c4a39284e37ed1cf53d337577d14212a4870fb976a4366c693b939918d5")
+)
+
+// ERC-165 interface IDs that setGuard and setModuleGuard require the guard to support.
+var (
+	TransactionGuardInterfaceID = [4]byte{0xe6, 0xd7, 0xa8, 0x3a}
+	ModuleGuardInterfaceID      = [4]byte{0x58, 0x40, 0x1e, 0xd8}
+)
+
+// SafeStateReader reads the code, storage and call results of contracts. *ethclient.Client implements it.
+type SafeStateReader interface {
+	bind.ContractCaller
+	ethereum.ChainStateReader
+}
+
+// Page size used when walking the module list with getModulesPaginated.
+const safeModulesPageSize = 50
+
+// Returns every module enabled on a Safe, in linked list order.
+func GetSafeModules(safeAddress common.Address, client bind.ContractCaller) ([]common.Address, error) {
+	safeInstance, err := Safe.NewSafeCaller(safeAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Safe instance: %w", err)
+	}
+
+	var modules []common.Address
+	start := SafeSentinelAddress
+	for {
+		page, err := safeInstance.GetModulesPaginated(&bind.CallOpts{}, start, big.NewInt(safeModulesPageSize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch modules: %w", err)
+		}
+		modules = append(modules, page.Array...)
+		if page.Next == SafeSentinelAddress || page.Next == (common.Address{}) || len(page.Array) == 0 {
+			return modules, nil
+		}
+		start = page.Next
+	}
+}
+
+// Returns the module preceding module in the Safe's linked list, which disableModule takes as prevModule.
+func PrevModule(modules []common.Address, module common.Address) (common.Address, error) {
+	for i, existing := range modules {
+		if existing == module {
+			if i == 0 {
+				return SafeSentinelAddress, nil
+			}
+			return modules[i-1], nil
+		}
+	}
+	return common.Address{}, fmt.Errorf("%s is not an enabled module", module.Hex())
+}
+
+// Reads an address stored in one of the Safe's fixed storage slots.
+func readSafeAddressSlot(safeAddress common.Address, slot common.Hash, client ethereum.ChainStateReader) (common.Address, error) {
+	value, err := client.StorageAt(context.Background(), safeAddress, slot, nil)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to read storage slot %s: %w", slot.Hex(), err)
+	}
+	return common.BytesToAddress(value), nil
+}
+
+// Calls supportsInterface(interfaceID) on target. Contracts without the function, or that revert, do not support it.
+func SupportsInterface(target common.Address, interfaceID [4]byte, client bind.ContractCaller) bool {
+	// supportsInterface(bytes4) selector followed by the left aligned interface ID.
+	input := make([]byte, 36)
+	copy(input, []byte{0x01, 0xff, 0xc9, 0xa7})
+	copy(input[4:], interfaceID[:])
+
+	output, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &target, Data: input}, nil)
+	if err != nil || len(output) != 32 {
+		return false
+	}
+	return new(big.Int).SetBytes(output).Cmp(big.NewInt(1)) == 0
+}
+
+func hasCode(address common.Address, client bind.ContractCaller) (bool, error) {
+	code, err := client.CodeAt(context.Background(), address, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to get code at %s: %w", address.Hex(), err)
+	}
+	return len(code) > 0, nil
+}
+
+func describeModules(modules []common.Address) []string {
+	if len(modules) == 0 {
+		return []string{"No modules enabled"}
+	}
+	lines := []string{fmt.Sprintf("%d modules enabled", len(modules))}
+	for _, module := range modules {
+		lines = append(lines, module.Hex())
+	}
+	return lines
+}
+
+func describeOptionalAddress(label string, address common.Address) []string {
+	if address == (common.Address{}) {
+		return []string{label + ": none"}
+	}
+	return []string{label + ": " + address.Hex()}
+}
+
+// Builds an enableModule call.
+func NewEnableModuleChange(safeAddress common.Address, modules []common.Address, module common.Address, client bind.ContractCaller) (*SafeSettingsChange, error) {
+	if module == (common.Address{}) || module == SafeSentinelAddress {
+		return nil, fmt.Errorf("%s cannot be a module", module.Hex())
+	}
+	for _, existing := range modules {
+		if existing == module {
+			return nil, fmt.Errorf("%s is already enabled", module.Hex())
+		}
+	}
+
+	data, err := packSafeCall("enableModule", module)
+	if err != nil {
+		return nil, err
+	}
+
+	// enableModule inserts new modules at the head of the list.
+	change := &SafeSettingsChange{
+		Description: fmt.Sprintf("Enable module %s", module.Hex()),
+		Data:        data,
+		Before:      describeModules(modules),
+		After:       describeModules(append([]common.Address{module}, modules...)),
+	}
+	change.Warn("an enabled module can execute any transaction from the Safe without owner signatures")
+	code, err := hasCode(module, client)
+	if err != nil {
+		return nil, err
+	}
+	if !code {
+		change.Warn("%s has no code, so whoever controls it will have full control of the Safe", module.Hex())
+	}
+	return change, nil
+}
+
+// Builds a disableModule call, resolving prevModule from the Safe's module list.
+func NewDisableModuleChange(modules []common.Address, module common.Address) (*SafeSettingsChange, error) {
+	prevModule, err := PrevModule(modules, module)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := packSafeCall("disableModule", prevModule, module)
+	if err != nil {
+		return nil, err
+	}
+
+	var remaining []common.Address
+	for _, existing := range modules {
+		if existing != module {
+			remaining = append(remaining, existing)
+		}
+	}
+	return &SafeSettingsChange{
+		Description: fmt.Sprintf("Disable module %s (prevModule %s)", module.Hex(), prevModule.Hex()),
+		Data:        data,
+		Before:      describeModules(modules),
+		After:       describeModules(remaining),
+	}, nil
+}
+
+// Builds a setGuard call, or a setModuleGuard call if moduleGuard is set. The zero address removes the guard.
+func NewSetGuardChange(safeAddress common.Address, guard common.Address, moduleGuard bool, client SafeStateReader) (*SafeSettingsChange, error) {
+	label, method, slot, interfaceID := "Guard", "setGuard", SafeGuardStorageSlot, TransactionGuardInterfaceID
+	if moduleGuard {
+		label, method, slot, interfaceID = "Module guard", "setModuleGuard", SafeModuleGuardStorageSlot, ModuleGuardInterfaceID
+
+		safeInstance, err := Safe.NewSafeCaller(safeAddress, client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Safe instance: %w", err)
+		}
+		version, err := safeInstance.VERSION(&bind.CallOpts{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Safe version: %w", err)
+		}
+		supported, err := safetx.VersionAtLeast(version, 1, 5)
+		if err != nil {
+			return nil, err
+		}
+		if !supported {
+			return nil, fmt.Errorf("module guards require Safe 1.5.0 or later, this Safe is %s", version)
+		}
+	}
+
+	current, err := readSafeAddressSlot(safeAddress, slot, client)
+	if err != nil {
+		return nil, err
+	}
+	if current == guard {
+		return nil, fmt.Errorf("%s is already %s", label, guard.Hex())
+	}
+
+	if guard != (common.Address{}) && !SupportsInterface(guard, interfaceID, client) {
+		return nil, fmt.Errorf("%s does not support the %s interface 0x%x, the Safe would reject it", guard.Hex(), method, interfaceID)
+	}
+
+	data, err := packSafeCall(method, guard)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &SafeSettingsChange{
+		Data:   data,
+		Before: describeOptionalAddress(label, current),
+		After:  describeOptionalAddress(label, guard),
+	}
+	switch {
+	case guard == (common.Address{}):
+		change.Description = fmt.Sprintf("Remove %s %s", label, current.Hex())
+	case current == (common.Address{}):
+		change.Description = fmt.Sprintf("Set %s to %s", label, guard.Hex())
+	default:
+		change.Description = fmt.Sprintf("Replace %s %s with %s", label, current.Hex(), guard.Hex())
+	}
+
+	if guard != (common.Address{}) {
+		if moduleGuard {
+			change.Warn("a module guard that reverts blocks every module transaction")
+		} else {
+			change.Warn("a guard that reverts blocks every Safe transaction, including the one that would remove it")
+		}
+		change.ExtraConfirmation = fmt.Sprintf("%s %s can permanently lock the Safe if it is faulty. Have you audited it?", label, guard.Hex())
+	}
+	return change, nil
+}
+
+// Builds a setFallbackHandler call. The zero address removes the handler.
+func NewSetFallbackHandlerChange(safeAddress common.Address, handler common.Address, client SafeStateReader) (*SafeSettingsChange, error) {
+	if handler == safeAddress {
+		return nil, fmt.Errorf("the Safe cannot be its own fallback handler")
+	}
+
+	current, err := readSafeAddressSlot(safeAddress, SafeFallbackHandlerStorageSlot, client)
+	if err != nil {
+		return nil, err
+	}
+	if current == handler {
+		return nil, fmt.Errorf("the fallback handler is already %s", handler.Hex())
+	}
+
+	data, err := packSafeCall("setFallbackHandler", handler)
+	if err != nil {
+		return nil, err
+	}
+
+	change := &SafeSettingsChange{
+		Description: fmt.Sprintf("Set fallback handler to %s", handler.Hex()),
+		Data:        data,
+		Before:      describeOptionalAddress("Fallback handler", current),
+		After:       describeOptionalAddress("Fallback handler", handler),
+	}
+	if handler == (common.Address{}) {
+		change.Description = fmt.Sprintf("Remove fallback handler %s", current.Hex())
+		change.Warn("without a fallback handler the Safe cannot validate EIP-1271 signatures or receive ERC-721 and ERC-1155 tokens safely")
+		return change, nil
+	}
+
+	code, err := hasCode(handler, client)
+	if err != nil {
+		return nil, err
+	}
+	if !code {
+		return nil, fmt.Errorf("%s has no code", handler.Hex())
+	}
+	return change, nil
+}
+
+func createSafeModulesCmd() *cobra.Command {
+	modulesCmd := &cobra.Command{
+		Use:   "modules",
+		Short: "Propose enabling or disabling Safe modules",
+	}
+
+	var enableModuleRaw string
+	enableCmd := createSafeSettingsCmd("enable", "Propose enabling a module", addressFlagValidator("module", &enableModuleRaw), func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error) {
+		modules, err := GetSafeModules(safeAddress, client)
+		if err != nil {
+			return nil, err
+		}
+		return NewEnableModuleChange(safeAddress, modules, common.HexToAddress(enableModuleRaw), client)
+	})
+	enableCmd.Flags().StringVar(&enableModuleRaw, "module", "", "Address of the module to enable")
+	enableCmd.MarkFlagRequired("module")
+
+	var disableModuleRaw string
+	disableCmd := createSafeSettingsCmd("disable", "Propose disabling a module", addressFlagValidator("module", &disableModuleRaw), func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error) {
+		modules, err := GetSafeModules(safeAddress, client)
+		if err != nil {
+			return nil, err
+		}
+		return NewDisableModuleChange(modules, common.HexToAddress(disableModuleRaw))
+	})
+	disableCmd.Flags().StringVar(&disableModuleRaw, "module", "", "Address of the module to disable")
+	disableCmd.MarkFlagRequired("module")
+
+	modulesCmd.AddCommand(enableCmd, disableCmd)
+	return modulesCmd
+}
+
+func createSafeGuardCmd() *cobra.Command {
+	guardCmd := &cobra.Command{
+		Use:   "guard",
+		Short: "Propose setting or removing a Safe's transaction or module guard",
+	}
+
+	var guardRaw string
+	var setModuleGuard bool
+	setCmd := createSafeSettingsCmd("set", "Propose setting the guard", addressFlagValidator("guard", &guardRaw), func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error) {
+		return NewSetGuardChange(safeAddress, common.HexToAddress(guardRaw), setModuleGuard, client)
+	})
+	setCmd.Flags().StringVar(&guardRaw, "guard", "", "Address of the guard")
+	setCmd.Flags().BoolVar(&setModuleGuard, "module-guard", false, "Set the module guard (Safe 1.5.0 and later) instead of the transaction guard")
+	setCmd.MarkFlagRequired("guard")
+
+	var removeModuleGuard bool
+	removeCmd := createSafeSettingsCmd("remove", "Propose removing the guard", nil, func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error) {
+		return NewSetGuardChange(safeAddress, common.Address{}, removeModuleGuard, client)
+	})
+	removeCmd.Flags().BoolVar(&removeModuleGuard, "module-guard", false, "Remove the module guard instead of the transaction guard")
+
+	guardCmd.AddCommand(setCmd, removeCmd)
+	return guardCmd
+}
+
+func createSafeHandlerCmd() *cobra.Command {
+	handlerCmd := &cobra.Command{
+		Use:   "handler",
+		Short: "Propose changing a Safe's fallback handler",
+	}
+
+	var handlerRaw string
+	setCmd := createSafeSettingsCmd("set", "Propose setting the fallback handler", addressFlagValidator("handler", &handlerRaw), func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error) {
+		return NewSetFallbackHandlerChange(safeAddress, common.HexToAddress(handlerRaw), client)
+	})
+	setCmd.Flags().StringVar(&handlerRaw, "handler", "", "Address of the fallback handler")
+	setCmd.MarkFlagRequired("handler")
+
+	removeCmd := createSafeSettingsCmd("remove", "Propose removing the fallback handler", nil, func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error) {
+		return NewSetFallbackHandlerChange(safeAddress, common.Address{}, client)
+	})
+
+	handlerCmd.AddCommand(setCmd, removeCmd)
+	return handlerCmd
+}
+
+// Returns a validate function for createSafeSettingsCmd checking that an address flag is a valid address.
+func addressFlagValidator(name string, value *string) func() error {
+	return func() error {
+		if !common.IsHexAddress(*value) {
+			return fmt.Errorf("invalid --%s address: %s", name, *value)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Deploys a contract whose code returns the 32-byte word value to every call, so that it answers every
+// supportsInterface query with value.
+func (c *testChain) deployConstant(t *testing.T, value byte) common.Address {
+	t.Helper()
+	// PUSH1 value PUSH1 0 MSTORE PUSH1 32 PUSH1 0 RETURN
+	return c.deployRuntime(t, []byte{0x60, value, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xf3})
+}
+
+// Deploys a contract with the given runtime code, which the constructor copies into memory and returns.
+func (c *testChain) deployRuntime(t *testing.T, runtime []byte) common.Address {
+	t.Helper()
+	if len(runtime) > 0xff {
+		t.Fatalf("runtime of %d bytes does not fit the constructor's PUSH1", len(runtime))
+	}
+	// PUSH1 len PUSH1 12 PUSH1 0 CODECOPY PUSH1 len PUSH1 0 RETURN
+	constructor := []byte{0x60, byte(len(runtime)), 0x60, 0x0c, 0x60, 0x00, 0x39, 0x60, byte(len(runtime)), 0x60, 0x00, 0xf3}
+	address, _, _, err := bind.DeployContract(c.deployer, abi.ABI{}, append(constructor, runtime...), c.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.backend.Commit()
+	return address
+}
+
+func TestPrevModule(t *testing.T) {
+	a := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	b := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	modules := []common.Address{a, b}
+
+	if prev, err := PrevModule(modules, a); err != nil || prev != SafeSentinelAddress {
+		t.Errorf("PrevModule of the first module = %s, %v, want the sentinel", prev.Hex(), err)
+	}
+	if prev, err := PrevModule(modules, b); err != nil || prev != a {
+		t.Errorf("PrevModule of the second module = %s, %v, want %s", prev.Hex(), err, a.Hex())
+	}
+	if _, err := PrevModule(modules, common.HexToAddress("0x000000000000000000000000000000000000000c")); err == nil {
+		t.Error("found a prevModule for a module that is not enabled")
+	}
+}
+
+// Enables more modules than fit in one page of getModulesPaginated, then disables the first module, one in the
+// second page and the last one, checking that the list read back matches each change.
+func TestSafeModulesOnChain(t *testing.T) {
+	chain := newTestChain(t)
+	keys, owners := testKeys(t, 1)
+	safeAddress := chain.deploySafe(t, owners, 1)
+
+	count := safeModulesPageSize + 5
+	for i := 0; i < count; i++ {
+		modules, err := GetSafeModules(safeAddress, chain.client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		module := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+		change, err := NewEnableModuleChange(safeAddress, modules, module, chain.client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := chain.execSelfCall(t, safeAddress, keys, change.Data); err != nil {
+			t.Fatalf("enabling module %d: %v", i, err)
+		}
+	}
+
+	modules, err := GetSafeModules(safeAddress, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modules) != count {
+		t.Fatalf("read %d modules, want %d", len(modules), count)
+	}
+	// Modules are inserted at the head of the list, so the last one enabled comes first.
+	for i, module := range modules {
+		if want := common.BigToAddress(big.NewInt(int64(0x1000 + count - 1 - i))); module != want {
+			t.Fatalf("module %d is %s, want %s", i, module.Hex(), want.Hex())
+		}
+	}
+	if _, err := NewEnableModuleChange(safeAddress, modules, modules[3], chain.client); err == nil {
+		t.Error("enabled a module twice")
+	}
+
+	for _, pick := range []func([]common.Address) common.Address{
+		func(m []common.Address) common.Address { return m[0] },
+		func(m []common.Address) common.Address { return m[safeModulesPageSize+1] },
+		func(m []common.Address) common.Address { return m[len(m)-1] },
+		func(m []common.Address) common.Address { return m[1] },
+	} {
+		before, err := GetSafeModules(safeAddress, chain.client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		module := pick(before)
+		change, err := NewDisableModuleChange(before, module)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := chain.execSelfCall(t, safeAddress, keys, change.Data); err != nil {
+			t.Fatalf("%s: %v", change.Description, err)
+		}
+		after, err := GetSafeModules(safeAddress, chain.client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := describeModules(after); strings.Join(got, "\n") != strings.Join(change.After, "\n") {
+			t.Fatalf("%s: Safe has\n%s\nwant\n%s", change.Description, strings.Join(got, "\n"), strings.Join(change.After, "\n"))
+		}
+	}
+
+	if _, err := NewDisableModuleChange(modules, common.HexToAddress("0xdead")); err == nil {
+		t.Error("disabled a module that is not enabled")
+	}
+}
+
+func TestNewSetGuardChange(t *testing.T) {
+	chain := newTestChain(t)
+	keys, owners := testKeys(t, 1)
+	safeAddress := chain.deploySafe(t, owners, 1)
+	guard := chain.deployConstant(t, 1)
+	notGuard := chain.deployConstant(t, 0)
+
+	if _, err := NewSetGuardChange(safeAddress, common.HexToAddress("0x000000000000000000000000000000000000dEaD"), false, chain.client); err == nil {
+		t.Error("accepted a guard address without code")
+	}
+	if _, err := NewSetGuardChange(safeAddress, notGuard, false, chain.client); err == nil {
+		t.Error("accepted a guard without the guard interface")
+	}
+	if _, err := NewSetGuardChange(safeAddress, guard, true, chain.client); err == nil {
+		t.Error("accepted a module guard on a 1.4.1 Safe")
+	}
+	if _, err := NewSetGuardChange(safeAddress, common.Address{}, false, chain.client); err == nil {
+		t.Error("accepted removing a guard that is not set")
+	}
+
+	change, err := NewSetGuardChange(safeAddress, guard, false, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.ExtraConfirmation == "" {
+		t.Error("setting a guard does not ask for extra confirmation")
+	}
+	if err := chain.execSelfCall(t, safeAddress, keys, change.Data); err != nil {
+		t.Fatalf("setGuard rejected: %v", err)
+	}
+	current, err := readSafeAddressSlot(safeAddress, SafeGuardStorageSlot, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current != guard {
+		t.Fatalf("guard slot holds %s, want %s", current.Hex(), guard.Hex())
+	}
+
+	if _, err := NewSetGuardChange(safeAddress, guard, false, chain.client); err == nil {
+		t.Error("accepted setting the current guard again")
+	}
+	change, err = NewSetGuardChange(safeAddress, common.Address{}, false, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := chain.execSelfCall(t, safeAddress, keys, change.Data); err != nil {
+		t.Fatalf("removing the guard failed: %v", err)
+	}
+}
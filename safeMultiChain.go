@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/G7DAO/safes/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// RPC URLs of the chains that can be given by name to --chains.
+var KnownChainRPCs = map[string]string{
+	"g7":         "https://mainnet-rpc.game7.io",
+	"g7-testnet": "https://testnet-rpc.game7.io",
+}
+
+// Resolves a --chains entry, either the name of a known chain or an RPC URL, to an RPC URL.
+func ResolveChainRPC(chain string) (string, error) {
+	if url, ok := KnownChainRPCs[strings.ToLower(chain)]; ok {
+		return url, nil
+	}
+	if strings.Contains(chain, "://") {
+		return chain, nil
+	}
+
+	names := make([]string, 0, len(KnownChainRPCs))
+	for name := range KnownChainRPCs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("unknown chain %q, use an RPC URL or one of: %s", chain, strings.Join(names, ", "))
+}
+
+// SafeChainDeployment is the state of a Safe creation on one chain of a multi-chain deployment. Chains with a Problem
+// are reported and skipped.
+type SafeChainDeployment struct {
+	Chain    string
+	Client   *ethclient.Client
+	ChainID  *big.Int
+	Creation SafeCreation
+	Address  common.Address
+	Deployed bool
+	Problem  string
+
+	factoryCodeHash   common.Hash
+	singletonCodeHash common.Hash
+}
+
+func (deployment *SafeChainDeployment) Name() string {
+	if deployment.ChainID == nil {
+		return deployment.Chain
+	}
+	return fmt.Sprintf("%s (chain %s)", deployment.Chain, deployment.ChainID.String())
+}
+
+func codeHash(address common.Address, client *ethclient.Client) (common.Hash, error) {
+	code, err := client.CodeAt(context.Background(), address, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get code at %s: %w", address.Hex(), err)
+	}
+	return crypto.Keccak256Hash(code), nil
+}
+
+// Connects to one chain and works out where the Safe would be deployed on it.
+func planSafeChainDeployment(chain string, creationFlags *safeCreationFlags) *SafeChainDeployment {
+	deployment := &SafeChainDeployment{Chain: chain}
+	fail := func(format string, args ...interface{}) *SafeChainDeployment {
+		deployment.Problem = fmt.Sprintf(format, args...)
+		return deployment
+	}
+
+	url, err := ResolveChainRPC(chain)
+	if err != nil {
+		return fail("%v", err)
+	}
+	deployment.Client, err = ethclient.Dial(url)
+	if err != nil {
+		return fail("failed to connect: %v", err)
+	}
+	deployment.ChainID, err = deployment.Client.ChainID(context.Background())
+	if err != nil {
+		return fail("failed to get chain ID: %v", err)
+	}
+
+	deployment.Creation, err = creationFlags.Creation(deployment.Client)
+	if err != nil {
+		return fail("%v", err)
+	}
+	if err := CheckSafeCreationContracts(deployment.Creation, deployment.Client); err != nil {
+		return fail("%v", err)
+	}
+	if deployment.factoryCodeHash, err = codeHash(deployment.Creation.Factory, deployment.Client); err != nil {
+		return fail("%v", err)
+	}
+	if deployment.singletonCodeHash, err = codeHash(deployment.Creation.Singleton, deployment.Client); err != nil {
+		return fail("%v", err)
+	}
+
+	deployment.Address, err = PredictSafeAddressOnChain(deployment.Creation, deployment.Client)
+	if err != nil {
+		return fail("%v", err)
+	}
+	deployment.Deployed, err = hasCode(deployment.Address, deployment.Client)
+	if err != nil {
+		return fail("%v", err)
+	}
+	return deployment
+}
+
+// Plans the creation of the same Safe on several chains. The first usable chain is the reference: the factory and
+// singleton must have the same bytecode on every other chain and, unless the creation is chain-specific, the
+// predicted address must be the same. A Safe already deployed at the address must have the requested setup.
+func PlanMultiChainSafeCreation(chains []string, creationFlags *safeCreationFlags) []*SafeChainDeployment {
+	deployments := make([]*SafeChainDeployment, len(chains))
+	for i, chain := range chains {
+		deployments[i] = planSafeChainDeployment(chain, creationFlags)
+	}
+
+	var reference *SafeChainDeployment
+	seen := make(map[string]string)
+	for _, deployment := range deployments {
+		if deployment.Problem != "" {
+			continue
+		}
+		if other, ok := seen[deployment.ChainID.String()]; ok {
+			deployment.Problem = fmt.Sprintf("same chain as %s", other)
+			continue
+		}
+		seen[deployment.ChainID.String()] = deployment.Chain
+
+		if reference == nil {
+			reference = deployment
+		} else {
+			switch {
+			case deployment.factoryCodeHash != reference.factoryCodeHash:
+				deployment.Problem = fmt.Sprintf("factory bytecode differs from %s", reference.Name())
+			case deployment.singletonCodeHash != reference.singletonCodeHash:
+				deployment.Problem = fmt.Sprintf("singleton bytecode differs from %s", reference.Name())
+			case !deployment.Creation.ChainSpecific && deployment.Address != reference.Address:
+				deployment.Problem = fmt.Sprintf("address would be %s instead of %s", deployment.Address.Hex(), reference.Address.Hex())
+			}
+			if deployment.Problem != "" {
+				continue
+			}
+		}
+
+		if deployment.Deployed {
+			if err := VerifySafeSetup(deployment.Address, deployment.Creation.Setup, deployment.Client); err != nil {
+				deployment.Problem = fmt.Sprintf("existing contract does not match the setup: %v", err)
+			}
+		}
+	}
+	return deployments
+}
+
+func PrintMultiChainSafeCreation(deployments []*SafeChainDeployment) {
+	for _, deployment := range deployments {
+		switch {
+		case deployment.Problem != "":
+			fmt.Printf("%s: SKIPPED, %s\n", deployment.Name(), deployment.Problem)
+		case deployment.Deployed:
+			fmt.Printf("%s: %s already deployed\n", deployment.Name(), deployment.Address.Hex())
+		default:
+			fmt.Printf("%s: %s to deploy\n", deployment.Name(), deployment.Address.Hex())
+		}
+	}
+}
+
+// Deploys the Safe on every planned chain where it is missing. CreateSafe checks the owners and threshold of each new
+// Safe. Chains that fail are recorded with a Problem and do not stop the others.
+func ExecuteMultiChainSafeCreation(deployments []*SafeChainDeployment, key signer.Signer) {
+	for _, deployment := range deployments {
+		if deployment.Problem != "" || deployment.Deployed {
+			continue
+		}
+
+		fmt.Printf("Deploying on %s\n", deployment.Name())
+		address, err := CreateSafe(deployment.Creation, key, deployment.ChainID, deployment.Client)
+		if err != nil {
+			deployment.Problem = fmt.Sprintf("deployment failed: %v", err)
+			continue
+		}
+		if address != deployment.Address {
+			deployment.Problem = fmt.Sprintf("Safe was deployed at %s, not at the predicted %s", address.Hex(), deployment.Address.Hex())
+			continue
+		}
+		deployment.Deployed = true
+	}
+}
+
+// Returns an error naming the chains with problems, if any.
+func MultiChainSafeCreationError(deployments []*SafeChainDeployment) error {
+	var failed []string
+	for _, deployment := range deployments {
+		if deployment.Problem != "" {
+			failed = append(failed, deployment.Name())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("Safe not created on %d of %d chains: %s", len(failed), len(deployments), strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/G7DAO/seer/bindings/GnosisSafe"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Parses a nonce flag as a non-negative decimal integer, or a hexadecimal one with a 0x prefix. A leading zero does
+// not make the nonce octal.
+func parseSafeNonce(flag string, raw string) (*big.Int, error) {
+	nonce, ok := ParseInteger(raw)
+	if !ok || nonce.Sign() < 0 {
+		return nil, fmt.Errorf("--%s must be a non-negative decimal or 0x-prefixed hex integer, got %q", flag, raw)
+	}
+	return nonce, nil
+}
+
+// SafeNonceQueue summarises which nonces are taken by transactions queued on the Safe Transaction Service.
+type SafeNonceQueue struct {
+	// The Safe's current on-chain nonce, i.e. the nonce of the next transaction it can execute.
+	OnChain uint64
+	// Number of queued transactions per nonce, for nonces at or above OnChain.
+	Queued map[uint64]int
+}
+
+// Returns the queued nonces in ascending order.
+func (q SafeNonceQueue) Nonces() []uint64 {
+	nonces := make([]uint64, 0, len(q.Queued))
+	for nonce := range q.Queued {
+		nonces = append(nonces, nonce)
+	}
+	sort.Slice(nonces, func(i, j int) bool { return nonces[i] < nonces[j] })
+	return nonces
+}
+
+// Returns the lowest nonce above every queued transaction, which a new proposal can use without conflicting.
+func (q SafeNonceQueue) NextFree() uint64 {
+	next := q.OnChain
+	for nonce := range q.Queued {
+		if nonce+1 > next {
+			next = nonce + 1
+		}
+	}
+	return next
+}
+
+// SafeNonceRange is an inclusive range of nonces.
+type SafeNonceRange struct {
+	From uint64
+	To   uint64
+}
+
+// Returns the number of nonces in the range.
+func (r SafeNonceRange) Len() uint64 {
+	return r.To - r.From + 1
+}
+
+func (r SafeNonceRange) String() string {
+	if r.From == r.To {
+		return fmt.Sprintf("%d", r.From)
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.To)
+}
+
+// Returns the ranges of nonces below the given one which neither have been executed nor have a queued
+// transaction. A transaction at the given nonce cannot execute until every gap has been filled. Only the queued
+// nonces are visited, so a stray proposal at a huge nonce produces one range rather than one entry per nonce.
+func (q SafeNonceQueue) GapsBelow(nonce uint64) []SafeNonceRange {
+	var gaps []SafeNonceRange
+	next := q.OnChain
+	for _, queued := range q.Nonces() {
+		if queued >= nonce {
+			break
+		}
+		if queued > next {
+			gaps = append(gaps, SafeNonceRange{From: next, To: queued - 1})
+		}
+		next = queued + 1
+	}
+	if nonce > next {
+		gaps = append(gaps, SafeNonceRange{From: next, To: nonce - 1})
+	}
+	return gaps
+}
+
+// Returns the queued nonces with more than one transaction competing for them.
+func (q SafeNonceQueue) Conflicts() []uint64 {
+	var conflicts []uint64
+	for _, nonce := range q.Nonces() {
+		if q.Queued[nonce] > 1 {
+			conflicts = append(conflicts, nonce)
+		}
+	}
+	return conflicts
+}
+
+// Reads the Safe's on-chain nonce and the nonces of its queued transactions.
+func GetSafeNonceQueue(safeAddress common.Address, chainID *big.Int, client *ethclient.Client, safeClientURL string) (*SafeNonceQueue, error) {
+	safeInstance, err := GnosisSafe.NewGnosisSafe(safeAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GnosisSafe instance: %w", err)
+	}
+
+	onChain, err := safeInstance.Nonce(&bind.CallOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch nonce: %w", err)
+	}
+
+	queue := &SafeNonceQueue{OnChain: onChain.Uint64(), Queued: make(map[uint64]int)}
+
+	queued, err := GetQueuedTransactions(safeClientURL, chainID, safeAddress)
+	if err != nil {
+		return queue, err
+	}
+	for _, tx := range queued {
+		if tx.Nonce >= queue.OnChain {
+			queue.Queued[tx.Nonce]++
+		}
+	}
+
+	return queue, nil
+}
+
+// Chooses the nonce for a new proposal. Without an explicit nonce this is the next nonce not taken by a queued
+// transaction. Warnings are printed for gaps in the queue and for nonces already taken by other proposals. If the
+// queue cannot be fetched, an explicit nonce is still used, with a warning, but otherwise no nonce is chosen since
+// the on-chain nonce would likely collide with a queued proposal.
+func SelectSafeNonce(safeAddress common.Address, chainID *big.Int, client *ethclient.Client, safeClientURL string, explicit *big.Int) (*big.Int, error) {
+	queue, err := GetSafeNonceQueue(safeAddress, chainID, client, safeClientURL)
+	if queue == nil {
+		return nil, err
+	}
+	if err != nil {
+		if explicit == nil {
+			return nil, fmt.Errorf("could not fetch the transaction queue to choose a Safe nonce, pass one explicitly with --safe-nonce: %w", err)
+		}
+		fmt.Printf("Warning: could not fetch the transaction queue (%v), nonce conflicts cannot be detected\n", err)
+	}
+
+	for _, nonce := range queue.Conflicts() {
+		fmt.Printf("Warning: %d queued proposals compete for nonce %d, only one of them can execute\n", queue.Queued[nonce], nonce)
+	}
+
+	var nonce uint64
+	if explicit == nil {
+		nonce = queue.NextFree()
+		fmt.Printf("Using Safe nonce %d (on-chain nonce %d, %d nonces queued)\n", nonce, queue.OnChain, len(queue.Queued))
+	} else {
+		if !explicit.IsUint64() || explicit.Uint64() < queue.OnChain {
+			return nil, fmt.Errorf("--safe-nonce %s has already been used, the Safe's nonce is %d", explicit.String(), queue.OnChain)
+		}
+		nonce = explicit.Uint64()
+		if count := queue.Queued[nonce]; count > 0 {
+			fmt.Printf("Warning: nonce %d is already taken by %d queued proposal(s), only one of them can execute\n", nonce, count)
+		}
+	}
+
+	if gaps := queue.GapsBelow(nonce); len(gaps) > 0 {
+		fmt.Printf("Warning: no proposal exists for nonce(s) %v, this proposal cannot execute until they are used\n", gaps)
+	}
+
+	return new(big.Int).SetUint64(nonce), nil
+}
+
+// Groups proposals by nonce, building the matching SafeNonceQueue. Proposals with a nonce below the on-chain nonce
+// can no longer execute and are left out.
+func GroupSafeProposalsByNonce(proposals []SafeProposal, onChain uint64) (*SafeNonceQueue, map[uint64][]SafeProposal) {
+	queue := &SafeNonceQueue{OnChain: onChain, Queued: make(map[uint64]int)}
+	groups := make(map[uint64][]SafeProposal)
+	for _, proposal := range proposals {
+		if !proposal.Tx.Nonce.IsUint64() || proposal.Tx.Nonce.Uint64() < onChain {
+			continue
+		}
+		nonce := proposal.Tx.Nonce.Uint64()
+		queue.Queued[nonce]++
+		groups[nonce] = append(groups[nonce], proposal)
+	}
+	return queue, groups
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSafeNonceQueueGapsBelow(t *testing.T) {
+	queue := SafeNonceQueue{OnChain: 5, Queued: map[uint64]int{5: 1, 7: 2, 8: 1, 12: 1, 1_000_000_000: 1}}
+
+	cases := []struct {
+		nonce uint64
+		want  []SafeNonceRange
+	}{
+		{5, nil},
+		{6, nil},
+		{7, []SafeNonceRange{{6, 6}}},
+		{9, []SafeNonceRange{{6, 6}}},
+		{12, []SafeNonceRange{{6, 6}, {9, 11}}},
+		{1_000_000_001, []SafeNonceRange{{6, 6}, {9, 11}, {13, 999_999_999}}},
+	}
+	for _, c := range cases {
+		if got := queue.GapsBelow(c.nonce); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("GapsBelow(%d) = %v, want %v", c.nonce, got, c.want)
+		}
+	}
+
+	if got := queue.NextFree(); got != 1_000_000_001 {
+		t.Errorf("NextFree = %d, want 1000000001", got)
+	}
+	if got := queue.Conflicts(); !reflect.DeepEqual(got, []uint64{7}) {
+		t.Errorf("Conflicts = %v, want [7]", got)
+	}
+	if got := fmt.Sprint(queue.GapsBelow(13)); got != "[6 9-11]" {
+		t.Errorf("GapsBelow(13) printed as %s, want [6 9-11]", got)
+	}
+
+	empty := SafeNonceQueue{OnChain: 3, Queued: map[uint64]int{}}
+	if got := empty.GapsBelow(6); !reflect.DeepEqual(got, []SafeNonceRange{{3, 5}}) {
+		t.Errorf("GapsBelow on an empty queue = %v, want [3-5]", got)
+	}
+}
+
+func TestParseSafeNonce(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want int64
+		ok   bool
+	}{
+		{"0", 0, true},
+		{"10", 10, true},
+		{"010", 10, true},
+		{"0x10", 16, true},
+		{"0X1f", 31, true},
+		{"-1", 0, false},
+		{"-0x1", 0, false},
+		{"0b101", 0, false},
+		{"0o17", 0, false},
+		{"1_000", 0, false},
+		{"", 0, false},
+		{"ten", 0, false},
+	}
+	for _, c := range cases {
+		got, err := parseSafeNonce("safe-nonce", c.raw)
+		if !c.ok {
+			if err == nil {
+				t.Errorf("parseSafeNonce(%q) = %s, want an error", c.raw, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSafeNonce(%q): %v", c.raw, err)
+			continue
+		}
+		if got.Int64() != c.want {
+			t.Errorf("parseSafeNonce(%q) = %s, want %d", c.raw, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// Sentinel at the head of the Safe's owner and module linked lists. It is the prevOwner of the first owner and the
+// prevModule of the first module.
+var SafeSentinelAddress = common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+// SafeOwnerState is a Safe's owner set and threshold.
+type SafeOwnerState struct {
+	Owners    []common.Address
+	Threshold uint64
+}
+
+func GetSafeOwnerState(safeAddress common.Address, client *ethclient.Client) (*SafeOwnerState, error) {
+	safeInstance, err := Safe.NewSafe(safeAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Safe instance: %w", err)
+	}
+
+	owners, err := safeInstance.GetOwners(&bind.CallOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch owners: %w", err)
+	}
+	threshold, err := safeInstance.GetThreshold(&bind.CallOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch threshold: %w", err)
+	}
+
+	return &SafeOwnerState{Owners: owners, Threshold: threshold.Uint64()}, nil
+}
+
+func (state *SafeOwnerState) IsOwner(address common.Address) bool {
+	return state.index(address) >= 0
+}
+
+// Returns the owner preceding owner in the Safe's linked list, which removeOwner and swapOwner take as prevOwner.
+// GetOwners returns the list in order, so this is the previous owner, or the sentinel for the first.
+func (state *SafeOwnerState) PrevOwner(owner common.Address) (common.Address, error) {
+	i := state.index(owner)
+	switch {
+	case i < 0:
+		return common.Address{}, fmt.Errorf("%s is not an owner", owner.Hex())
+	case i == 0:
+		return SafeSentinelAddress, nil
+	}
+	return state.Owners[i-1], nil
+}
+
+func (state *SafeOwnerState) index(address common.Address) int {
+	for i, owner := range state.Owners {
+		if owner == address {
+			return i
+		}
+	}
+	return -1
+}
+
+func (state *SafeOwnerState) Describe() []string {
+	lines := []string{fmt.Sprintf("Threshold %d of %d owners", state.Threshold, len(state.Owners))}
+	for _, owner := range state.Owners {
+		lines = append(lines, owner.Hex())
+	}
+	return lines
+}
+
+// Builds the SafeSettingsChange for a transition between two owner states, checking the new threshold.
+func newSafeOwnerChange(description string, before, after *SafeOwnerState, data []byte) (*SafeSettingsChange, error) {
+	if after.Threshold < 1 || after.Threshold > uint64(len(after.Owners)) {
+		return nil, fmt.Errorf("threshold %d is invalid, it must be between 1 and the number of owners (%d)", after.Threshold, len(after.Owners))
+	}
+
+	change := &SafeSettingsChange{
+		Description: description,
+		Data:        data,
+		Before:      before.Describe(),
+		After:       after.Describe(),
+	}
+	if after.Threshold == 1 && before.Threshold > 1 {
+		change.Warn("the threshold drops to 1, any single owner will be able to execute transactions alone")
+	}
+	return change, nil
+}
+
+// Checks that an address can be added as an owner, as addOwnerWithThreshold and swapOwner do.
+func checkNewOwner(safeAddress common.Address, state *SafeOwnerState, owner common.Address) error {
+	switch {
+	case owner == (common.Address{}) || owner == SafeSentinelAddress:
+		return fmt.Errorf("%s cannot be an owner", owner.Hex())
+	case owner == safeAddress:
+		return fmt.Errorf("the Safe cannot be its own owner")
+	case state.IsOwner(owner):
+		return fmt.Errorf("%s is already an owner", owner.Hex())
+	}
+	return nil
+}
+
+// Builds an addOwnerWithThreshold call. A nil threshold keeps the current one.
+func NewAddOwnerChange(safeAddress common.Address, state *SafeOwnerState, owner common.Address, threshold *uint64) (*SafeSettingsChange, error) {
+	if err := checkNewOwner(safeAddress, state, owner); err != nil {
+		return nil, err
+	}
+
+	// addOwner inserts new owners at the head of the list.
+	after := &SafeOwnerState{Owners: append([]common.Address{owner}, state.Owners...), Threshold: state.Threshold}
+	if threshold != nil {
+		after.Threshold = *threshold
+	}
+
+	data, err := packSafeCall("addOwnerWithThreshold", owner, new(big.Int).SetUint64(after.Threshold))
+	if err != nil {
+		return nil, err
+	}
+	return newSafeOwnerChange(fmt.Sprintf("Add owner %s", owner.Hex()), state, after, data)
+}
+
+// Builds a removeOwner call. A nil threshold keeps the current one, lowered if needed to the remaining owner count.
+func NewRemoveOwnerChange(state *SafeOwnerState, owner common.Address, threshold *uint64) (*SafeSettingsChange, error) {
+	prevOwner, err := state.PrevOwner(owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(state.Owners) == 1 {
+		return nil, fmt.Errorf("cannot remove the only owner")
+	}
+
+	after := &SafeOwnerState{Threshold: state.Threshold}
+	for _, existing := range state.Owners {
+		if existing != owner {
+			after.Owners = append(after.Owners, existing)
+		}
+	}
+	if threshold != nil {
+		after.Threshold = *threshold
+	} else if after.Threshold > uint64(len(after.Owners)) {
+		after.Threshold = uint64(len(after.Owners))
+	}
+
+	data, err := packSafeCall("removeOwner", prevOwner, owner, new(big.Int).SetUint64(after.Threshold))
+	if err != nil {
+		return nil, err
+	}
+	return newSafeOwnerChange(fmt.Sprintf("Remove owner %s (prevOwner %s)", owner.Hex(), prevOwner.Hex()), state, after, data)
+}
+
+// Builds a swapOwner call replacing oldOwner with newOwner in place.
+func NewSwapOwnerChange(safeAddress common.Address, state *SafeOwnerState, oldOwner common.Address, newOwner common.Address) (*SafeSettingsChange, error) {
+	prevOwner, err := state.PrevOwner(oldOwner)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNewOwner(safeAddress, state, newOwner); err != nil {
+		return nil, err
+	}
+
+	after := &SafeOwnerState{Owners: make([]common.Address, len(state.Owners)), Threshold: state.Threshold}
+	copy(after.Owners, state.Owners)
+	after.Owners[state.index(oldOwner)] = newOwner
+
+	data, err := packSafeCall("swapOwner", prevOwner, oldOwner, newOwner)
+	if err != nil {
+		return nil, err
+	}
+	return newSafeOwnerChange(fmt.Sprintf("Swap owner %s for %s (prevOwner %s)", oldOwner.Hex(), newOwner.Hex(), prevOwner.Hex()), state, after, data)
+}
+
+// Builds a changeThreshold call.
+func NewChangeThresholdChange(state *SafeOwnerState, threshold uint64) (*SafeSettingsChange, error) {
+	if threshold == state.Threshold {
+		return nil, fmt.Errorf("the threshold is already %d", threshold)
+	}
+
+	after := &SafeOwnerState{Owners: state.Owners, Threshold: threshold}
+	data, err := packSafeCall("changeThreshold", new(big.Int).SetUint64(threshold))
+	if err != nil {
+		return nil, err
+	}
+	return newSafeOwnerChange(fmt.Sprintf("Change threshold from %d to %d", state.Threshold, threshold), state, after, data)
+}
+
+func createSafeOwnersCmd() *cobra.Command {
+	ownersCmd := &cobra.Command{
+		Use:   "owners",
+		Short: "Propose changes to a Safe's owners and threshold",
+		Long: `Propose changes to a Safe's owners and threshold. The change is proposed as a transaction from the Safe to
+itself, so it needs the approval of the current owners. prevOwner arguments are looked up from the Safe's owner list.`,
+	}
+
+	ownersCmd.AddCommand(createAddSafeOwnerCmd())
+	ownersCmd.AddCommand(createRemoveSafeOwnerCmd())
+	ownersCmd.AddCommand(createSwapSafeOwnerCmd())
+	ownersCmd.AddCommand(createChangeSafeThresholdCmd())
+
+	return ownersCmd
+}
+
+// Parses an optional --threshold flag, where "" keeps the default.
+func parseThresholdFlag(raw string) (*uint64, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	threshold, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --threshold: %s", raw)
+	}
+	return &threshold, nil
+}
+
+func createAddSafeOwnerCmd() *cobra.Command {
+	var ownerRaw, thresholdRaw string
+	var threshold *uint64
+
+	validate := func() error {
+		if !common.IsHexAddress(ownerRaw) {
+			return fmt.Errorf("invalid owner address: %s", ownerRaw)
+		}
+		var err error
+		threshold, err = parseThresholdFlag(thresholdRaw)
+		return err
+	}
+	addCmd := createSafeSettingsCmd("add", "Propose adding an owner", validate, func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error) {
+		state, err := GetSafeOwnerState(safeAddress, client)
+		if err != nil {
+			return nil, err
+		}
+		return NewAddOwnerChange(safeAddress, state, common.HexToAddress(ownerRaw), threshold)
+	})
+	addCmd.Flags().StringVar(&ownerRaw, "owner", "", "Address of the new owner")
+	addCmd.Flags().StringVar(&thresholdRaw, "threshold", "", "Threshold after adding the owner (defaults to the current threshold)")
+	addCmd.MarkFlagRequired("owner")
+
+	return addCmd
+}
+
+func createRemoveSafeOwnerCmd() *cobra.Command {
+	var ownerRaw, thresholdRaw string
+	var threshold *uint64
+
+	validate := func() error {
+		if !common.IsHexAddress(ownerRaw) {
+			return fmt.Errorf("invalid owner address: %s", ownerRaw)
+		}
+		var err error
+		threshold, err = parseThresholdFlag(thresholdRaw)
+		return err
+	}
+	removeCmd := createSafeSettingsCmd("remove", "Propose removing an owner", validate, func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error) {
+		state, err := GetSafeOwnerState(safeAddress, client)
+		if err != nil {
+			return nil, err
+		}
+		return NewRemoveOwnerChange(state, common.HexToAddress(ownerRaw), threshold)
+	})
+	removeCmd.Flags().StringVar(&ownerRaw, "owner", "", "Address of the owner to remove")
+	removeCmd.Flags().StringVar(&thresholdRaw, "threshold", "", "Threshold after removing the owner (defaults to the current threshold, capped at the remaining owner count)")
+	removeCmd.MarkFlagRequired("owner")
+
+	return removeCmd
+}
+
+func createSwapSafeOwnerCmd() *cobra.Command {
+	var oldOwnerRaw, newOwnerRaw string
+
+	validate := func() error {
+		if !common.IsHexAddress(oldOwnerRaw) {
+			return fmt.Errorf("invalid old owner address: %s", oldOwnerRaw)
+		}
+		if !common.IsHexAddress(newOwnerRaw) {
+			return fmt.Errorf("invalid new owner address: %s", newOwnerRaw)
+		}
+		return nil
+	}
+	swapCmd := createSafeSettingsCmd("swap", "Propose replacing an owner with another address", validate, func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error) {
+		state, err := GetSafeOwnerState(safeAddress, client)
+		if err != nil {
+			return nil, err
+		}
+		return NewSwapOwnerChange(safeAddress, state, common.HexToAddress(oldOwnerRaw), common.HexToAddress(newOwnerRaw))
+	})
+	swapCmd.Flags().StringVar(&oldOwnerRaw, "old-owner", "", "Address of the owner to replace")
+	swapCmd.Flags().StringVar(&newOwnerRaw, "new-owner", "", "Address of the new owner")
+	swapCmd.MarkFlagRequired("old-owner")
+	swapCmd.MarkFlagRequired("new-owner")
+
+	return swapCmd
+}
+
+func createChangeSafeThresholdCmd() *cobra.Command {
+	var threshold uint64
+
+	thresholdCmd := createSafeSettingsCmd("threshold", "Propose changing the threshold", nil, func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error) {
+		state, err := GetSafeOwnerState(safeAddress, client)
+		if err != nil {
+			return nil, err
+		}
+		return NewChangeThresholdChange(state, threshold)
+	})
+	thresholdCmd.Flags().Uint64Var(&threshold, "threshold", 0, "New threshold")
+	thresholdCmd.MarkFlagRequired("threshold")
+
+	return thresholdCmd
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPrevOwner(t *testing.T) {
+	a := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	b := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	c := common.HexToAddress("0x000000000000000000000000000000000000000c")
+	state := &SafeOwnerState{Owners: []common.Address{a, b, c}, Threshold: 2}
+
+	cases := []struct {
+		name  string
+		owner common.Address
+		want  common.Address
+	}{
+		{"first owner", a, SafeSentinelAddress},
+		{"middle owner", b, a},
+		{"last owner", c, b},
+	}
+	for _, tc := range cases {
+		got, err := state.PrevOwner(tc.owner)
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+		} else if got != tc.want {
+			t.Errorf("%s: prevOwner %s, want %s", tc.name, got.Hex(), tc.want.Hex())
+		}
+	}
+
+	if _, err := state.PrevOwner(common.HexToAddress("0x000000000000000000000000000000000000000d")); err == nil {
+		t.Error("found a prevOwner for a non-owner")
+	}
+	if _, err := state.PrevOwner(SafeSentinelAddress); err == nil {
+		t.Error("found a prevOwner for the sentinel")
+	}
+}
+
+func TestSafeOwnerChanges(t *testing.T) {
+	safeAddress := common.HexToAddress("0x5afe000000000000000000000000000000000000")
+	a := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	b := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	c := common.HexToAddress("0x000000000000000000000000000000000000000c")
+	d := common.HexToAddress("0x000000000000000000000000000000000000000d")
+	threshold := func(n uint64) *uint64 { return &n }
+	twoOfThree := &SafeOwnerState{Owners: []common.Address{a, b, c}, Threshold: 2}
+	twoOfTwo := &SafeOwnerState{Owners: []common.Address{a, b}, Threshold: 2}
+	threeOfThree := &SafeOwnerState{Owners: []common.Address{a, b, c}, Threshold: 3}
+
+	cases := []struct {
+		name    string
+		change  func() (*SafeSettingsChange, error)
+		wantErr bool
+		after   string
+		warns   bool
+	}{
+		{name: "add keeping threshold", change: func() (*SafeSettingsChange, error) { return NewAddOwnerChange(safeAddress, twoOfThree, d, nil) }, after: "Threshold 2 of 4 owners"},
+		{name: "add raising threshold to owner count", change: func() (*SafeSettingsChange, error) {
+			return NewAddOwnerChange(safeAddress, twoOfThree, d, threshold(4))
+		}, after: "Threshold 4 of 4 owners"},
+		{name: "add with threshold above owner count", change: func() (*SafeSettingsChange, error) {
+			return NewAddOwnerChange(safeAddress, twoOfThree, d, threshold(5))
+		}, wantErr: true},
+		{name: "add with threshold 0", change: func() (*SafeSettingsChange, error) {
+			return NewAddOwnerChange(safeAddress, twoOfThree, d, threshold(0))
+		}, wantErr: true},
+		{name: "add existing owner", change: func() (*SafeSettingsChange, error) { return NewAddOwnerChange(safeAddress, twoOfThree, b, nil) }, wantErr: true},
+		{name: "add the Safe itself", change: func() (*SafeSettingsChange, error) {
+			return NewAddOwnerChange(safeAddress, twoOfThree, safeAddress, nil)
+		}, wantErr: true},
+		{name: "add the sentinel", change: func() (*SafeSettingsChange, error) {
+			return NewAddOwnerChange(safeAddress, twoOfThree, SafeSentinelAddress, nil)
+		}, wantErr: true},
+		{name: "remove keeping threshold", change: func() (*SafeSettingsChange, error) { return NewRemoveOwnerChange(twoOfThree, b, nil) }, after: "Threshold 2 of 2 owners"},
+		{name: "remove lowering threshold to owner count", change: func() (*SafeSettingsChange, error) { return NewRemoveOwnerChange(threeOfThree, a, nil) }, after: "Threshold 2 of 2 owners"},
+		{name: "remove with threshold above owner count", change: func() (*SafeSettingsChange, error) { return NewRemoveOwnerChange(twoOfThree, c, threshold(3)) }, wantErr: true},
+		{name: "remove with threshold 0", change: func() (*SafeSettingsChange, error) { return NewRemoveOwnerChange(twoOfThree, c, threshold(0)) }, wantErr: true},
+		{name: "remove non-owner", change: func() (*SafeSettingsChange, error) { return NewRemoveOwnerChange(twoOfThree, d, nil) }, wantErr: true},
+		{name: "remove only owner", change: func() (*SafeSettingsChange, error) {
+			return NewRemoveOwnerChange(&SafeOwnerState{Owners: []common.Address{a}, Threshold: 1}, a, nil)
+		}, wantErr: true},
+		{name: "remove dropping threshold to 1", change: func() (*SafeSettingsChange, error) { return NewRemoveOwnerChange(twoOfTwo, a, nil) }, after: "Threshold 1 of 1 owners", warns: true},
+		{name: "swap", change: func() (*SafeSettingsChange, error) { return NewSwapOwnerChange(safeAddress, twoOfThree, b, d) }, after: "Threshold 2 of 3 owners"},
+		{name: "swap non-owner", change: func() (*SafeSettingsChange, error) { return NewSwapOwnerChange(safeAddress, twoOfThree, d, a) }, wantErr: true},
+		{name: "swap for existing owner", change: func() (*SafeSettingsChange, error) { return NewSwapOwnerChange(safeAddress, twoOfThree, b, c) }, wantErr: true},
+		{name: "change threshold to 1", change: func() (*SafeSettingsChange, error) { return NewChangeThresholdChange(twoOfThree, 1) }, after: "Threshold 1 of 3 owners", warns: true},
+		{name: "change threshold to 3", change: func() (*SafeSettingsChange, error) { return NewChangeThresholdChange(twoOfThree, 3) }, after: "Threshold 3 of 3 owners"},
+		{name: "change threshold above owner count", change: func() (*SafeSettingsChange, error) { return NewChangeThresholdChange(twoOfThree, 4) }, wantErr: true},
+		{name: "change threshold to 0", change: func() (*SafeSettingsChange, error) { return NewChangeThresholdChange(twoOfThree, 0) }, wantErr: true},
+		{name: "change threshold to current", change: func() (*SafeSettingsChange, error) { return NewChangeThresholdChange(twoOfThree, 2) }, wantErr: true},
+	}
+	for _, tc := range cases {
+		change, err := tc.change()
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tc.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		if change.After[0] != tc.after {
+			t.Errorf("%s: after %q, want %q", tc.name, change.After[0], tc.after)
+		}
+		warned := false
+		for _, warning := range change.Warnings {
+			if strings.Contains(warning, "threshold drops to 1") {
+				warned = true
+			}
+		}
+		if warned != tc.warns {
+			t.Errorf("%s: threshold warning %v, want %v (%v)", tc.name, warned, tc.warns, change.Warnings)
+		}
+	}
+}
+
+// Executes owner changes on a Safe, checking that the looked-up prevOwner is accepted and that the resulting owner
+// list is the one described by the change.
+func TestSafeOwnerChangesOnChain(t *testing.T) {
+	chain := newTestChain(t)
+	keys, owners := testKeys(t, 4)
+	_, newOwners := testKeys(t, 2)
+	safeAddress := chain.deploySafe(t, owners, 1)
+	safeInstance, err := Safe.NewSafe(safeAddress, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := func() *SafeOwnerState {
+		current, err := safeInstance.GetOwners(&bind.CallOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		threshold, err := safeInstance.GetThreshold(&bind.CallOpts{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &SafeOwnerState{Owners: current, Threshold: threshold.Uint64()}
+	}
+
+	steps := []struct {
+		name   string
+		change func(*SafeOwnerState) (*SafeSettingsChange, error)
+	}{
+		{"remove first owner", func(s *SafeOwnerState) (*SafeSettingsChange, error) { return NewRemoveOwnerChange(s, s.Owners[0], nil) }},
+		{"remove last owner", func(s *SafeOwnerState) (*SafeSettingsChange, error) {
+			return NewRemoveOwnerChange(s, s.Owners[len(s.Owners)-1], nil)
+		}},
+		{"add owner", func(s *SafeOwnerState) (*SafeSettingsChange, error) {
+			return NewAddOwnerChange(safeAddress, s, newOwners[0], nil)
+		}},
+		{"swap middle owner", func(s *SafeOwnerState) (*SafeSettingsChange, error) {
+			return NewSwapOwnerChange(safeAddress, s, s.Owners[1], newOwners[1])
+		}},
+		{"remove middle owner", func(s *SafeOwnerState) (*SafeSettingsChange, error) { return NewRemoveOwnerChange(s, s.Owners[1], nil) }},
+		{"swap first owner", func(s *SafeOwnerState) (*SafeSettingsChange, error) {
+			return NewSwapOwnerChange(safeAddress, s, s.Owners[0], owners[0])
+		}},
+	}
+	for _, step := range steps {
+		before := state()
+		change, err := step.change(before)
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+
+		// Any current owner among the original keys can sign, since the threshold is 1.
+		signer := -1
+		for i, owner := range owners {
+			if before.IsOwner(owner) {
+				signer = i
+				break
+			}
+		}
+		if signer < 0 {
+			t.Fatalf("%s: no original owner left to sign", step.name)
+		}
+		if err := chain.execSelfCall(t, safeAddress, keys[signer:signer+1], change.Data); err != nil {
+			t.Fatalf("%s: execution failed: %v", step.name, err)
+		}
+
+		after := state()
+		if got := after.Describe(); strings.Join(got, "\n") != strings.Join(change.After, "\n") {
+			t.Errorf("%s: Safe has\n%s\nwant\n%s", step.name, strings.Join(got, "\n"), strings.Join(change.After, "\n"))
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/big"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/G7DAO/safes/bindings/SafeProxyFactory"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// Returns the salt nonce the factory actually uses. createProxyWithCallback mixes the callback into the salt nonce
+// as uint256(keccak256(abi.encodePacked(saltNonce, callback))).
+func (creation SafeCreation) effectiveSaltNonce() []byte {
+	saltNonce := common.LeftPadBytes(creation.SaltNonce.Bytes(), 32)
+	if creation.Callback == (common.Address{}) {
+		return saltNonce
+	}
+	return crypto.Keccak256(saltNonce, creation.Callback.Bytes())
+}
+
+// Computes the CREATE2 salt as SafeProxyFactory does: keccak256(abi.encodePacked(keccak256(initializer), saltNonce)),
+// with the chain ID appended for chain-specific creations.
+func (creation SafeCreation) Salt(chainID *big.Int) (common.Hash, error) {
+	initializer, err := creation.Setup.Initializer()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if creation.ChainSpecific {
+		return crypto.Keccak256Hash(crypto.Keccak256(initializer), creation.effectiveSaltNonce(), common.LeftPadBytes(chainID.Bytes(), 32)), nil
+	}
+	return crypto.Keccak256Hash(crypto.Keccak256(initializer), creation.effectiveSaltNonce()), nil
+}
+
+// Returns the hash of the proxy deployment data, abi.encodePacked(proxyCreationCode, uint256(uint160(singleton))).
+func proxyInitCodeHash(proxyCreationCode []byte, singleton common.Address) []byte {
+	return crypto.Keccak256(proxyCreationCode, common.LeftPadBytes(singleton.Bytes(), 32))
+}
+
+// Predicts the address of the proxy the factory would deploy for a creation. proxyCreationCode is the factory's
+// proxyCreationCode() and chainID is only used for chain-specific creations.
+func PredictSafeAddress(creation SafeCreation, proxyCreationCode []byte, chainID *big.Int) (common.Address, error) {
+	salt, err := creation.Salt(chainID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.CreateAddress2(creation.Factory, salt, proxyInitCodeHash(proxyCreationCode, creation.Singleton)), nil
+}
+
+func GetProxyCreationCode(factoryAddress common.Address, client *ethclient.Client) ([]byte, error) {
+	factory, err := SafeProxyFactory.NewSafeProxyFactory(factoryAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SafeProxyFactory instance: %w", err)
+	}
+	code, err := factory.ProxyCreationCode(&bind.CallOpts{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch proxyCreationCode from factory %s: %w", factoryAddress.Hex(), err)
+	}
+	return code, nil
+}
+
+// Predicts the address of a creation on the client's chain, using the proxy creation code of the deployed factory.
+func PredictSafeAddressOnChain(creation SafeCreation, client *ethclient.Client) (common.Address, error) {
+	chainID, err := client.ChainID(context.Background())
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	proxyCreationCode, err := GetProxyCreationCode(creation.Factory, client)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return PredictSafeAddress(creation, proxyCreationCode, chainID)
+}
+
+// Parses a hex address prefix such as "0xdead" into nibbles.
+func parseVanityPrefix(prefix string) ([]byte, error) {
+	prefix = strings.ToLower(strings.TrimPrefix(prefix, "0x"))
+	if len(prefix) == 0 || len(prefix) > 40 {
+		return nil, fmt.Errorf("vanity prefix must have between 1 and 40 hex digits")
+	}
+	nibbles := make([]byte, len(prefix))
+	for i, c := range prefix {
+		value, err := hex.DecodeString("0" + string(c))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex digit %q in vanity prefix", c)
+		}
+		nibbles[i] = value[0]
+	}
+	return nibbles, nil
+}
+
+func hasNibblePrefix(address []byte, nibbles []byte) bool {
+	for i, nibble := range nibbles {
+		b := address[i/2]
+		if i%2 == 0 {
+			b >>= 4
+		}
+		if b&0x0f != nibble {
+			return false
+		}
+	}
+	return true
+}
+
+// VanitySearchResult is a salt nonce giving a Safe address with the requested prefix.
+type VanitySearchResult struct {
+	SaltNonce *big.Int
+	Address   common.Address
+	Attempts  uint64
+}
+
+// Searches salt nonces, starting from creation.SaltNonce, for one that gives an address starting with prefix. The
+// search is spread over workers goroutines and gives up after maxAttempts nonces, or never if maxAttempts is 0.
+func SearchVanitySaltNonce(creation SafeCreation, proxyCreationCode []byte, chainID *big.Int, prefix string, workers int, maxAttempts uint64) (*VanitySearchResult, error) {
+	nibbles, err := parseVanityPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+
+	initializer, err := creation.Setup.Initializer()
+	if err != nil {
+		return nil, err
+	}
+	initializerHash := crypto.Keccak256(initializer)
+	initCodeHash := proxyInitCodeHash(proxyCreationCode, creation.Singleton)
+	var chainIDBytes []byte
+	if creation.ChainSpecific {
+		chainIDBytes = common.LeftPadBytes(chainID.Bytes(), 32)
+	}
+
+	var (
+		attempts atomic.Uint64
+		done     atomic.Bool
+		found    *VanitySearchResult
+		foundMu  sync.Mutex
+		wg       sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(offset int) {
+			defer wg.Done()
+
+			candidate := creation
+			candidate.SaltNonce = new(big.Int).Add(creation.SaltNonce, big.NewInt(int64(offset)))
+			step := big.NewInt(int64(workers))
+			for !done.Load() {
+				n := attempts.Add(1)
+				if maxAttempts > 0 && n > maxAttempts {
+					return
+				}
+
+				var salt []byte
+				if creation.ChainSpecific {
+					salt = crypto.Keccak256(initializerHash, candidate.effectiveSaltNonce(), chainIDBytes)
+				} else {
+					salt = crypto.Keccak256(initializerHash, candidate.effectiveSaltNonce())
+				}
+				address := crypto.CreateAddress2(creation.Factory, common.BytesToHash(salt), initCodeHash)
+				if hasNibblePrefix(address.Bytes(), nibbles) {
+					foundMu.Lock()
+					if found == nil || candidate.SaltNonce.Cmp(found.SaltNonce) < 0 {
+						found = &VanitySearchResult{SaltNonce: new(big.Int).Set(candidate.SaltNonce), Address: address}
+					}
+					foundMu.Unlock()
+					done.Store(true)
+					return
+				}
+
+				candidate.SaltNonce = new(big.Int).Add(candidate.SaltNonce, step)
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	total := attempts.Load()
+	if maxAttempts > 0 && total > maxAttempts {
+		total = maxAttempts
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no address with prefix %s found after %d salt nonces", prefix, total)
+	}
+	found.Attempts = total
+	return found, nil
+}
+
+func createSafePredictCmd() *cobra.Command {
+	var (
+		creationFlags safeCreationFlags
+		vanityPrefix  string
+		workers       int
+		maxAttempts   uint64
+		save          bool
+		label         string
+	)
+
+	predictCmd := &cobra.Command{
+		Use:   "predict",
+		Short: "Predict the address of a Safe before it is deployed",
+		Long: `Predict the CREATE2 address at which the factory would deploy a Safe with the given parameters, computed from the
+factory's proxyCreationCode, the singleton, the setup initializer and the salt nonce exactly as SafeProxyFactory does.
+
+With --vanity-prefix, salt nonces are searched from --salt-nonce upwards, on every CPU core, for an address starting
+with the given hex digits. Each extra digit makes the search 16 times longer.
+
+With --save, the Safe is recorded as a counterfactual Safe so that it can be deployed later with "safe counterfactual
+deploy", using exactly the same parameters.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if vanityPrefix != "" {
+				if _, err := parseVanityPrefix(vanityPrefix); err != nil {
+					return err
+				}
+			}
+			return creationFlags.Validate()
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			creation, err := creationFlags.Creation(client)
+			if err != nil {
+				return err
+			}
+			if err := CheckSafeCreationContracts(creation, client); err != nil {
+				return err
+			}
+
+			proxyCreationCode, err := GetProxyCreationCode(creation.Factory, client)
+			if err != nil {
+				return err
+			}
+
+			if vanityPrefix != "" {
+				nibbles, _ := parseVanityPrefix(vanityPrefix)
+				cmd.Printf("Searching for an address starting with 0x%s (about %.0f attempts expected)...\n", strings.TrimPrefix(strings.ToLower(vanityPrefix), "0x"), math.Pow(16, float64(len(nibbles))))
+				result, err := SearchVanitySaltNonce(creation, proxyCreationCode, chainID, vanityPrefix, workers, maxAttempts)
+				if err != nil {
+					return err
+				}
+				cmd.Printf("Found salt nonce %s after %d attempts\n", result.SaltNonce.String(), result.Attempts)
+				creation.SaltNonce = result.SaltNonce
+			}
+
+			address, err := PredictSafeAddress(creation, proxyCreationCode, chainID)
+			if err != nil {
+				return err
+			}
+
+			PrintSafeCreation(creation)
+			cmd.Println("Predicted Safe address:", address.Hex())
+
+			deployed, err := hasCode(address, client)
+			if err != nil {
+				return err
+			}
+			if deployed {
+				cmd.Println("A contract is already deployed at this address")
+			}
+
+			if save {
+				path, err := AddCounterfactualSafe(NewCounterfactualSafe(creation, address, chainID, label))
+				if err != nil {
+					return fmt.Errorf("failed to save counterfactual Safe: %v", err)
+				}
+				cmd.Printf("Counterfactual Safe saved to %s\n", path)
+			}
+			return nil
+		},
+	}
+
+	addSafeCreationFlags(predictCmd, &creationFlags)
+	predictCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of the chain, used to read the factory's proxy creation code and the chain ID")
+	predictCmd.Flags().StringVar(&vanityPrefix, "vanity-prefix", "", "Search for a salt nonce giving an address starting with these hex digits")
+	predictCmd.Flags().IntVar(&workers, "workers", runtime.NumCPU(), "Number of goroutines used by the vanity search")
+	predictCmd.Flags().Uint64Var(&maxAttempts, "max-attempts", 0, "Give up the vanity search after this many salt nonces (0 for no limit)")
+	predictCmd.Flags().BoolVar(&save, "save", false, "Record the predicted Safe as a counterfactual Safe to deploy later")
+	predictCmd.Flags().StringVar(&label, "label", "", "Label stored with a saved counterfactual Safe")
+	predictCmd.MarkFlagRequired("rpc")
+
+	return predictCmd
+}
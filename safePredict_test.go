@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/G7DAO/safes/bindings/SafeProxyFactory"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Deploys a creation through the factory on the simulated chain and returns the address of the new proxy.
+func (c *testChain) createSafe(t *testing.T, creation SafeCreation) common.Address {
+	t.Helper()
+	initializer, err := creation.Setup.Initializer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	factory, err := SafeProxyFactory.NewSafeProxyFactory(creation.Factory, c.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tx *types.Transaction
+	switch {
+	case creation.Callback != (common.Address{}):
+		tx, err = factory.CreateProxyWithCallback(c.deployer, creation.Singleton, initializer, creation.SaltNonce, creation.Callback)
+	case creation.ChainSpecific:
+		tx, err = factory.CreateChainSpecificProxyWithNonce(c.deployer, creation.Singleton, initializer, creation.SaltNonce)
+	default:
+		tx, err = factory.CreateProxyWithNonce(c.deployer, creation.Singleton, initializer, creation.SaltNonce)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.backend.Commit()
+
+	receipt, err := c.client.TransactionReceipt(context.Background(), tx.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("creation transaction %s reverted", tx.Hash().Hex())
+	}
+	address, err := SafeAddressFromReceipt(receipt, creation.Factory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return address
+}
+
+func proxyCreationCodeOf(t *testing.T, chain *testChain, factoryAddress common.Address) []byte {
+	t.Helper()
+	factory, err := SafeProxyFactory.NewSafeProxyFactory(factoryAddress, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := factory.ProxyCreationCode(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestPredictSafeAddress(t *testing.T) {
+	chain := newTestChain(t)
+	factory, singleton := chain.deploySafeFactory(t)
+	proxyCreationCode := proxyCreationCodeOf(t, chain, factory)
+	_, owners := testKeys(t, 3)
+
+	setup := SafeSetupParams{
+		Owners:          owners,
+		Threshold:       2,
+		FallbackHandler: common.HexToAddress("0xfd0732Dc9E303f09fCEf3a7388Ad10A83459Ec99"),
+	}
+	// The singleton has a fallback function, so it accepts the proxyCreated notification of a callback creation.
+	variants := map[string]SafeCreation{
+		"plain":          {Factory: factory, Singleton: singleton, Setup: setup, SaltNonce: big.NewInt(7)},
+		"callback":       {Factory: factory, Singleton: singleton, Setup: setup, SaltNonce: big.NewInt(7), Callback: singleton},
+		"chain-specific": {Factory: factory, Singleton: singleton, Setup: setup, SaltNonce: big.NewInt(7), ChainSpecific: true},
+	}
+
+	predictions := make(map[common.Address]string)
+	for name, creation := range variants {
+		predicted, err := PredictSafeAddress(creation, proxyCreationCode, simulatedChainID)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if other, ok := predictions[predicted]; ok {
+			t.Errorf("%s and %s predict the same address %s", name, other, predicted.Hex())
+		}
+		predictions[predicted] = name
+
+		if deployed := chain.createSafe(t, creation); deployed != predicted {
+			t.Errorf("%s: predicted %s, factory deployed %s", name, predicted.Hex(), deployed.Hex())
+		}
+	}
+}
+
+func TestSearchVanitySaltNonce(t *testing.T) {
+	chain := newTestChain(t)
+	factory, singleton := chain.deploySafeFactory(t)
+	proxyCreationCode := proxyCreationCodeOf(t, chain, factory)
+	_, owners := testKeys(t, 1)
+
+	variants := map[string]SafeCreation{
+		"plain":          {Factory: factory, Singleton: singleton, Setup: SafeSetupParams{Owners: owners, Threshold: 1}, SaltNonce: big.NewInt(0)},
+		"chain-specific": {Factory: factory, Singleton: singleton, Setup: SafeSetupParams{Owners: owners, Threshold: 1}, SaltNonce: big.NewInt(0), ChainSpecific: true},
+	}
+	for name, creation := range variants {
+		result, err := SearchVanitySaltNonce(creation, proxyCreationCode, simulatedChainID, "0x5afe", 4, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !strings.HasPrefix(strings.ToLower(result.Address.Hex()), "0x5afe") {
+			t.Errorf("%s: found %s without the prefix", name, result.Address.Hex())
+		}
+
+		creation.SaltNonce = result.SaltNonce
+		predicted, err := PredictSafeAddress(creation, proxyCreationCode, simulatedChainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if predicted != result.Address {
+			t.Errorf("%s: salt nonce %s predicts %s, search reported %s", name, result.SaltNonce.String(), predicted.Hex(), result.Address.Hex())
+		}
+		if deployed := chain.createSafe(t, creation); deployed != result.Address {
+			t.Errorf("%s: salt nonce %s deployed %s, search reported %s", name, result.SaltNonce.String(), deployed.Hex(), result.Address.Hex())
+		}
+	}
+
+	creation := variants["plain"]
+	if _, err := SearchVanitySaltNonce(creation, proxyCreationCode, simulatedChainID, "0x5afe5afe5afe", 2, 100); err == nil {
+		t.Error("search succeeded beyond its attempt limit")
+	}
+	if _, err := SearchVanitySaltNonce(creation, proxyCreationCode, simulatedChainID, "0xnope", 2, 100); err == nil {
+		t.Error("search accepted an invalid prefix")
+	}
+}
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/bindings/SafeProxyFactory"
+	"github.com/G7DAO/safes/signer"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// Decodes a setup call, the initializer of a Safe proxy.
+func DecodeSafeSetup(initializer []byte) (SafeSetupParams, error) {
+	safeAbi, err := Safe.SafeMetaData.GetAbi()
+	if err != nil {
+		return SafeSetupParams{}, fmt.Errorf("failed to get Safe ABI: %w", err)
+	}
+	setup := safeAbi.Methods["setup"]
+	if len(initializer) < 4 || !bytes.Equal(initializer[:4], setup.ID) {
+		return SafeSetupParams{}, fmt.Errorf("initializer is not a Safe setup call")
+	}
+
+	values, err := setup.Inputs.Unpack(initializer[4:])
+	if err != nil {
+		return SafeSetupParams{}, fmt.Errorf("failed to decode setup call: %w", err)
+	}
+	params := SafeSetupParams{
+		Owners:          values[0].([]common.Address),
+		Threshold:       values[1].(*big.Int).Uint64(),
+		To:              values[2].(common.Address),
+		Data:            values[3].([]byte),
+		FallbackHandler: values[4].(common.Address),
+		PaymentToken:    values[5].(common.Address),
+		Payment:         values[6].(*big.Int),
+		PaymentReceiver: values[7].(common.Address),
+	}
+
+	// Re-encoding must give back the initializer exactly, otherwise a replay would not reach the same address.
+	encoded, err := params.Initializer()
+	if err != nil {
+		return SafeSetupParams{}, err
+	}
+	if !bytes.Equal(encoded, initializer) {
+		return SafeSetupParams{}, fmt.Errorf("setup call uses a non-canonical encoding that cannot be replayed")
+	}
+	return params, nil
+}
+
+// Recovers the SafeCreation from a transaction that called a SafeProxyFactory directly.
+func SafeCreationFromTransaction(tx *types.Transaction, factoryAddress common.Address) (SafeCreation, error) {
+	if tx.To() == nil || *tx.To() != factoryAddress {
+		return SafeCreation{}, fmt.Errorf("transaction %s does not call the factory %s directly, so its creation parameters cannot be recovered", tx.Hash().Hex(), factoryAddress.Hex())
+	}
+
+	factoryAbi, err := SafeProxyFactory.SafeProxyFactoryMetaData.GetAbi()
+	if err != nil {
+		return SafeCreation{}, fmt.Errorf("failed to get SafeProxyFactory ABI: %w", err)
+	}
+	input := tx.Data()
+	if len(input) < 4 {
+		return SafeCreation{}, fmt.Errorf("transaction %s has no calldata", tx.Hash().Hex())
+	}
+	method, err := factoryAbi.MethodById(input[:4])
+	if err != nil {
+		return SafeCreation{}, fmt.Errorf("transaction %s does not call a known factory method: %w", tx.Hash().Hex(), err)
+	}
+	values, err := method.Inputs.Unpack(input[4:])
+	if err != nil {
+		return SafeCreation{}, fmt.Errorf("failed to decode %s call: %w", method.Name, err)
+	}
+
+	creation := SafeCreation{Factory: factoryAddress}
+	switch method.Name {
+	case "createProxyWithNonce", "createChainSpecificProxyWithNonce", "createProxyWithCallback":
+		creation.Singleton = values[0].(common.Address)
+		creation.SaltNonce = values[2].(*big.Int)
+		creation.ChainSpecific = method.Name == "createChainSpecificProxyWithNonce"
+		if method.Name == "createProxyWithCallback" {
+			creation.Callback = values[3].(common.Address)
+		}
+		creation.Setup, err = DecodeSafeSetup(values[1].([]byte))
+		if err != nil {
+			return SafeCreation{}, err
+		}
+	default:
+		return SafeCreation{}, fmt.Errorf("transaction %s calls %s, which does not create a Safe", tx.Hash().Hex(), method.Name)
+	}
+	return creation, nil
+}
+
+// Number of blocks searched for a ProxyCreation event when no end block is given.
+const ProxyCreationSearchBlocks = 10000
+
+// Finds the ProxyCreation event of a Safe and returns it with the Safe's address. If txHash is set the event is
+// taken from that transaction's receipt, otherwise the logs from fromBlock to toBlock are searched for the Safe's
+// creation. The proxy is not indexed by the 1.3.0 factory, so the search cannot filter on it and matches the
+// ProxyCreation events of the range locally.
+func FindProxyCreationLog(client *ethclient.Client, txHash *common.Hash, safeAddress *common.Address, fromBlock uint64, toBlock uint64) (*types.Log, common.Address, error) {
+	var logs []*types.Log
+	if txHash != nil {
+		receipt, err := client.TransactionReceipt(context.Background(), *txHash)
+		if err != nil {
+			return nil, common.Address{}, fmt.Errorf("failed to fetch receipt of %s: %w", txHash.Hex(), err)
+		}
+		logs = receipt.Logs
+	} else {
+		query := ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(fromBlock),
+			ToBlock:   new(big.Int).SetUint64(toBlock),
+			Topics:    [][]common.Hash{{ProxyCreationTopic}},
+		}
+		found, err := client.FilterLogs(context.Background(), query)
+		if err != nil {
+			return nil, common.Address{}, fmt.Errorf("failed to search blocks %d to %d for the ProxyCreation event of %s (try --tx-hash or a smaller range): %w", fromBlock, toBlock, safeAddress.Hex(), err)
+		}
+		for i := range found {
+			logs = append(logs, &found[i])
+		}
+	}
+
+	match, address, err := MatchProxyCreationLog(logs, safeAddress)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+	if match == nil {
+		if safeAddress != nil {
+			return nil, common.Address{}, fmt.Errorf("no ProxyCreation event found for %s in blocks %d to %d", safeAddress.Hex(), fromBlock, toBlock)
+		}
+		return nil, common.Address{}, fmt.Errorf("no ProxyCreation event found in transaction %s", txHash.Hex())
+	}
+	return match, address, nil
+}
+
+// Returns the ProxyCreation event among logs that created safeAddress, or the only one if safeAddress is nil.
+// Returns a nil log if there is none.
+func MatchProxyCreationLog(logs []*types.Log, safeAddress *common.Address) (*types.Log, common.Address, error) {
+	var match *types.Log
+	var matchAddress common.Address
+	for _, log := range logs {
+		if len(log.Topics) == 0 || log.Topics[0] != ProxyCreationTopic {
+			continue
+		}
+		// Other contracts may emit an event with the same signature but a different layout.
+		address, err := ProxyCreationAddress(log)
+		if err != nil {
+			continue
+		}
+		if safeAddress != nil && address != *safeAddress {
+			continue
+		}
+		if match != nil {
+			return nil, common.Address{}, fmt.Errorf("transaction creates several Safes, use --safe to choose one")
+		}
+		match, matchAddress = log, address
+	}
+	return match, matchAddress, nil
+}
+
+// Recovers how a Safe was created from its creation transaction, and checks that the recovered parameters predict
+// its address on the source chain.
+func RecoverSafeCreation(client *ethclient.Client, txHash *common.Hash, safeAddress *common.Address, fromBlock uint64, toBlock uint64) (SafeCreation, common.Address, error) {
+	log, address, err := FindProxyCreationLog(client, txHash, safeAddress, fromBlock, toBlock)
+	if err != nil {
+		return SafeCreation{}, common.Address{}, err
+	}
+
+	tx, _, err := client.TransactionByHash(context.Background(), log.TxHash)
+	if err != nil {
+		return SafeCreation{}, common.Address{}, fmt.Errorf("failed to fetch transaction %s: %w", log.TxHash.Hex(), err)
+	}
+	creation, err := SafeCreationFromTransaction(tx, log.Address)
+	if err != nil {
+		return SafeCreation{}, common.Address{}, err
+	}
+
+	predicted, err := PredictSafeAddressOnChain(creation, client)
+	if err != nil {
+		return SafeCreation{}, common.Address{}, err
+	}
+	if predicted != address {
+		return SafeCreation{}, common.Address{}, fmt.Errorf("recovered parameters predict %s, but the transaction created %s", predicted.Hex(), address.Hex())
+	}
+	return creation, address, nil
+}
+
+// Checks that a Safe creation can be replayed from one chain onto another at the same address: the factory and
+// singleton must have identical bytecode, and the contracts used during setup must exist on the target chain.
+func CheckSafeReplication(creation SafeCreation, safeAddress common.Address, source *ethclient.Client, target *ethclient.Client) error {
+	if creation.ChainSpecific {
+		return fmt.Errorf("%s was created with createChainSpecificProxyWithNonce, so it cannot have the same address on another chain", safeAddress.Hex())
+	}
+
+	for _, contract := range []struct {
+		name    string
+		address common.Address
+	}{
+		{"factory", creation.Factory},
+		{"singleton", creation.Singleton},
+	} {
+		sourceHash, err := codeHash(contract.address, source)
+		if err != nil {
+			return err
+		}
+		targetHash, err := codeHash(contract.address, target)
+		if err != nil {
+			return err
+		}
+		if targetHash != sourceHash {
+			return fmt.Errorf("%s %s has different bytecode on the target chain", contract.name, contract.address.Hex())
+		}
+	}
+
+	if err := CheckSafeCreationContracts(creation, target); err != nil {
+		return err
+	}
+	for _, contract := range []struct {
+		name    string
+		address common.Address
+	}{
+		{"setup call target", creation.Setup.To},
+		{"callback", creation.Callback},
+		{"payment token", creation.Setup.PaymentToken},
+	} {
+		if contract.address == (common.Address{}) {
+			continue
+		}
+		code, err := hasCode(contract.address, target)
+		if err != nil {
+			return err
+		}
+		if !code {
+			return fmt.Errorf("%s %s has no code on the target chain", contract.name, contract.address.Hex())
+		}
+	}
+	return nil
+}
+
+func createSafeReplicateCmd() *cobra.Command {
+	var (
+		fromChain string
+		toChain   string
+		txHashRaw string
+		safe      string
+		fromBlock uint64
+		toBlock   uint64
+		keyfile   string
+		password  string
+		yes       bool
+	)
+
+	replicateCmd := &cobra.Command{
+		Use:   "replicate",
+		Short: "Deploy an existing Safe at the same address on another chain",
+		Long: `Deploy an existing Safe at the same address on another chain by replaying its creation. The creation
+transaction is found from --tx-hash, or with --safe from the Safe's ProxyCreation event between --from-block and
+--to-block. Its factory call is decoded to recover the singleton, setup and salt nonce. The factory and singleton must
+have identical bytecode on the target chain, and the predicted address must match before anything is sent.
+
+The replica is created with the Safe's original owners and threshold. If they have changed since, the same changes
+must be made again on the target chain.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if (txHashRaw == "") == (safe == "") {
+				return fmt.Errorf("exactly one of --tx-hash and --safe must be specified")
+			}
+			if safe != "" {
+				if !common.IsHexAddress(safe) {
+					return fmt.Errorf("invalid safe address: %s", safe)
+				}
+				if !cmd.Flags().Changed("from-block") {
+					return fmt.Errorf("--safe requires --from-block, the block from which to search for the Safe's creation")
+				}
+				if !cmd.Flags().Changed("to-block") {
+					toBlock = fromBlock + ProxyCreationSearchBlocks - 1
+				} else if toBlock < fromBlock {
+					return fmt.Errorf("--to-block %d is before --from-block %d", toBlock, fromBlock)
+				}
+			}
+			if keyfile == "" {
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sourceURL, err := ResolveChainRPC(fromChain)
+			if err != nil {
+				return err
+			}
+			targetURL, err := ResolveChainRPC(toChain)
+			if err != nil {
+				return err
+			}
+			source, err := ethclient.Dial(sourceURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the source chain: %v", err)
+			}
+			target, err := ethclient.Dial(targetURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the target chain: %v", err)
+			}
+			sourceChainID, err := source.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get source chain ID: %v", err)
+			}
+			targetChainID, err := target.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get target chain ID: %v", err)
+			}
+			if sourceChainID.Cmp(targetChainID) == 0 {
+				return fmt.Errorf("source and target are the same chain (%s)", sourceChainID.String())
+			}
+
+			var txHash *common.Hash
+			var safeAddress *common.Address
+			if txHashRaw != "" {
+				hash := common.HexToHash(txHashRaw)
+				txHash = &hash
+			} else {
+				address := common.HexToAddress(safe)
+				safeAddress = &address
+			}
+
+			creation, address, err := RecoverSafeCreation(source, txHash, safeAddress, fromBlock, toBlock)
+			if err != nil {
+				return err
+			}
+			if err := CheckSafeReplication(creation, address, source, target); err != nil {
+				return err
+			}
+
+			predicted, err := PredictSafeAddressOnChain(creation, target)
+			if err != nil {
+				return err
+			}
+			if predicted != address {
+				return fmt.Errorf("the creation would give %s on chain %s instead of %s", predicted.Hex(), targetChainID.String(), address.Hex())
+			}
+
+			deployed, err := hasCode(address, target)
+			if err != nil {
+				return err
+			}
+			if deployed {
+				cmd.Printf("%s is already deployed on chain %s\n", address.Hex(), targetChainID.String())
+				return VerifySafeSetup(address, creation.Setup, target)
+			}
+
+			PrintSafeCreation(creation)
+			cmd.Printf("Replicating %s from chain %s to chain %s\n", address.Hex(), sourceChainID.String(), targetChainID.String())
+
+			current, err := GetSafeOwnerState(address, source)
+			if err != nil {
+				return err
+			}
+			if !sameOwnerState(current, creation.Setup) {
+				cmd.Println("Warning: the Safe's owners or threshold have changed since it was created. The replica will have the original ones:")
+				for _, line := range current.Describe() {
+					cmd.Println("  current: " + line)
+				}
+			}
+			if creation.Setup.Payment != nil && creation.Setup.Payment.Sign() > 0 {
+				cmd.Println("Warning: the setup pays a fee from the Safe's balance, so the address must be funded on the target chain first")
+			}
+
+			if !yes {
+				ok, err := ConfirmPrompt("Deploy this Safe on the target chain?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("replication not confirmed")
+				}
+			}
+
+			key, err := signer.Open(keyfile, password)
+			if err != nil {
+				return err
+			}
+
+			created, err := CreateSafe(creation, key, targetChainID, target)
+			if err != nil {
+				return err
+			}
+			if created != address {
+				return fmt.Errorf("Safe was deployed at %s, not at %s", created.Hex(), address.Hex())
+			}
+			return nil
+		},
+	}
+
+	replicateCmd.Flags().StringVar(&fromChain, "from-chain", "", "Chain the Safe is deployed on, as an RPC URL or known chain name (g7, g7-testnet)")
+	replicateCmd.Flags().StringVar(&toChain, "to-chain", "", "Chain to deploy the Safe on, as an RPC URL or known chain name (g7, g7-testnet)")
+	replicateCmd.Flags().StringVar(&txHashRaw, "tx-hash", "", "Hash of the transaction that created the Safe")
+	replicateCmd.Flags().StringVar(&safe, "safe", "", "Safe address, whose ProxyCreation event is searched for on the source chain")
+	replicateCmd.Flags().Uint64Var(&fromBlock, "from-block", 0, "Block from which to search for the ProxyCreation event with --safe")
+	replicateCmd.Flags().Uint64Var(&toBlock, "to-block", 0, fmt.Sprintf("Last block to search for the ProxyCreation event with --safe (defaults to %d blocks from --from-block)", ProxyCreationSearchBlocks))
+	replicateCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer of the account submitting the creation: "+signer.SpecUsage)
+	replicateCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	replicateCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Deploy without asking for confirmation")
+	replicateCmd.MarkFlagRequired("from-chain")
+	replicateCmd.MarkFlagRequired("to-chain")
+	replicateCmd.MarkFlagRequired("keyfile")
+
+	return replicateCmd
+}
+
+func sameOwnerState(state *SafeOwnerState, setup SafeSetupParams) bool {
+	if state.Threshold != setup.Threshold || len(state.Owners) != len(setup.Owners) {
+		return false
+	}
+	for i := range setup.Owners {
+		if state.Owners[i] != setup.Owners[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestMatchProxyCreationLog(t *testing.T) {
+	singletonWord := common.LeftPadBytes(common.HexToAddress("0xd9Db270c1B5E3Bd161E8c8503c55cEABeE709552").Bytes(), 32)
+	indexed := func(proxy common.Address) *types.Log {
+		return &types.Log{Topics: []common.Hash{ProxyCreationTopic, common.BytesToHash(proxy.Bytes())}, Data: singletonWord}
+	}
+	unindexed := func(proxy common.Address) *types.Log {
+		return &types.Log{Topics: []common.Hash{ProxyCreationTopic}, Data: append(common.LeftPadBytes(proxy.Bytes(), 32), singletonWord...)}
+	}
+
+	safeA := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	safeB := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	other := &types.Log{Topics: []common.Hash{common.HexToHash("0x01")}, Data: singletonWord}
+	foreign := &types.Log{Topics: []common.Hash{ProxyCreationTopic}, Data: singletonWord}
+
+	cases := []struct {
+		name    string
+		logs    []*types.Log
+		safe    *common.Address
+		found   bool
+		wantErr bool
+	}{
+		{name: "1.4.1 by address", logs: []*types.Log{other, indexed(safeB), indexed(safeA)}, safe: &safeA, found: true},
+		{name: "1.3.0 by address", logs: []*types.Log{foreign, unindexed(safeB), unindexed(safeA)}, safe: &safeA, found: true},
+		{name: "only creation in transaction", logs: []*types.Log{other, unindexed(safeA)}, found: true},
+		{name: "several creations in transaction", logs: []*types.Log{indexed(safeA), unindexed(safeB)}, wantErr: true},
+		{name: "not found", logs: []*types.Log{other, foreign, indexed(safeB)}, safe: &safeA},
+	}
+	for _, c := range cases {
+		match, address, err := MatchProxyCreationLog(c.logs, c.safe)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", c.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if !c.found {
+			if match != nil {
+				t.Errorf("%s: matched %s", c.name, address.Hex())
+			}
+			continue
+		}
+		if match == nil {
+			t.Errorf("%s: no match", c.name)
+		} else if address != safeA || match != c.logs[len(c.logs)-1] {
+			t.Errorf("%s: matched %s", c.name, address.Hex())
+		}
+	}
+}
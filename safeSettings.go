@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// SafeSettingsChange is a call from a Safe to itself changing its own configuration, such as its owners or modules.
+// Before and After describe the affected settings for review.
+type SafeSettingsChange struct {
+	Description string
+	Data        []byte
+	Before      []string
+	After       []string
+	Warnings    []string
+	// If set, this question must be answered even when confirmation is otherwise skipped with --yes.
+	ExtraConfirmation string
+}
+
+func (change *SafeSettingsChange) Warn(format string, args ...interface{}) {
+	change.Warnings = append(change.Warnings, fmt.Sprintf(format, args...))
+}
+
+func PrintSafeSettingsChange(change *SafeSettingsChange) {
+	fmt.Println(change.Description)
+	fmt.Println("Before:")
+	for _, line := range change.Before {
+		fmt.Println("  " + line)
+	}
+	fmt.Println("After:")
+	for _, line := range change.After {
+		fmt.Println("  " + line)
+	}
+	DefaultSignatureRegistry().PrintCalldata(change.Data, "  ")
+	for _, warning := range change.Warnings {
+		fmt.Println("Warning:", warning)
+	}
+}
+
+// Encodes a call to one of the Safe's own methods.
+func packSafeCall(method string, args ...interface{}) ([]byte, error) {
+	safeAbi, err := Safe.SafeMetaData.GetAbi()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Safe ABI: %w", err)
+	}
+	data, err := safeAbi.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", method, err)
+	}
+	return data, nil
+}
+
+// Builds a subcommand proposing a change to a Safe's settings, with the flags shared by every such command. validate
+// checks the command's own flags and may be nil; build is called once the chain is reachable.
+func createSafeSettingsCmd(use string, short string, validate func() error, build func(safeAddress common.Address, client *ethclient.Client) (*SafeSettingsChange, error)) *cobra.Command {
+	var (
+		safe         string
+		keyfile      string
+		password     string
+		yes          bool
+		gasFlags     safeTxGasFlags
+		gasParams    SafeTxGasParams
+		safeNonceRaw string
+		safeNonce    *big.Int
+	)
+
+	settingsCmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !common.IsHexAddress(safe) {
+				return fmt.Errorf("invalid safe address: %s", safe)
+			}
+			if keyfile == "" {
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
+			}
+			var err error
+			gasParams, err = gasFlags.Parse()
+			if err != nil {
+				return err
+			}
+			if safeNonceRaw != "" {
+				var err error
+				safeNonce, err = parseSafeNonce("safe-nonce", safeNonceRaw)
+				if err != nil {
+					return err
+				}
+			}
+			if validate != nil {
+				return validate()
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			if err := gasParams.Validate(client); err != nil {
+				return err
+			}
+
+			safeAddress := common.HexToAddress(safe)
+			change, err := build(safeAddress, client)
+			if err != nil {
+				return err
+			}
+
+			PrintSafeSettingsChange(change)
+			if change.ExtraConfirmation != "" {
+				ok, err := ConfirmPrompt(change.ExtraConfirmation)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("change not confirmed")
+				}
+			}
+			if !yes {
+				ok, err := ConfirmPrompt("Sign and propose this change?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("change not confirmed")
+				}
+			}
+
+			key, err := signer.Open(keyfile, password)
+			if err != nil {
+				return err
+			}
+
+			return ProposeSafeCall(safeAddress, safeAddress, big.NewInt(0), change.Data, gasParams, safeNonce, chainID, key, client, SafeClientBaseURL(safeAPIURL))
+		},
+	}
+
+	settingsCmd.Flags().StringVar(&safe, "safe", "", "Safe address")
+	settingsCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer: "+signer.SpecUsage)
+	settingsCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	settingsCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of the Safe's chain")
+	settingsCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway base URL")
+	settingsCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Propose without asking for confirmation")
+	addSafeTxGasFlags(settingsCmd, &gasFlags)
+	settingsCmd.Flags().StringVar(&safeNonceRaw, "safe-nonce", "", "Safe nonce for the proposal (defaults to the next nonce not taken by a queued proposal)")
+	settingsCmd.MarkFlagRequired("safe")
+	settingsCmd.MarkFlagRequired("rpc")
+
+	return settingsCmd
+}
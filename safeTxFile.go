@@ -0,0 +1,239 @@
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/safetx"
+	"github.com/G7DAO/safes/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// SafeTxFile is an unsigned SafeTx, exported so that it can be signed on another (possibly offline) machine.
+type SafeTxFile struct {
+	ChainID     string                   `json:"chainId"`
+	Safe        string                   `json:"safe"`
+	SafeVersion string                   `json:"safeVersion"`
+	SafeTxHash  string                   `json:"safeTxHash"`
+	Tx          Safe.SafeTransactionData `json:"tx"`
+}
+
+// SafeTxSignatureFile is a detached owner signature over the SafeTxHash of a SafeTxFile.
+type SafeTxSignatureFile struct {
+	ChainID    string `json:"chainId"`
+	Safe       string `json:"safe"`
+	SafeTxHash string `json:"safeTxHash"`
+	Signer     string `json:"signer"`
+	Signature  string `json:"signature"`
+}
+
+func ExportSafeTransaction(safeAddress common.Address, txData Safe.SafeTransactionData, chainID *big.Int, client *ethclient.Client) (*SafeTxFile, error) {
+	safeTxHash, version, err := safetx.HashForSafe(client, safeAddress, SafeTxFromData(txData), chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate SafeTxHash: %w", err)
+	}
+
+	return &SafeTxFile{
+		ChainID:     chainID.String(),
+		Safe:        safeAddress.Hex(),
+		SafeVersion: version,
+		SafeTxHash:  safeTxHash.Hex(),
+		Tx:          txData,
+	}, nil
+}
+
+func WriteJSONFile(path string, v interface{}) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	return os.WriteFile(path, append(content, '\n'), 0644)
+}
+
+func readJSONFile(path string, v interface{}) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(content, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// Reads a SafeTxFile and recomputes its SafeTxHash, failing if it does not match the hash recorded in the file.
+func ReadSafeTxFile(path string) (*SafeTxFile, common.Hash, error) {
+	var txFile SafeTxFile
+	if err := readJSONFile(path, &txFile); err != nil {
+		return nil, common.Hash{}, err
+	}
+
+	if !common.IsHexAddress(txFile.Safe) {
+		return nil, common.Hash{}, fmt.Errorf("invalid safe address in %s: %s", path, txFile.Safe)
+	}
+	if txFile.Tx.Nonce == nil {
+		return nil, common.Hash{}, fmt.Errorf("missing nonce in %s", path)
+	}
+
+	chainID, ok := new(big.Int).SetString(txFile.ChainID, 10)
+	if !ok {
+		return nil, common.Hash{}, fmt.Errorf("invalid chain ID in %s: %s", path, txFile.ChainID)
+	}
+
+	safeTxHash, err := safetx.Hash(common.HexToAddress(txFile.Safe), SafeTxFromData(txFile.Tx), chainID, txFile.SafeVersion)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("failed to calculate SafeTxHash: %w", err)
+	}
+
+	if safeTxHash != common.HexToHash(txFile.SafeTxHash) {
+		return nil, common.Hash{}, fmt.Errorf("SafeTxHash mismatch: file records %s, recomputed %s", txFile.SafeTxHash, safeTxHash.Hex())
+	}
+
+	return &txFile, safeTxHash, nil
+}
+
+// Recovers the owner that produced a 65-byte ECDSA signature over safeTxHash. Both plain signatures (v = 27, 28)
+// and eth_sign signatures (v = 31, 32) are accepted.
+func RecoverSafeTxSigner(safeTxHash common.Hash, signature []byte) (common.Address, error) {
+	if len(signature) != 65 {
+		return common.Address{}, fmt.Errorf("signature has length %d, expected 65", len(signature))
+	}
+
+	signer, kind, err := ParseSafeSignature(safeTxHash, signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if kind != SafeSignatureECDSA && kind != SafeSignatureEthSign {
+		return common.Address{}, fmt.Errorf("unsupported signature type (v = %d)", signature[64])
+	}
+	return signer, nil
+}
+
+// Signs the SafeTx in txFile with the given key. This does not require any network access.
+func SignSafeTxFile(txFile *SafeTxFile, safeTxHash common.Hash, key signer.Signer) (*SafeTxSignatureFile, error) {
+	chainID, ok := new(big.Int).SetString(txFile.ChainID, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid chain ID: %s", txFile.ChainID)
+	}
+
+	signature, err := SignSafeTx(common.HexToAddress(txFile.Safe), txFile.Tx, chainID, txFile.SafeVersion, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SafeTxSignatureFile{
+		ChainID:    txFile.ChainID,
+		Safe:       txFile.Safe,
+		SafeTxHash: safeTxHash.Hex(),
+		Signer:     key.Address().Hex(),
+		Signature:  signature,
+	}, nil
+}
+
+// Wraps a signature produced by an external wallet (for example from eth_signTypedData_v4) into a signature file.
+func ImportSafeTxSignature(txFile *SafeTxFile, safeTxHash common.Hash, signature string) (*SafeTxSignatureFile, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature hex: %w", err)
+	}
+
+	signer, err := RecoverSafeTxSigner(safeTxHash, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SafeTxSignatureFile{
+		ChainID:    txFile.ChainID,
+		Safe:       txFile.Safe,
+		SafeTxHash: safeTxHash.Hex(),
+		Signer:     signer.Hex(),
+		Signature:  "0x" + common.Bytes2Hex(sig),
+	}, nil
+}
+
+// Reads signature files and checks that each one signs safeTxHash and recovers to the signer it claims.
+func ReadSafeTxSignatureFiles(paths []string, safeTxHash common.Hash) (map[common.Address][]byte, error) {
+	signatures := make(map[common.Address][]byte)
+	for _, path := range paths {
+		var sigFile SafeTxSignatureFile
+		if err := readJSONFile(path, &sigFile); err != nil {
+			return nil, err
+		}
+
+		if common.HexToHash(sigFile.SafeTxHash) != safeTxHash {
+			return nil, fmt.Errorf("%s signs %s, expected %s", path, sigFile.SafeTxHash, safeTxHash.Hex())
+		}
+
+		sig, err := hex.DecodeString(strings.TrimPrefix(sigFile.Signature, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature hex in %s: %w", path, err)
+		}
+
+		signer, err := RecoverSafeTxSigner(safeTxHash, sig)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if signer != common.HexToAddress(sigFile.Signer) {
+			return nil, fmt.Errorf("%s claims signer %s but the signature recovers to %s", path, sigFile.Signer, signer.Hex())
+		}
+
+		signatures[signer] = sig
+	}
+
+	return signatures, nil
+}
+
+// Submits collected signatures to the Safe client gateway. If the SafeTx is not yet known to the service it is
+// proposed with the first signature, and the remaining signatures are added as confirmations.
+func SubmitSafeTxSignatures(txFile *SafeTxFile, safeTxHash common.Hash, signatures map[common.Address][]byte, safeClientURL string) error {
+	chainID, ok := new(big.Int).SetString(txFile.ChainID, 10)
+	if !ok {
+		return fmt.Errorf("invalid chain ID: %s", txFile.ChainID)
+	}
+	safeAddress := common.HexToAddress(txFile.Safe)
+
+	// Only a transaction the service reports as missing is proposed; any other failure to look it up is returned
+	// rather than proposing it again.
+	confirmed := make(map[common.Address]bool)
+	details, err := GetTransactionDetails(safeClientURL, chainID, safeTxHash.Hex())
+	if err != nil && !IsSafeClientNotFound(err) {
+		return err
+	}
+	known := err == nil
+	if known {
+		for _, confirmation := range details.DetailedExecutionInfo.Confirmations {
+			confirmed[common.HexToAddress(confirmation.Signer.Value)] = true
+		}
+	}
+
+	for signer, sig := range signatures {
+		if confirmed[signer] {
+			fmt.Printf("Signature from %s already submitted\n", signer.Hex())
+			continue
+		}
+
+		signature := "0x" + common.Bytes2Hex(sig)
+		if !known {
+			proposeURL := fmt.Sprintf("%s/v1/chains/%s/transactions/%s/propose", safeClientURL, chainID.String(), safeAddress.Hex())
+			if err := ProposeSafeTransaction(proposeURL, txFile.Tx, safeTxHash, signer, signature); err != nil {
+				return fmt.Errorf("failed to propose transaction: %w", err)
+			}
+			known = true
+			fmt.Printf("Proposed %s with signature from %s\n", safeTxHash.Hex(), signer.Hex())
+			continue
+		}
+
+		if err := ConfirmTransaction(safeClientURL, chainID, safeTxHash, signature); err != nil {
+			return fmt.Errorf("failed to submit signature from %s: %w", signer.Hex(), err)
+		}
+		fmt.Printf("Submitted signature from %s\n", signer.Hex())
+	}
+
+	return nil
+}
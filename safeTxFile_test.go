@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// gatewayStub answers transaction lookups with status (and details when it is 200), and records POSTs by path.
+type gatewayStub struct {
+	status  int
+	details *SafeClientTransactionDetails
+
+	mu    sync.Mutex
+	posts []string
+}
+
+func (g *gatewayStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		g.mu.Lock()
+		g.posts = append(g.posts, r.URL.Path)
+		g.mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(g.status)
+	if g.status == http.StatusOK {
+		json.NewEncoder(w).Encode(g.details)
+	} else {
+		w.Write([]byte(`{"code":1,"message":"error"}`))
+	}
+}
+
+func TestSubmitSafeTxSignatures(t *testing.T) {
+	keys, signers := testKeys(t, 2)
+	safeTxHash := crypto.Keccak256Hash([]byte("SafeTx"))
+	signatures := map[common.Address][]byte{
+		signers[0]: signSafeTxHash(t, keys[0], safeTxHash),
+		signers[1]: signSafeTxHash(t, keys[1], safeTxHash),
+	}
+	txFile := &SafeTxFile{
+		ChainID: "1337",
+		Safe:    "0x000000000000000000000000000000000000cafE",
+		Tx: Safe.SafeTransactionData{
+			To:             "0x000000000000000000000000000000000000dEaD",
+			Value:          "0",
+			GasPrice:       "0",
+			GasToken:       Safe.NativeTokenAddress,
+			RefundReceiver: Safe.NativeTokenAddress,
+			Nonce:          big.NewInt(0),
+		},
+	}
+
+	countPosts := func(g *gatewayStub, suffix string) int {
+		count := 0
+		for _, path := range g.posts {
+			if strings.HasSuffix(path, suffix) {
+				count++
+			}
+		}
+		return count
+	}
+
+	t.Run("not found is proposed", func(t *testing.T) {
+		gateway := &gatewayStub{status: http.StatusNotFound}
+		server := httptest.NewServer(gateway)
+		defer server.Close()
+
+		if err := SubmitSafeTxSignatures(txFile, safeTxHash, signatures, server.URL); err != nil {
+			t.Fatal(err)
+		}
+		if countPosts(gateway, "/propose") != 1 || countPosts(gateway, "/confirmations") != 1 {
+			t.Errorf("posted %v, want one proposal and one confirmation", gateway.posts)
+		}
+	})
+
+	for _, status := range []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusUnauthorized} {
+		t.Run(http.StatusText(status)+" is returned", func(t *testing.T) {
+			gateway := &gatewayStub{status: status}
+			server := httptest.NewServer(gateway)
+			defer server.Close()
+
+			if err := SubmitSafeTxSignatures(txFile, safeTxHash, signatures, server.URL); err == nil {
+				t.Error("lookup failure was not returned")
+			}
+			if len(gateway.posts) > 0 {
+				t.Errorf("posted %v after a failed lookup", gateway.posts)
+			}
+		})
+	}
+
+	t.Run("unreachable gateway is returned", func(t *testing.T) {
+		server := httptest.NewServer(&gatewayStub{})
+		server.Close()
+		if err := SubmitSafeTxSignatures(txFile, safeTxHash, signatures, server.URL); err == nil {
+			t.Error("connection failure was not returned")
+		}
+	})
+
+	t.Run("invalid chain ID is returned", func(t *testing.T) {
+		gateway := &gatewayStub{status: http.StatusNotFound}
+		server := httptest.NewServer(gateway)
+		defer server.Close()
+
+		invalid := *txFile
+		invalid.ChainID = "0x539"
+		if err := SubmitSafeTxSignatures(&invalid, safeTxHash, signatures, server.URL); err == nil {
+			t.Error("invalid chain ID was not returned")
+		}
+		if len(gateway.posts) != 0 {
+			t.Errorf("posted %v with an invalid chain ID", gateway.posts)
+		}
+	})
+
+	t.Run("known transaction is confirmed", func(t *testing.T) {
+		signature := "0x" + common.Bytes2Hex(signatures[signers[0]])
+		gateway := &gatewayStub{status: http.StatusOK, details: &SafeClientTransactionDetails{
+			TxData: &SafeClientTxData{},
+			DetailedExecutionInfo: &SafeClientExecutionInfo{
+				Type:          "MULTISIG",
+				Confirmations: []SafeClientConfirmation{{Signer: SafeClientAddress{Value: signers[0].Hex()}, Signature: &signature}},
+			},
+		}}
+		server := httptest.NewServer(gateway)
+		defer server.Close()
+
+		if err := SubmitSafeTxSignatures(txFile, safeTxHash, signatures, server.URL); err != nil {
+			t.Fatal(err)
+		}
+		if countPosts(gateway, "/propose") != 0 || countPosts(gateway, "/confirmations") != 1 {
+			t.Errorf("posted %v, want only the missing confirmation", gateway.posts)
+		}
+	})
+}
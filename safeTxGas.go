@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// SafeTxGasParams holds the gas and refund fields of a SafeTx. The zero value (no refund) is what the Safe{Wallet}
+// interface uses for transactions executed by an owner.
+type SafeTxGasParams struct {
+	SafeTxGas      uint64
+	BaseGas        uint64
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+}
+
+// Raw values of the gas and refund flags, as registered by addSafeTxGasFlags.
+type safeTxGasFlags struct {
+	safeTxGas      uint64
+	baseGas        uint64
+	gasPrice       string
+	gasToken       string
+	refundReceiver string
+}
+
+func addSafeTxGasFlags(cmd *cobra.Command, flags *safeTxGasFlags) {
+	cmd.Flags().Uint64Var(&flags.safeTxGas, "safe-tx-gas", 0, "Gas available to the SafeTx call (0 lets the executor supply all remaining gas)")
+	cmd.Flags().Uint64Var(&flags.baseGas, "base-gas", 0, "Gas costs independent of the call (signature checks, refund transfer) included in the refund")
+	cmd.Flags().StringVar(&flags.gasPrice, "gas-price", "0", "Gas price used to compute the refund paid by the Safe, in wei or gas token units (0 disables refunds)")
+	cmd.Flags().StringVar(&flags.gasToken, "gas-token", Safe.NativeTokenAddress, "Token the refund is paid in (zero address for the native token)")
+	cmd.Flags().StringVar(&flags.refundReceiver, "refund-receiver", Safe.NativeTokenAddress, "Address receiving the refund (zero address for the executor)")
+}
+
+// Parses and cross-checks the gas and refund flags. Checks requiring chain state are done by Validate.
+func (flags safeTxGasFlags) Parse() (SafeTxGasParams, error) {
+	gasPrice, ok := ParseInteger(flags.gasPrice)
+	if !ok || gasPrice.Sign() < 0 {
+		return SafeTxGasParams{}, fmt.Errorf("invalid --gas-price: %s", flags.gasPrice)
+	}
+	if !common.IsHexAddress(flags.gasToken) {
+		return SafeTxGasParams{}, fmt.Errorf("invalid --gas-token address: %s", flags.gasToken)
+	}
+	if !common.IsHexAddress(flags.refundReceiver) {
+		return SafeTxGasParams{}, fmt.Errorf("invalid --refund-receiver address: %s", flags.refundReceiver)
+	}
+
+	params := SafeTxGasParams{
+		SafeTxGas:      flags.safeTxGas,
+		BaseGas:        flags.baseGas,
+		GasPrice:       gasPrice,
+		GasToken:       common.HexToAddress(flags.gasToken),
+		RefundReceiver: common.HexToAddress(flags.refundReceiver),
+	}
+
+	// The Safe only pays a refund when gasPrice is non-zero, so refund settings without one are almost certainly
+	// a mistake.
+	if gasPrice.Sign() == 0 {
+		if params.BaseGas != 0 || params.GasToken != (common.Address{}) || params.RefundReceiver != (common.Address{}) {
+			return SafeTxGasParams{}, fmt.Errorf("--base-gas, --gas-token and --refund-receiver only take effect with a non-zero --gas-price")
+		}
+	}
+
+	return params, nil
+}
+
+// Checks the parameters against chain state: a gas token must be a contract.
+func (params SafeTxGasParams) Validate(client *ethclient.Client) error {
+	if params.GasToken == (common.Address{}) {
+		return nil
+	}
+
+	code, err := client.CodeAt(context.Background(), params.GasToken, nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch code at gas token %s: %w", params.GasToken.Hex(), err)
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("gas token %s is not a contract", params.GasToken.Hex())
+	}
+	return nil
+}
+
+// Sets the gas and refund fields of a SafeTx.
+func (params SafeTxGasParams) Apply(txData *Safe.SafeTransactionData) {
+	gasPrice := params.GasPrice
+	if gasPrice == nil {
+		gasPrice = big.NewInt(0)
+	}
+	txData.SafeTxGas = params.SafeTxGas
+	txData.BaseGas = params.BaseGas
+	txData.GasPrice = gasPrice.String()
+	txData.GasToken = params.GasToken.Hex()
+	txData.RefundReceiver = params.RefundReceiver.Hex()
+}
+
+// Returns the largest refund the Safe can pay for a SafeTx, (safeTxGas + baseGas) * gasPrice. A nil result means
+// the refund is unbounded, since with safeTxGas 0 the executor chooses how much gas the call may use.
+func MaxSafeTxRefund(txData Safe.SafeTransactionData) (*big.Int, error) {
+	gasPrice, ok := new(big.Int).SetString(txData.GasPrice, 10)
+	if !ok || gasPrice.Sign() < 0 {
+		return nil, fmt.Errorf("invalid gas price: %s", txData.GasPrice)
+	}
+	if gasPrice.Sign() == 0 {
+		return big.NewInt(0), nil
+	}
+	if txData.SafeTxGas == 0 {
+		return nil, nil
+	}
+
+	gas := new(big.Int).Add(new(big.Int).SetUint64(txData.SafeTxGas), new(big.Int).SetUint64(txData.BaseGas))
+	return gas.Mul(gas, gasPrice), nil
+}
+
+func formatMaxSafeTxRefund(txData Safe.SafeTransactionData) string {
+	refund, err := MaxSafeTxRefund(txData)
+	if err != nil {
+		return err.Error()
+	}
+
+	unit := "wei"
+	if common.HexToAddress(txData.GasToken) != (common.Address{}) {
+		unit = "units of " + common.HexToAddress(txData.GasToken).Hex()
+	}
+	receiver := "the executor"
+	if common.HexToAddress(txData.RefundReceiver) != (common.Address{}) {
+		receiver = common.HexToAddress(txData.RefundReceiver).Hex()
+	}
+
+	switch {
+	case refund == nil:
+		return fmt.Sprintf("UNBOUNDED (safeTxGas is 0, so the refund is (gas used + %d) * %s %s, paid to %s)", txData.BaseGas, txData.GasPrice, unit, receiver)
+	case refund.Sign() == 0:
+		return "none"
+	}
+	return fmt.Sprintf("%s %s, paid to %s", refund.String(), unit, receiver)
+}
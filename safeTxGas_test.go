@@ -0,0 +1,100 @@
+package main
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestSafeTxGasFlagsParse(t *testing.T) {
+	const token = "0x00000000000000000000000000000000000000aa"
+	const receiver = "0x00000000000000000000000000000000000000bb"
+	defaults := safeTxGasFlags{gasPrice: "0", gasToken: Safe.NativeTokenAddress, refundReceiver: Safe.NativeTokenAddress}
+
+	cases := []struct {
+		name     string
+		edit     func(*safeTxGasFlags)
+		gasPrice int64
+		ok       bool
+	}{
+		{"defaults", func(f *safeTxGasFlags) {}, 0, true},
+		{"safeTxGas without a gas price", func(f *safeTxGasFlags) { f.safeTxGas = 100_000 }, 0, true},
+		{"decimal gas price", func(f *safeTxGasFlags) { f.gasPrice = "010" }, 10, true},
+		{"hex gas price", func(f *safeTxGasFlags) { f.gasPrice = "0x10" }, 16, true},
+		{"negative gas price", func(f *safeTxGasFlags) { f.gasPrice = "-1" }, 0, false},
+		{"binary gas price", func(f *safeTxGasFlags) { f.gasPrice = "0b1" }, 0, false},
+		{"empty gas price", func(f *safeTxGasFlags) { f.gasPrice = "" }, 0, false},
+		{"invalid gas token", func(f *safeTxGasFlags) { f.gasPrice = "1"; f.gasToken = "0x1234" }, 0, false},
+		{"invalid refund receiver", func(f *safeTxGasFlags) { f.gasPrice = "1"; f.refundReceiver = "bob" }, 0, false},
+		{"base gas without a gas price", func(f *safeTxGasFlags) { f.baseGas = 21_000 }, 0, false},
+		{"gas token without a gas price", func(f *safeTxGasFlags) { f.gasToken = token }, 0, false},
+		{"refund receiver without a gas price", func(f *safeTxGasFlags) { f.refundReceiver = receiver }, 0, false},
+		{"full refund settings", func(f *safeTxGasFlags) {
+			f.safeTxGas, f.baseGas, f.gasPrice, f.gasToken, f.refundReceiver = 100_000, 21_000, "7", token, receiver
+		}, 7, true},
+	}
+	for _, c := range cases {
+		flags := defaults
+		c.edit(&flags)
+		params, err := flags.Parse()
+		if !c.ok {
+			if err == nil {
+				t.Errorf("%s: Parse succeeded, want an error", c.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if params.GasPrice.Cmp(big.NewInt(c.gasPrice)) != 0 {
+			t.Errorf("%s: gas price %s, want %d", c.name, params.GasPrice, c.gasPrice)
+		}
+		if params.SafeTxGas != flags.safeTxGas || params.BaseGas != flags.baseGas ||
+			params.GasToken != common.HexToAddress(flags.gasToken) || params.RefundReceiver != common.HexToAddress(flags.refundReceiver) {
+			t.Errorf("%s: Parse = %+v, does not match the flags %+v", c.name, params, flags)
+		}
+	}
+}
+
+func TestMaxSafeTxRefund(t *testing.T) {
+	maxUint256 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	maxGas := new(big.Int).Mul(big.NewInt(2), new(big.Int).SetUint64(math.MaxUint64))
+
+	cases := []struct {
+		name      string
+		safeTxGas uint64
+		baseGas   uint64
+		gasPrice  string
+		want      *big.Int
+		ok        bool
+	}{
+		{"no gas price", 100_000, 21_000, "0", big.NewInt(0), true},
+		{"unbounded", 0, 21_000, "1", nil, true},
+		{"bounded", 100_000, 21_000, "3", big.NewInt(363_000), true},
+		{"gas sum above uint64", math.MaxUint64, math.MaxUint64, "1", maxGas, true},
+		{"product above uint256", math.MaxUint64, math.MaxUint64, maxUint256.String(), new(big.Int).Mul(maxGas, maxUint256), true},
+		{"negative gas price", 100_000, 0, "-1", nil, false},
+		{"hex gas price", 100_000, 0, "0x1", nil, false},
+	}
+	for _, c := range cases {
+		txData := Safe.SafeTransactionData{SafeTxGas: c.safeTxGas, BaseGas: c.baseGas, GasPrice: c.gasPrice}
+		got, err := MaxSafeTxRefund(txData)
+		if !c.ok {
+			if err == nil {
+				t.Errorf("%s: MaxSafeTxRefund = %v, want an error", c.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if (got == nil) != (c.want == nil) || (got != nil && got.Cmp(c.want) != 0) {
+			t.Errorf("%s: MaxSafeTxRefund = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Package safetx computes the EIP-712 SafeTx typed data and hash expected by each version of the Safe contracts.
+//
+// It is hand-written and shared by the main package and the generated bindings, so that regenerating the bindings
+// does not lose it.
+package safetx
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+
+	"github.com/G7DAO/seer/bindings/GnosisSafe"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Released Safe versions. Versions outside this list are rejected rather than hashed with a guessed layout.
+var KnownVersions = []string{"0.1.0", "1.0.0", "1.1.0", "1.1.1", "1.2.0", "1.3.0", "1.4.0", "1.4.1", "1.5.0"}
+
+var versionPattern = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)(\+L2)?$`)
+
+// SafeTx holds the hashed fields of a Safe transaction, in the same representation as the SafeTransactionData of the
+// generated bindings: Data is hex without 0x prefix, and amounts and addresses are strings.
+type SafeTx struct {
+	To             string
+	Value          string
+	Data           string
+	Operation      uint8
+	SafeTxGas      uint64
+	BaseGas        uint64
+	GasPrice       string
+	GasToken       string
+	RefundReceiver string
+	Nonce          *big.Int
+}
+
+// VersionAtLeast reports whether a Safe VERSION() string such as "1.3.0" or "1.3.0+L2" is at least major.minor. It
+// fails on strings that are not a known Safe version.
+func VersionAtLeast(version string, major, minor int) (bool, error) {
+	match := versionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return false, fmt.Errorf("unrecognized Safe version %q", version)
+	}
+	known := false
+	for _, knownVersion := range KnownVersions {
+		if match[1]+"."+match[2]+"."+match[3] == knownVersion {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return false, fmt.Errorf("unsupported Safe version %q", version)
+	}
+
+	vMajor, _ := strconv.Atoi(match[1])
+	vMinor, _ := strconv.Atoi(match[2])
+	return vMajor > major || (vMajor == major && vMinor >= minor), nil
+}
+
+// TypedData returns the EIP-712 typed data of a SafeTx as hashed by a Safe of the given version. Safes before 1.3.0
+// use a domain without chainId, and Safes before 1.0.0 call the baseGas field dataGas.
+func TypedData(safeAddress common.Address, tx SafeTx, chainID *big.Int, version string) (apitypes.TypedData, error) {
+	withChainID, err := VersionAtLeast(version, 1, 3)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+	withBaseGas, err := VersionAtLeast(version, 1, 0)
+	if err != nil {
+		return apitypes.TypedData{}, err
+	}
+
+	domainTypes := []apitypes.Type{{Name: "verifyingContract", Type: "address"}}
+	domain := apitypes.TypedDataDomain{
+		VerifyingContract: safeAddress.Hex(),
+	}
+	if withChainID {
+		domainTypes = []apitypes.Type{
+			{Name: "chainId", Type: "uint256"},
+			{Name: "verifyingContract", Type: "address"},
+		}
+		domain.ChainId = (*math.HexOrDecimal256)(chainID)
+	}
+
+	baseGasField := "baseGas"
+	if !withBaseGas {
+		baseGasField = "dataGas"
+	}
+
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": domainTypes,
+			"SafeTx": []apitypes.Type{
+				{Name: "to", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"},
+				{Name: "safeTxGas", Type: "uint256"},
+				{Name: baseGasField, Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"},
+				{Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		Domain:      domain,
+		PrimaryType: "SafeTx",
+		Message: apitypes.TypedDataMessage{
+			"to":             tx.To,
+			"value":          tx.Value,
+			"data":           "0x" + tx.Data,
+			"operation":      fmt.Sprintf("%d", tx.Operation),
+			"safeTxGas":      fmt.Sprintf("%d", tx.SafeTxGas),
+			baseGasField:     fmt.Sprintf("%d", tx.BaseGas),
+			"gasPrice":       tx.GasPrice,
+			"gasToken":       tx.GasToken,
+			"refundReceiver": tx.RefundReceiver,
+			"nonce":          fmt.Sprintf("%d", tx.Nonce),
+		},
+	}, nil
+}
+
+// Hash calculates the SafeTxHash of a transaction for a Safe of the given version.
+func Hash(safeAddress common.Address, tx SafeTx, chainID *big.Int, version string) (common.Hash, error) {
+	typedData, err := TypedData(safeAddress, tx, chainID, version)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	typedDataHash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash typed data: %v", err)
+	}
+	return common.BytesToHash(typedDataHash), nil
+}
+
+// Version reads VERSION() from a Safe.
+func Version(caller bind.ContractCaller, safeAddress common.Address) (string, error) {
+	safeCaller, err := GnosisSafe.NewGnosisSafeCaller(safeAddress, caller)
+	if err != nil {
+		return "", fmt.Errorf("failed to create GnosisSafe caller: %v", err)
+	}
+	version, err := safeCaller.VERSION(&bind.CallOpts{})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Safe version: %v", err)
+	}
+	return version, nil
+}
+
+// HashForSafe reads VERSION() from the Safe and calculates the SafeTxHash it expects. The version is returned
+// alongside the hash.
+func HashForSafe(caller bind.ContractCaller, safeAddress common.Address, tx SafeTx, chainID *big.Int) (common.Hash, string, error) {
+	version, err := Version(caller, safeAddress)
+	if err != nil {
+		return common.Hash{}, "", err
+	}
+	safeTxHash, err := Hash(safeAddress, tx, chainID, version)
+	return safeTxHash, version, err
+}
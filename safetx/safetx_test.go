@@ -0,0 +1,208 @@
+package safetx_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/bindings/SafeProxy"
+	"github.com/G7DAO/safes/safetx"
+	"github.com/G7DAO/seer/bindings/GnosisSafe"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+var (
+	testChainID    = big.NewInt(1337)
+	deployerKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+
+	// Addresses of the 1.2.0 proxy and the 1.4.1 singleton that TestHashMatchesGetTransactionHash deploys from
+	// deployerKey.
+	safeV120Address = common.HexToAddress("0xdB7d6AB1f17c6b31909aE466702703dAEf9269Cf")
+	safeV141Address = common.HexToAddress("0x880EC53Af800b5Cd051531672EF4fc4De233bD5d")
+)
+
+func testSafeTx() safetx.SafeTx {
+	return safetx.SafeTx{
+		To:             "0x000000000000000000000000000000000000dEaD",
+		Value:          "1000000000000000000",
+		Data:           "a9059cbb000000000000000000000000000000000000000000000000000000000000beef0000000000000000000000000000000000000000000000000000000000000064",
+		Operation:      1,
+		SafeTxGas:      50000,
+		BaseGas:        21000,
+		GasPrice:       "1",
+		GasToken:       "0x0000000000000000000000000000000000000000",
+		RefundReceiver: "0x000000000000000000000000000000000000bEEF",
+		Nonce:          big.NewInt(7),
+	}
+}
+
+func TestVersionAtLeast(t *testing.T) {
+	cases := []struct {
+		version      string
+		major, minor int
+		want         bool
+	}{
+		{"0.1.0", 1, 0, false},
+		{"1.0.0", 1, 0, true},
+		{"1.2.0", 1, 3, false},
+		{"1.3.0", 1, 3, true},
+		{"1.3.0+L2", 1, 3, true},
+		{"1.4.1+L2", 1, 5, false},
+		{"1.5.0", 1, 5, true},
+	}
+	for _, c := range cases {
+		got, err := safetx.VersionAtLeast(c.version, c.major, c.minor)
+		if err != nil {
+			t.Errorf("VersionAtLeast(%q, %d, %d): %v", c.version, c.major, c.minor, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("VersionAtLeast(%q, %d, %d) = %v, want %v", c.version, c.major, c.minor, got, c.want)
+		}
+	}
+}
+
+func TestVersionAtLeastRejectsUnknownVersions(t *testing.T) {
+	for _, version := range []string{"", "garbage", "v1.3.0", "1.3", "1.3.0-rc1", "1.3.0+L3", "9.9.9", "1.6.0"} {
+		if _, err := safetx.VersionAtLeast(version, 1, 3); err == nil {
+			t.Errorf("VersionAtLeast(%q) accepted an unknown version", version)
+		}
+		if _, err := safetx.Hash(safeV141Address, testSafeTx(), testChainID, version); err == nil {
+			t.Errorf("Hash(%q) hashed with an unknown version", version)
+		}
+	}
+}
+
+// The 1.1.1/1.2.0 and 1.3.0+ vectors are getTransactionHash results from the 1.2.0 and 1.4.1 contracts, as checked by
+// TestHashMatchesGetTransactionHash. No pre-1.0 bytecode is available to run, so the 0.1.0 vector is pinned by
+// TestDataGasLayoutMatchesTypehashes instead.
+func TestHashLayouts(t *testing.T) {
+	cases := []struct {
+		name        string
+		version     string
+		safeAddress common.Address
+		want        common.Hash
+	}{
+		{"pre-1.0 with dataGas", "0.1.0", safeV120Address, common.HexToHash("0x727cd367fafadc1f5ff3411e2f75e2f148896767a905259f7de3ec2091c3f028")},
+		{"1.1.1 without chainId", "1.1.1", safeV120Address, common.HexToHash("0x2b81d568c2db8b940f69381dd14571a4d1c95c971df1490f72ee8e68624f6554")},
+		{"1.2.0 without chainId", "1.2.0", safeV120Address, common.HexToHash("0x2b81d568c2db8b940f69381dd14571a4d1c95c971df1490f72ee8e68624f6554")},
+		{"1.3.0 with chainId", "1.3.0", safeV141Address, common.HexToHash("0x88c7cfec852c332ee4a8ce9b9564de59c69942b09c3447cfc599f0b2c9930df7")},
+		{"1.4.1+L2 with chainId", "1.4.1+L2", safeV141Address, common.HexToHash("0x88c7cfec852c332ee4a8ce9b9564de59c69942b09c3447cfc599f0b2c9930df7")},
+		{"1.5.0 with chainId", "1.5.0", safeV141Address, common.HexToHash("0x88c7cfec852c332ee4a8ce9b9564de59c69942b09c3447cfc599f0b2c9930df7")},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := safetx.Hash(c.safeAddress, testSafeTx(), testChainID, c.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("Hash = %s, want %s", got.Hex(), c.want.Hex())
+			}
+		})
+	}
+}
+
+// Recomputes the pre-1.0 hash from the DOMAIN_SEPARATOR_TYPEHASH and SAFE_TX_TYPEHASH constants of the 0.1.0 contract,
+// which name the baseGas field dataGas.
+func TestDataGasLayoutMatchesTypehashes(t *testing.T) {
+	domainTypehash := common.HexToHash("0x035aff83d86937d35b32e04f0ddc6ff469290eef2f1b692d8a815c89404d4749")
+	safeTxTypehash := common.HexToHash("0x14d461bc7412367e924637b363c7bf29b8f47e2f84869f4426e5633d8af47b20")
+
+	tx := testSafeTx()
+	word := func(n int64) []byte { return math.U256Bytes(big.NewInt(n)) }
+	address := func(hex string) []byte { return common.LeftPadBytes(common.HexToAddress(hex).Bytes(), 32) }
+
+	domainSeparator := crypto.Keccak256(domainTypehash.Bytes(), common.LeftPadBytes(safeV120Address.Bytes(), 32))
+	value, _ := new(big.Int).SetString(tx.Value, 10)
+	structHash := crypto.Keccak256(
+		safeTxTypehash.Bytes(),
+		address(tx.To),
+		math.U256Bytes(value),
+		crypto.Keccak256(common.FromHex(tx.Data)),
+		word(int64(tx.Operation)),
+		word(int64(tx.SafeTxGas)),
+		word(int64(tx.BaseGas)),
+		word(1),
+		address(tx.GasToken),
+		address(tx.RefundReceiver),
+		math.U256Bytes(new(big.Int).Set(tx.Nonce)),
+	)
+	want := crypto.Keccak256Hash([]byte{0x19, 0x01}, domainSeparator, structHash)
+
+	got, err := safetx.Hash(safeV120Address, tx, testChainID, "0.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("Hash = %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func TestHashMatchesGetTransactionHash(t *testing.T) {
+	deployer, err := bind.NewKeyedTransactorWithChainID(deployerKey, testChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := simulated.NewBackend(types.GenesisAlloc{deployer.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)}})
+	defer backend.Close()
+	client := backend.Client()
+
+	// The 1.2.0 domain separator is only set by setup, which the singleton refuses, so it is called through a proxy.
+	singletonV120, _, _, err := GnosisSafe.DeployGnosisSafe(deployer, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	proxyV120, _, _, err := SafeProxy.DeploySafeProxy(deployer, client, singletonV120)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+	safeV120, err := GnosisSafe.NewGnosisSafe(proxyV120, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := safeV120.Setup(deployer, []common.Address{deployer.From}, big.NewInt(1), common.Address{}, nil, common.Address{}, common.Address{}, big.NewInt(0), common.Address{}); err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	singletonV141, _, _, err := Safe.DeploySafe(deployer, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend.Commit()
+
+	if proxyV120 != safeV120Address || singletonV141 != safeV141Address {
+		t.Fatalf("deployed %s and %s, expected %s and %s", proxyV120.Hex(), singletonV141.Hex(), safeV120Address.Hex(), safeV141Address.Hex())
+	}
+
+	tx := testSafeTx()
+	value, _ := new(big.Int).SetString(tx.Value, 10)
+	for _, safeAddress := range []common.Address{safeV120Address, safeV141Address} {
+		caller, err := GnosisSafe.NewGnosisSafeCaller(safeAddress, client)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := caller.GetTransactionHash(&bind.CallOpts{}, common.HexToAddress(tx.To), value, common.FromHex(tx.Data), tx.Operation,
+			new(big.Int).SetUint64(tx.SafeTxGas), new(big.Int).SetUint64(tx.BaseGas), big.NewInt(1),
+			common.HexToAddress(tx.GasToken), common.HexToAddress(tx.RefundReceiver), tx.Nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, version, err := safetx.HashForSafe(client, safeAddress, tx, testChainID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != common.Hash(want) {
+			t.Errorf("Safe %s: HashForSafe = %s, getTransactionHash = %x", version, got.Hex(), want)
+		}
+	}
+}
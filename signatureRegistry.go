@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/G7DAO/safes/bindings/CompatibilityFallbackHandler"
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/bindings/SafeL2"
+	"github.com/G7DAO/safes/bindings/SafeProxyFactory"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Environment variable overriding the location of the local signature registry.
+const SignatureRegistryEnvVar = "SAFES_SIGNATURES"
+
+// SignatureRegistry maps 4-byte selectors to the methods known to have that selector.
+type SignatureRegistry struct {
+	methods map[[4]byte][]abi.Method
+	// Function fragments added by the user, in the form they are persisted to disk.
+	fragments []json.RawMessage
+}
+
+func NewSignatureRegistry() *SignatureRegistry {
+	return &SignatureRegistry{methods: make(map[[4]byte][]abi.Method)}
+}
+
+// Registers a method, returning false if a method with the same signature is already known.
+func (r *SignatureRegistry) AddMethod(method abi.Method) bool {
+	var selector [4]byte
+	copy(selector[:], method.ID)
+	for _, known := range r.methods[selector] {
+		if known.Sig == method.Sig {
+			return false
+		}
+	}
+	r.methods[selector] = append(r.methods[selector], method)
+	return true
+}
+
+func (r *SignatureRegistry) AddABI(contractABI *abi.ABI) int {
+	added := 0
+	for _, method := range contractABI.Methods {
+		if r.AddMethod(method) {
+			added++
+		}
+	}
+	return added
+}
+
+// Returns the methods registered for a selector.
+func (r *SignatureRegistry) Lookup(selector []byte) []abi.Method {
+	var key [4]byte
+	copy(key[:], selector)
+	return r.methods[key]
+}
+
+// Returns the signatures of all registered methods, sorted by selector.
+func (r *SignatureRegistry) Signatures() []string {
+	var signatures []string
+	for selector, methods := range r.methods {
+		for _, method := range methods {
+			signatures = append(signatures, fmt.Sprintf("0x%x %s", selector, method.Sig))
+		}
+	}
+	sort.Strings(signatures)
+	return signatures
+}
+
+// Adds the functions of an ABI file (plain ABI JSON or a Hardhat/Foundry artifact) to the user's part of the
+// registry, returning the number of new signatures.
+func (r *SignatureRegistry) AddABIFile(path string) (int, error) {
+	if _, err := LoadABI(path); err != nil {
+		return 0, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var artifact struct {
+		ABI []json.RawMessage `json:"abi"`
+	}
+	var fragments []json.RawMessage
+	if err := json.Unmarshal(content, &fragments); err != nil {
+		if err := json.Unmarshal(content, &artifact); err != nil {
+			return 0, fmt.Errorf("failed to parse ABI in %s: %w", path, err)
+		}
+		fragments = artifact.ABI
+	}
+
+	added := 0
+	for _, fragment := range fragments {
+		method, ok, err := parseFunctionFragment(fragment)
+		if err != nil {
+			return added, fmt.Errorf("failed to parse ABI entry in %s: %w", path, err)
+		}
+		if ok && r.AddMethod(method) {
+			r.fragments = append(r.fragments, fragment)
+			added++
+		}
+	}
+
+	return added, nil
+}
+
+// Parses a single ABI entry, reporting false if it is not a function.
+func parseFunctionFragment(fragment json.RawMessage) (abi.Method, bool, error) {
+	var entry struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(fragment, &entry); err != nil {
+		return abi.Method{}, false, err
+	}
+	if entry.Type != "function" && entry.Type != "" {
+		return abi.Method{}, false, nil
+	}
+
+	parsed, err := abi.JSON(bytes.NewReader(append(append([]byte("["), fragment...), ']')))
+	if err != nil {
+		return abi.Method{}, false, err
+	}
+	for _, method := range parsed.Methods {
+		return method, true, nil
+	}
+	return abi.Method{}, false, nil
+}
+
+// Returns the path of the local signature registry: $SAFES_SIGNATURES if set, otherwise signatures.json in the
+// user's config directory.
+func SignatureRegistryPath() (string, error) {
+	if path := os.Getenv(SignatureRegistryEnvVar); path != "" {
+		return path, nil
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory, please set %s: %w", SignatureRegistryEnvVar, err)
+	}
+	return filepath.Join(configDir, "safes", "signatures.json"), nil
+}
+
+// Builds a registry seeded with the ABIs of the bundled bindings, the MultiSend library and the token standards, then
+// loads any signatures the user has added.
+func LoadSignatureRegistry() (*SignatureRegistry, error) {
+	registry := NewSignatureRegistry()
+
+	for _, metaData := range []string{
+		Safe.SafeMetaData.ABI,
+		SafeL2.SafeL2MetaData.ABI,
+		SafeProxyFactory.SafeProxyFactoryMetaData.ABI,
+		CompatibilityFallbackHandler.CompatibilityFallbackHandlerMetaData.ABI,
+		multiSendABI,
+		erc20ABI,
+		erc721ABI,
+		erc1155ABI,
+	} {
+		parsed, err := abi.JSON(strings.NewReader(metaData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse bundled ABI: %w", err)
+		}
+		registry.AddABI(&parsed)
+	}
+
+	path, err := SignatureRegistryPath()
+	if err != nil {
+		return registry, err
+	}
+
+	var fragments []json.RawMessage
+	if err := readJSONFile(path, &fragments); err != nil {
+		if os.IsNotExist(err) {
+			return registry, nil
+		}
+		return registry, err
+	}
+
+	for _, fragment := range fragments {
+		method, ok, err := parseFunctionFragment(fragment)
+		if err != nil {
+			return registry, fmt.Errorf("invalid entry in %s: %w", path, err)
+		}
+		if ok {
+			registry.AddMethod(method)
+			registry.fragments = append(registry.fragments, fragment)
+		}
+	}
+
+	return registry, nil
+}
+
+// Persists the signatures added by the user.
+func (r *SignatureRegistry) Save() (string, error) {
+	path, err := SignatureRegistryPath()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if r.fragments == nil {
+		r.fragments = []json.RawMessage{}
+	}
+	return path, WriteJSONFile(path, r.fragments)
+}
+
+var defaultSignatureRegistry *SignatureRegistry
+
+// Returns the local signature registry, loading it on first use. A registry that fails to load falls back to the
+// bundled signatures with a warning, so that a broken registry file never prevents reviewing a transaction.
+func DefaultSignatureRegistry() *SignatureRegistry {
+	if defaultSignatureRegistry == nil {
+		registry, err := LoadSignatureRegistry()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to load signature registry: %v\n", err)
+		}
+		defaultSignatureRegistry = registry
+	}
+	return defaultSignatureRegistry
+}
+
+// Decodes calldata against the registry, returning the first known method whose arguments unpack cleanly.
+func (r *SignatureRegistry) Decode(calldata []byte) (*abi.Method, []interface{}, bool) {
+	if len(calldata) < 4 {
+		return nil, nil, false
+	}
+	for _, method := range r.Lookup(calldata[:4]) {
+		values, err := method.Inputs.Unpack(calldata[4:])
+		if err != nil {
+			continue
+		}
+		return &method, values, true
+	}
+	return nil, nil, false
+}
+
+// Returns a one-line description of the method called by calldata, flagging selectors missing from the registry.
+func (r *SignatureRegistry) MethodName(calldata []byte) string {
+	if len(calldata) == 0 {
+		return "(none)"
+	}
+	if method, _, ok := r.Decode(calldata); ok {
+		return fmt.Sprintf("%s (0x%x)", method.Sig, method.ID)
+	}
+	if len(calldata) < 4 {
+		return fmt.Sprintf("UNKNOWN: malformed calldata 0x%x", calldata)
+	}
+	return fmt.Sprintf("UNKNOWN SELECTOR 0x%x", calldata[:4])
+}
+
+// Prints the decoded method and named arguments of calldata, recursing into the calls of a MultiSend batch.
+func (r *SignatureRegistry) PrintCalldata(calldata []byte, indent string) {
+	if len(calldata) == 0 {
+		fmt.Printf("%s(no calldata)\n", indent)
+		return
+	}
+
+	method, values, ok := r.Decode(calldata)
+	if !ok {
+		fmt.Printf("%s%s - not in the local signature registry, review the raw data before signing\n", indent, r.MethodName(calldata))
+		return
+	}
+
+	fmt.Printf("%s%s\n", indent, method.Sig)
+	for i, input := range method.Inputs {
+		if method.RawName == "multiSend" && input.Type.T == abi.BytesTy {
+			continue
+		}
+		fmt.Printf("%s  %s: %s\n", indent, argumentLabel(input, i), FormatABIValue(values[i]))
+	}
+
+	if method.RawName == "multiSend" {
+		calls, err := DecodeMultiSend(calldata)
+		if err != nil {
+			fmt.Printf("%s  INVALID MultiSend batch: %v\n", indent, err)
+			return
+		}
+		r.PrintCalls(calls, indent+"  ")
+	}
+}
+
+// Prints every call of a batch together with its decoded calldata.
+func (r *SignatureRegistry) PrintCalls(calls []MultiSendCall, indent string) {
+	for i, call := range calls {
+		operation := "Call"
+		if call.Operation == 1 {
+			operation = "DelegateCall"
+		}
+		fmt.Printf("%s[%d] %s to %s, value %s, data 0x%s\n", indent, i, operation, call.To.Hex(), call.Value.String(), common.Bytes2Hex(call.Data))
+		r.PrintCalldata(call.Data, indent+"    ")
+	}
+}
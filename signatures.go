@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+)
+
+func CreateSignaturesCmd() *cobra.Command {
+	signaturesCmd := &cobra.Command{
+		Use:   "signatures",
+		Short: "Manage the local registry of method signatures used to decode calldata",
+		Long: fmt.Sprintf(`Manage the local registry of method signatures used to decode calldata.
+
+The registry always contains the methods of the bundled Safe, SafeL2, SafeProxyFactory, CompatibilityFallbackHandler
+and MultiSend ABIs, and the transfer methods of ERC-20, ERC-721 and ERC-1155 tokens. Signatures added with
+"signatures add" are stored in signatures.json in the user config directory, or at the path given by $%s.`, SignatureRegistryEnvVar),
+	}
+
+	signaturesCmd.AddCommand(createAddSignaturesCmd())
+	signaturesCmd.AddCommand(createListSignaturesCmd())
+	signaturesCmd.AddCommand(createDecodeCalldataCmd())
+
+	return signaturesCmd
+}
+
+func createAddSignaturesCmd() *cobra.Command {
+	addSignaturesCmd := &cobra.Command{
+		Use:   "add <abi file>...",
+		Short: "Add the functions of ABI JSON files or Hardhat/Foundry artifacts to the registry",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := LoadSignatureRegistry()
+			if err != nil {
+				return err
+			}
+
+			for _, path := range args {
+				added, err := registry.AddABIFile(path)
+				if err != nil {
+					return err
+				}
+				cmd.Printf("Added %d new signatures from %s\n", added, path)
+			}
+
+			path, err := registry.Save()
+			if err != nil {
+				return fmt.Errorf("failed to save signature registry: %v", err)
+			}
+			cmd.Printf("Signature registry saved to %s\n", path)
+			return nil
+		},
+	}
+
+	return addSignaturesCmd
+}
+
+func createListSignaturesCmd() *cobra.Command {
+	listSignaturesCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List every signature known to the registry",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := LoadSignatureRegistry()
+			if err != nil {
+				return err
+			}
+			for _, signature := range registry.Signatures() {
+				cmd.Println(signature)
+			}
+			return nil
+		},
+	}
+
+	return listSignaturesCmd
+}
+
+func createDecodeCalldataCmd() *cobra.Command {
+	var calldata string
+
+	decodeCalldataCmd := &cobra.Command{
+		Use:   "decode",
+		Short: "Decode calldata using the registry, including the calls of MultiSend batches",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !IsValidHex(calldata) {
+				return fmt.Errorf("invalid calldata hex: %s", calldata)
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := LoadSignatureRegistry()
+			if err != nil {
+				return err
+			}
+			registry.PrintCalldata(common.FromHex(calldata), "")
+			return nil
+		},
+	}
+
+	decodeCalldataCmd.Flags().StringVar(&calldata, "calldata", "", "Hex-encoded calldata to decode")
+	decodeCalldataCmd.MarkFlagRequired("calldata")
+
+	return decodeCalldataCmd
+}
@@ -0,0 +1,184 @@
+package signer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ClefSigner signs through Clef's external API, over IPC or HTTP, so that keys never enter this process. Typed data
+// is sent in full with account_signTypedData so that Clef can show every field for approval, and transactions are
+// signed with account_signTransaction. Clef does not sign bare hashes.
+type ClefSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// Connects to Clef at endpoint, an IPC path or an HTTP URL. If address is the zero address, Clef must manage exactly
+// one account, which is used.
+func NewClefSigner(endpoint string, address common.Address) (*ClefSigner, error) {
+	client, err := rpc.DialContext(context.Background(), endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Clef at %s: %w", endpoint, err)
+	}
+
+	if address == (common.Address{}) {
+		var accounts []common.Address
+		if err := client.Call(&accounts, "account_list"); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("failed to list Clef accounts: %w", err)
+		}
+		if len(accounts) != 1 {
+			client.Close()
+			return nil, fmt.Errorf("Clef returned %d accounts, please choose one with clef:%s?address=...", len(accounts), endpoint)
+		}
+		address = accounts[0]
+	}
+
+	return &ClefSigner{client: client, address: address}, nil
+}
+
+func (s *ClefSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *ClefSigner) SignHash(hash common.Hash) ([]byte, error) {
+	return nil, fmt.Errorf("Clef does not sign bare hashes, only typed data and transactions")
+}
+
+func (s *ClefSigner) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	var signature hexutil.Bytes
+	if err := s.client.Call(&signature, "account_signTypedData", common.NewMixedcaseAddress(s.address), typedData); err != nil {
+		return nil, fmt.Errorf("Clef account_signTypedData failed: %w", err)
+	}
+
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	if err := checkSignature(common.BytesToHash(hash), signature, s.address); err != nil {
+		return nil, fmt.Errorf("Clef returned an invalid signature: %w", err)
+	}
+	return signature, nil
+}
+
+func (s *ClefSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	data := hexutil.Bytes(tx.Data())
+	args := apitypes.SendTxArgs{
+		From:    common.NewMixedcaseAddress(s.address),
+		Gas:     hexutil.Uint64(tx.Gas()),
+		Value:   hexutil.Big(*tx.Value()),
+		Nonce:   hexutil.Uint64(tx.Nonce()),
+		Input:   &data,
+		ChainID: (*hexutil.Big)(chainID),
+	}
+	if tx.To() != nil {
+		to := common.NewMixedcaseAddress(*tx.To())
+		args.To = &to
+	}
+	switch tx.Type() {
+	case types.LegacyTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	case types.AccessListTxType:
+		args.GasPrice = (*hexutil.Big)(tx.GasPrice())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	case types.DynamicFeeTxType:
+		args.MaxFeePerGas = (*hexutil.Big)(tx.GasFeeCap())
+		args.MaxPriorityFeePerGas = (*hexutil.Big)(tx.GasTipCap())
+		accessList := tx.AccessList()
+		args.AccessList = &accessList
+	default:
+		return nil, fmt.Errorf("unsupported transaction type %d", tx.Type())
+	}
+
+	var result struct {
+		Raw hexutil.Bytes      `json:"raw"`
+		Tx  *types.Transaction `json:"tx"`
+	}
+	if err := s.client.Call(&result, "account_signTransaction", args); err != nil {
+		return nil, fmt.Errorf("Clef account_signTransaction failed: %w", err)
+	}
+	if result.Tx == nil {
+		return nil, fmt.Errorf("Clef returned no transaction")
+	}
+
+	// Clef may only sign what it was asked to, so check the approved transaction is the one that was requested.
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), result.Tx)
+	if err != nil {
+		return nil, fmt.Errorf("Clef returned an invalid transaction: %w", err)
+	}
+	if sender != s.address {
+		return nil, fmt.Errorf("Clef signed the transaction as %s, expected %s", sender.Hex(), s.address.Hex())
+	}
+	if !sameTransaction(result.Tx, tx) {
+		return nil, fmt.Errorf("Clef signed a different transaction from the one requested")
+	}
+	return result.Tx, nil
+}
+
+// Compares the fields of two transactions that decide what is executed and what it may cost the sender.
+func sameTransaction(a, b *types.Transaction) bool {
+	return a.Nonce() == b.Nonce() &&
+		sameRecipient(a.To(), b.To()) &&
+		a.Value().Cmp(b.Value()) == 0 &&
+		string(a.Data()) == string(b.Data()) &&
+		a.Gas() == b.Gas() &&
+		a.GasPrice().Cmp(b.GasPrice()) == 0 &&
+		a.GasFeeCap().Cmp(b.GasFeeCap()) == 0 &&
+		a.GasTipCap().Cmp(b.GasTipCap()) == 0
+}
+
+func (s *ClefSigner) Close() {
+	s.client.Close()
+}
+
+func openClef(spec string) (Signer, error) {
+	endpoint, query, _ := strings.Cut(spec, "?")
+	var address common.Address
+	if query != "" {
+		raw, ok := strings.CutPrefix(query, "address=")
+		if !ok || !common.IsHexAddress(raw) {
+			return nil, fmt.Errorf("invalid Clef options %q, expected address=0x...", query)
+		}
+		address = common.HexToAddress(raw)
+	}
+	return NewClefSigner(endpoint, address)
+}
+
+// Checks that signature is a 65-byte signature of hash by expected, with v either 27 or 28.
+func checkSignature(hash common.Hash, signature []byte, expected common.Address) error {
+	if len(signature) != 65 {
+		return fmt.Errorf("signature has length %d, expected 65", len(signature))
+	}
+	if signature[64] != 27 && signature[64] != 28 {
+		return fmt.Errorf("signature has v = %d, expected 27 or 28", signature[64])
+	}
+
+	sig := make([]byte, 65)
+	copy(sig, signature)
+	sig[64] -= 27
+	publicKey, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		return err
+	}
+	if recovered := crypto.PubkeyToAddress(*publicKey); recovered != expected {
+		return fmt.Errorf("signature is from %s, expected %s", recovered.Hex(), expected.Hex())
+	}
+	return nil
+}
+
+func sameRecipient(a, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -0,0 +1,248 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// clefStub serves the account_ namespace of Clef's external API, signing with key and approving everything unless
+// reject is set.
+type clefStub struct {
+	accounts []common.Address
+	key      *ecdsa.PrivateKey
+	reject   bool
+	// Applied to transactions before signing, to simulate Clef signing something else than was asked.
+	tamper func(*types.DynamicFeeTx)
+}
+
+type clefSignTransactionResult struct {
+	Raw hexutil.Bytes      `json:"raw"`
+	Tx  *types.Transaction `json:"tx"`
+}
+
+func (stub *clefStub) List() []common.Address {
+	return stub.accounts
+}
+
+func (stub *clefStub) SignTypedData(address common.MixedcaseAddress, typedData apitypes.TypedData) (hexutil.Bytes, error) {
+	if stub.reject {
+		return nil, errors.New("Request denied")
+	}
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(hash, stub.key)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+func (stub *clefStub) SignTransaction(args apitypes.SendTxArgs) (*clefSignTransactionResult, error) {
+	if stub.reject {
+		return nil, errors.New("Request denied")
+	}
+	requested, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	inner := &types.DynamicFeeTx{
+		ChainID:   requested.ChainId(),
+		Nonce:     requested.Nonce(),
+		GasTipCap: requested.GasTipCap(),
+		GasFeeCap: requested.GasFeeCap(),
+		Gas:       requested.Gas(),
+		To:        requested.To(),
+		Value:     requested.Value(),
+		Data:      requested.Data(),
+	}
+	if stub.tamper != nil {
+		stub.tamper(inner)
+	}
+	signed, err := types.SignNewTx(stub.key, types.LatestSignerForChainID(inner.ChainID), inner)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &clefSignTransactionResult{Raw: raw, Tx: signed}, nil
+}
+
+func newClefStubSigner(t *testing.T, stub *clefStub, address common.Address) (*ClefSigner, error) {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", stub); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	s, err := NewClefSigner(httpServer.URL, address)
+	if err == nil {
+		t.Cleanup(s.Close)
+	}
+	return s, err
+}
+
+func testTypedData() apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": []apitypes.Type{{Name: "name", Type: "string"}},
+			"Mail":         []apitypes.Type{{Name: "contents", Type: "string"}},
+		},
+		Domain:      apitypes.TypedDataDomain{Name: "Test"},
+		PrimaryType: "Mail",
+		Message:     apitypes.TypedDataMessage{"contents": "hello"},
+	}
+}
+
+func testClefKeys(t *testing.T) (*ecdsa.PrivateKey, common.Address, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, crypto.PubkeyToAddress(key.PublicKey), other
+}
+
+func TestClefSignerAccounts(t *testing.T) {
+	key, address, other := testClefKeys(t)
+
+	s, err := newClefStubSigner(t, &clefStub{accounts: []common.Address{address}, key: key}, common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Address() != address {
+		t.Errorf("Address = %s, want %s", s.Address().Hex(), address.Hex())
+	}
+
+	accounts := []common.Address{address, crypto.PubkeyToAddress(other.PublicKey)}
+	if _, err := newClefStubSigner(t, &clefStub{accounts: accounts, key: key}, common.Address{}); err == nil {
+		t.Error("Clef with several accounts and no address chosen was accepted")
+	}
+	if _, err := newClefStubSigner(t, &clefStub{accounts: accounts, key: key}, address); err != nil {
+		t.Errorf("Clef with several accounts and an address chosen: %v", err)
+	}
+}
+
+func TestClefSignerSignTypedData(t *testing.T) {
+	key, address, other := testClefKeys(t)
+	typedData := testTypedData()
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := newClefStubSigner(t, &clefStub{key: key}, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := s.SignTypedData(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkSignature(common.BytesToHash(hash), signature, address); err != nil {
+		t.Error(err)
+	}
+
+	rejecting, err := newClefStubSigner(t, &clefStub{key: key, reject: true}, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rejecting.SignTypedData(typedData); err == nil {
+		t.Error("rejected request returned a signature")
+	}
+
+	wrongKey, err := newClefStubSigner(t, &clefStub{key: other}, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrongKey.SignTypedData(typedData); err == nil {
+		t.Error("signature from another account accepted")
+	}
+}
+
+func TestClefSignerSignTx(t *testing.T) {
+	key, address, other := testClefKeys(t)
+	chainID := big.NewInt(1337)
+	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     3,
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(100),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(5),
+	})
+
+	s, err := newClefStubSigner(t, &clefStub{key: key}, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := s.SignTx(tx, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed); err != nil || sender != address {
+		t.Errorf("signed transaction sender = %s, %v, want %s", sender.Hex(), err, address.Hex())
+	}
+	if signed.Hash() == tx.Hash() || signed.Nonce() != tx.Nonce() || signed.Value().Cmp(tx.Value()) != 0 {
+		t.Error("signed transaction does not match the request")
+	}
+
+	rejecting, err := newClefStubSigner(t, &clefStub{key: key, reject: true}, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rejecting.SignTx(tx, chainID); err == nil {
+		t.Error("rejected request returned a transaction")
+	}
+
+	wrongKey, err := newClefStubSigner(t, &clefStub{key: other}, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrongKey.SignTx(tx, chainID); err == nil {
+		t.Error("transaction signed by another account accepted")
+	}
+
+	tampers := map[string]func(*types.DynamicFeeTx){
+		"value":     func(inner *types.DynamicFeeTx) { inner.Value = big.NewInt(500) },
+		"nonce":     func(inner *types.DynamicFeeTx) { inner.Nonce++ },
+		"data":      func(inner *types.DynamicFeeTx) { inner.Data = []byte{0x01} },
+		"recipient": func(inner *types.DynamicFeeTx) { inner.To = &address },
+		"gas":       func(inner *types.DynamicFeeTx) { inner.Gas = 1_000_000 },
+		"fee cap":   func(inner *types.DynamicFeeTx) { inner.GasFeeCap = big.NewInt(1_000_000) },
+		"tip cap":   func(inner *types.DynamicFeeTx) { inner.GasTipCap = big.NewInt(99) },
+	}
+	for name, tamper := range tampers {
+		tampering, err := newClefStubSigner(t, &clefStub{key: key, tamper: tamper}, address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tampering.SignTx(tx, chainID); err == nil {
+			t.Errorf("transaction with a different %s from the request accepted", name)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tyler-smith/go-bip39/wordlists"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Derivation path of the first account of a mnemonic, as used by most wallets.
+const DefaultDerivationPath = "m/44'/60'/0'/0/0"
+
+// Derives the private key at a BIP-32 derivation path from a BIP-39 mnemonic and passphrase.
+func DeriveMnemonicKey(mnemonic string, passphrase string, derivationPath string) (*ecdsa.PrivateKey, error) {
+	seed, err := MnemonicSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return DeriveSeedKey(seed, derivationPath)
+}
+
+// Validates a BIP-39 mnemonic against the English word list and its checksum, and returns the seed it produces
+// with the given passphrase. Only ASCII mnemonics and passphrases are supported, since they need no Unicode
+// normalisation.
+func MnemonicSeed(mnemonic string, passphrase string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return nil, fmt.Errorf("mnemonic has %d words, expected 12, 15, 18, 21 or 24", len(words))
+	}
+	normalized := strings.Join(words, " ")
+	for _, r := range normalized + passphrase {
+		if r > 0x7f {
+			return nil, fmt.Errorf("only ASCII mnemonics and passphrases are supported")
+		}
+	}
+	if err := checkMnemonicChecksum(words); err != nil {
+		return nil, err
+	}
+
+	return pbkdf2.Key([]byte(normalized), []byte("mnemonic"+passphrase), 2048, 64, sha512.New), nil
+}
+
+// Checks that every word is in the English word list and that the last bits of the mnemonic match the checksum of
+// the entropy it encodes, so that a mistyped word is rejected instead of deriving a different key.
+func checkMnemonicChecksum(words []string) error {
+	indices := make(map[string]int, len(wordlists.English))
+	for i, word := range wordlists.English {
+		indices[word] = i
+	}
+
+	bits := new(big.Int)
+	for position, word := range words {
+		index, ok := indices[word]
+		if !ok {
+			return fmt.Errorf("mnemonic word %d (%q) is not in the BIP-39 English word list", position+1, word)
+		}
+		bits.Lsh(bits, 11)
+		bits.Or(bits, big.NewInt(int64(index)))
+	}
+
+	// Each word holds 11 bits, of which one in 33 is checksum.
+	checksumBits := uint(len(words) * 11 / 33)
+	entropyBytes := len(words) * 11 * 32 / 33 / 8
+	checksum := new(big.Int).And(bits, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), checksumBits), big.NewInt(1)))
+	entropy := new(big.Int).Rsh(bits, checksumBits).FillBytes(make([]byte, entropyBytes))
+
+	hash := sha256.Sum256(entropy)
+	expected := new(big.Int).Rsh(new(big.Int).SetBytes(hash[:1]), 8-checksumBits)
+	if checksum.Cmp(expected) != 0 {
+		return fmt.Errorf("invalid mnemonic checksum, check the words for typos")
+	}
+	return nil
+}
+
+// Derives the private key at a BIP-32 derivation path from a seed.
+func DeriveSeedKey(seed []byte, derivationPath string) (*ecdsa.PrivateKey, error) {
+	path, err := accounts.ParseDerivationPath(derivationPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derivation path %s: %w", derivationPath, err)
+	}
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	master := mac.Sum(nil)
+	key, chainCode := new(big.Int).SetBytes(master[:32]), master[32:]
+
+	curveOrder := crypto.S256().Params().N
+	if key.Sign() == 0 || key.Cmp(curveOrder) >= 0 {
+		return nil, fmt.Errorf("seed produces an invalid master key")
+	}
+
+	for _, index := range path {
+		data := make([]byte, 0, 37)
+		if index >= 0x80000000 {
+			data = append(data, 0)
+			data = append(data, scalarBytes(key)...)
+		} else {
+			privateKey, err := crypto.ToECDSA(scalarBytes(key))
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, crypto.CompressPubkey(&privateKey.PublicKey)...)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		child := mac.Sum(nil)
+
+		tweak := new(big.Int).SetBytes(child[:32])
+		if tweak.Cmp(curveOrder) >= 0 {
+			return nil, fmt.Errorf("derivation path %s produces an invalid key", derivationPath)
+		}
+		key = tweak.Add(tweak, key)
+		key.Mod(key, curveOrder)
+		if key.Sign() == 0 {
+			return nil, fmt.Errorf("derivation path %s produces an invalid key", derivationPath)
+		}
+		chainCode = child[32:]
+	}
+
+	return crypto.ToECDSA(scalarBytes(key))
+}
+
+// Left-pads a scalar to 32 bytes.
+func scalarBytes(value *big.Int) []byte {
+	return value.FillBytes(make([]byte, 32))
+}
@@ -0,0 +1,135 @@
+package signer
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Official BIP-39 test vectors, whose seeds use the passphrase "TREZOR".
+var bip39Vectors = []struct {
+	mnemonic string
+	seed     string
+}{
+	{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		"ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+	{
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+		"107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+	},
+	{
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+	{
+		"jelly better achieve collect unaware mountain thought cargo oxygen act hood bridge",
+		"b5b6d0127db1a9d2226af0c3346031d77af31e918dba64287a1b44b8ebf63cdd52676f672a290aae502472cf2d602c051f3e6f18055e84e4c43897fc4e51a6ff",
+	},
+	{
+		"renew stay biology evidence goat welcome casual join adapt armor shuffle fault little machine walk stumble urge swap",
+		"9248d83e06f4cd98debf5b6f010542760df925ce46cf38a1bdb4e4de7d21f5c39366941c69e1bdbf2966e0f6e6dbece898a0e2f0a4c2b3e640953dfe8b7bbdc5",
+	},
+	{
+		"beyond stage sleep clip because twist token leaf atom beauty genius food business side grid unable middle armed observe pair crouch tonight away coconut",
+		"b15509eaa2d09d3efd3e006ef42151b30367dc6e3aa5e44caba3fe4d3e352e65101fbdb86a96776b91946ff06f8eac594dc6ee1d3e82a42dfe1b40fef6bcc3fd",
+	},
+}
+
+func TestMnemonicSeed(t *testing.T) {
+	for _, vector := range bip39Vectors {
+		seed, err := MnemonicSeed(vector.mnemonic, "TREZOR")
+		if err != nil {
+			t.Errorf("MnemonicSeed(%q): %v", vector.mnemonic, err)
+			continue
+		}
+		if got := hex.EncodeToString(seed); got != vector.seed {
+			t.Errorf("MnemonicSeed(%q) = %s, want %s", vector.mnemonic, got, vector.seed)
+		}
+	}
+}
+
+func TestMnemonicSeedRejectsInvalidMnemonics(t *testing.T) {
+	invalid := []string{
+		// Wrong word counts.
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow yellow",
+		// Words outside the word list.
+		"letter advice cage absurd amount doctor acoustic avoid letter advice caged above",
+		"jello better achieve collect unaware mountain thought cargo oxygen act hood bridge",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo, wrong",
+		// Valid words with a bad checksum.
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon letter",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo why",
+		"legal winner thank year wave sausage worth useful legal winner thank year",
+	}
+	for _, mnemonic := range invalid {
+		if _, err := MnemonicSeed(mnemonic, ""); err == nil {
+			t.Errorf("MnemonicSeed(%q) accepted an invalid mnemonic", mnemonic)
+		}
+	}
+}
+
+// BIP-32 test vector 1.
+func TestDeriveSeedKey(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	cases := []struct {
+		path string
+		key  string
+	}{
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, c := range cases {
+		key, err := DeriveSeedKey(seed, c.path)
+		if err != nil {
+			t.Errorf("DeriveSeedKey(%s): %v", c.path, err)
+			continue
+		}
+		if got := hex.EncodeToString(crypto.FromECDSA(key)); got != c.key {
+			t.Errorf("DeriveSeedKey(%s) = %s, want %s", c.path, got, c.key)
+		}
+	}
+}
+
+func TestDeriveMnemonicKey(t *testing.T) {
+	mnemonic := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	key, err := DeriveMnemonicKey(mnemonic, "", DefaultDerivationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	if got := crypto.PubkeyToAddress(key.PublicKey); got != want {
+		t.Errorf("DeriveMnemonicKey = %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func TestMnemonicPassphraseDoesNotUseKeystorePassword(t *testing.T) {
+	t.Setenv(PasswordEnvVar, "keystore password")
+	t.Setenv(MnemonicPassphraseEnvVar, "")
+	t.Setenv("SAFES_TEST_MNEMONIC", bip39Vectors[0].mnemonic)
+
+	s, err := Open("mnemonic:env:SAFES_TEST_MNEMONIC", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94")
+	if s.Address() != want {
+		t.Errorf("mnemonic signer used $%s as its passphrase: got %s, want %s", PasswordEnvVar, s.Address().Hex(), want.Hex())
+	}
+}
@@ -0,0 +1,275 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Header used for remote signer requests when the configured auth value does not name one.
+const RemoteSignerDefaultAuthHeader = "Authorization"
+
+// Prefix of auth settings that name their own header, as in "header=X-Api-Key:secret".
+const RemoteSignerAuthHeaderPrefix = "header="
+
+// Request and response bodies of the remote signing protocol:
+//
+//	GET  ENDPOINT/keys/KEY_ID       -> {"address": "0x..."}
+//	POST ENDPOINT/keys/KEY_ID/sign  {"digest": "0x..."} -> {"signature": "0x..."}
+//
+// The signature is either DER encoded, as KMS services return it, or 64 or 65 bytes of r || s [|| v].
+type RemoteKeyResponse struct {
+	Address string `json:"address"`
+}
+
+type RemoteSignRequest struct {
+	Digest string `json:"digest"`
+}
+
+type RemoteSignResponse struct {
+	Signature string `json:"signature"`
+}
+
+// RemoteSigner signs 32-byte digests with a key held by an HTTP signing service. Whatever signature format the
+// service returns is normalised to low-s, and v is recovered by matching the key's address.
+type RemoteSigner struct {
+	endpoint   string
+	keyID      string
+	authHeader string
+	authValue  string
+	address    common.Address
+	httpClient *http.Client
+}
+
+// Creates a signer for key keyID of the service at endpoint. auth is sent with every request, as parsed by
+// ParseAuthHeader. If address is the zero address, it is fetched
+// from the service.
+func NewRemoteSigner(endpoint string, keyID string, auth string, address common.Address) (*RemoteSigner, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("no key ID given for remote signer %s", endpoint)
+	}
+
+	s := &RemoteSigner{
+		endpoint:   strings.TrimRight(endpoint, "/"),
+		keyID:      keyID,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	if auth != "" {
+		var err error
+		s.authHeader, s.authValue, err = ParseAuthHeader(auth)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if address == (common.Address{}) {
+		var response RemoteKeyResponse
+		if err := s.do(http.MethodGet, s.keyURL(), nil, &response); err != nil {
+			return nil, fmt.Errorf("failed to fetch address of key %s: %w", keyID, err)
+		}
+		if !common.IsHexAddress(response.Address) {
+			return nil, fmt.Errorf("remote signer returned an invalid address for key %s: %s", keyID, response.Address)
+		}
+		address = common.HexToAddress(response.Address)
+	}
+	s.address = address
+
+	return s, nil
+}
+
+// Splits an auth setting into a header name and value. Settings of the form "header=Header-Name:value" name their
+// header; anything else, including tokens that contain colons, is the value of the Authorization header.
+func ParseAuthHeader(auth string) (string, string, error) {
+	spec, named := strings.CutPrefix(auth, RemoteSignerAuthHeaderPrefix)
+	if !named {
+		return RemoteSignerDefaultAuthHeader, auth, nil
+	}
+	name, value, found := strings.Cut(spec, ":")
+	if !found || !isHeaderToken(name) {
+		return "", "", fmt.Errorf("auth setting must have the form %sHeader-Name:value", RemoteSignerAuthHeaderPrefix)
+	}
+	return name, strings.TrimSpace(value), nil
+}
+
+// Reports whether name is a valid HTTP header name (an RFC 9110 token).
+func isHeaderToken(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		if r > 0x7e || r <= 0x20 || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *RemoteSigner) SignHash(hash common.Hash) ([]byte, error) {
+	var response RemoteSignResponse
+	if err := s.do(http.MethodPost, s.keyURL()+"/sign", RemoteSignRequest{Digest: hash.Hex()}, &response); err != nil {
+		return nil, fmt.Errorf("remote signing with key %s failed: %w", s.keyID, err)
+	}
+
+	raw, err := hexutil.Decode(response.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("remote signer returned invalid signature hex: %w", err)
+	}
+	return NormalizeSignature(hash, raw, s.address)
+}
+
+func (s *RemoteSigner) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return s.SignHash(common.BytesToHash(hash))
+}
+
+func (s *RemoteSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	txSigner := types.LatestSignerForChainID(chainID)
+	signature, err := s.SignHash(txSigner.Hash(tx))
+	if err != nil {
+		return nil, err
+	}
+	signature[64] -= 27
+	return tx.WithSignature(txSigner, signature)
+}
+
+func (s *RemoteSigner) keyURL() string {
+	return s.endpoint + "/keys/" + url.PathEscape(s.keyID)
+}
+
+func (s *RemoteSigner) do(method string, endpoint string, payload interface{}, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("error marshaling payload: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.authHeader != "" {
+		req.Header.Set(s.authHeader, s.authValue)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(respBody))
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("error unmarshaling response: %w", err)
+	}
+	return nil
+}
+
+// Converts a secp256k1 signature of hash, either DER encoded or 64 or 65 bytes of r || s [|| v], to the 65-byte
+// r || s || v form with low s and v in {27, 28}. Any v in the input is ignored and recovered again by matching
+// expected, so that signatures from services which only return r and s can be used.
+func NormalizeSignature(hash common.Hash, signature []byte, expected common.Address) ([]byte, error) {
+	var r, s *big.Int
+	switch len(signature) {
+	case 64, 65:
+		r = new(big.Int).SetBytes(signature[:32])
+		s = new(big.Int).SetBytes(signature[32:64])
+	default:
+		var der struct {
+			R, S *big.Int
+		}
+		rest, err := asn1.Unmarshal(signature, &der)
+		if err != nil {
+			return nil, fmt.Errorf("signature of length %d is neither r || s || v nor DER encoded: %w", len(signature), err)
+		}
+		if len(rest) > 0 {
+			return nil, fmt.Errorf("trailing data after DER signature")
+		}
+		r, s = der.R, der.S
+	}
+
+	curveOrder := crypto.S256().Params().N
+	if r.Sign() <= 0 || s.Sign() <= 0 || r.Cmp(curveOrder) >= 0 || s.Cmp(curveOrder) >= 0 {
+		return nil, fmt.Errorf("signature r or s is out of range")
+	}
+	// Ethereum only accepts signatures in the lower half of the curve order (EIP-2), which KMS services do not
+	// guarantee.
+	if s.Cmp(new(big.Int).Rsh(curveOrder, 1)) > 0 {
+		s = new(big.Int).Sub(curveOrder, s)
+	}
+
+	normalized := make([]byte, 65)
+	r.FillBytes(normalized[:32])
+	s.FillBytes(normalized[32:64])
+	for v := byte(0); v < 2; v++ {
+		normalized[64] = v
+		publicKey, err := crypto.SigToPub(hash.Bytes(), normalized)
+		if err == nil && crypto.PubkeyToAddress(*publicKey) == expected {
+			normalized[64] += 27
+			return normalized, nil
+		}
+	}
+	return nil, fmt.Errorf("signature was not made by %s", expected.Hex())
+}
+
+func openRemote(spec string, password string) (Signer, error) {
+	endpoint, query, _ := strings.Cut(spec, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote signer options %q: %w", query, err)
+	}
+
+	var address common.Address
+	if raw := params.Get("address"); raw != "" {
+		if !common.IsHexAddress(raw) {
+			return nil, fmt.Errorf("invalid remote signer address: %s", raw)
+		}
+		address = common.HexToAddress(raw)
+	}
+
+	// The auth header is a secret, so like a password it is best read from a file or environment variable.
+	auth := params.Get("auth")
+	if auth == "" {
+		auth = password
+	}
+	if auth != "" {
+		auth, err = ResolvePassword(auth)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return NewRemoteSigner(endpoint, params.Get("key"), auth, address)
+}
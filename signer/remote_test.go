@@ -0,0 +1,170 @@
+package signer
+
+import (
+	"encoding/asn1"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestNormalizeSignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	hash := crypto.Keccak256Hash([]byte("digest"))
+
+	signature, err := crypto.Sign(hash.Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte{}, signature...)
+	want[64] += 27
+
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
+	highS := new(big.Int).Sub(crypto.S256().Params().N, s)
+
+	der, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	highSDER, err := asn1.Marshal(struct{ R, S *big.Int }{r, highS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	highSRaw := append(append(r.FillBytes(make([]byte, 32)), highS.FillBytes(make([]byte, 32))...), 1-signature[64])
+
+	cases := []struct {
+		name      string
+		signature []byte
+	}{
+		{"65 bytes", signature},
+		{"65 bytes with v of 27 or 28", want},
+		{"65 bytes with a wrong v", append(append([]byte{}, signature[:64]...), 1-signature[64])},
+		{"64 bytes", signature[:64]},
+		{"DER", der},
+		{"high s DER", highSDER},
+		{"high s raw", highSRaw},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := NormalizeSignature(hash, c.signature, address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if common.Bytes2Hex(got) != common.Bytes2Hex(want) {
+				t.Errorf("NormalizeSignature = %x, want %x", got, want)
+			}
+		})
+	}
+
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid := map[string][]byte{
+		"wrong key":        signature,
+		"garbage":          []byte{1, 2, 3},
+		"trailing data":    append(append([]byte{}, der...), 0),
+		"zero r":           append(make([]byte, 32), signature[32:]...),
+		"s of curve order": append(append([]byte{}, signature[:32]...), crypto.S256().Params().N.FillBytes(make([]byte, 32))...),
+	}
+	for name, signature := range invalid {
+		expected := address
+		if name == "wrong key" {
+			expected = crypto.PubkeyToAddress(other.PublicKey)
+		}
+		if _, err := NormalizeSignature(hash, signature, expected); err == nil {
+			t.Errorf("%s: NormalizeSignature accepted %x", name, signature)
+		}
+	}
+}
+
+func TestParseAuthHeader(t *testing.T) {
+	cases := []struct {
+		auth, header, value string
+	}{
+		{"Bearer abc:def", "Authorization", "Bearer abc:def"},
+		{"Basic dXNlcjpwYXNz", "Authorization", "Basic dXNlcjpwYXNz"},
+		{"token:with:colons", "Authorization", "token:with:colons"},
+		{"X-Api-Key: secret", "Authorization", "X-Api-Key: secret"},
+		{"header=X-Api-Key:secret", "X-Api-Key", "secret"},
+		{"header=X-Api-Key: secret:with:colons", "X-Api-Key", "secret:with:colons"},
+	}
+	for _, c := range cases {
+		header, value, err := ParseAuthHeader(c.auth)
+		if err != nil {
+			t.Errorf("ParseAuthHeader(%q): %v", c.auth, err)
+			continue
+		}
+		if header != c.header || value != c.value {
+			t.Errorf("ParseAuthHeader(%q) = %q, %q, want %q, %q", c.auth, header, value, c.header, c.value)
+		}
+	}
+
+	for _, auth := range []string{"header=", "header=X-Api-Key", "header=Bad Name:value", "header=:value", "header=X(y):value"} {
+		if _, _, err := ParseAuthHeader(auth); err == nil {
+			t.Errorf("ParseAuthHeader(%q) accepted an invalid header", auth)
+		}
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := NewKeySigner(key)
+	hash := crypto.Keccak256Hash([]byte("digest"))
+	want, err := local.SignHash(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, der := range []bool{false, true} {
+		server := httptest.NewServer(&RemoteSignerServer{
+			Keys:       map[string]Signer{"main": local, "other": NewKeySigner(other)},
+			AuthHeader: "Authorization",
+			AuthValue:  "Bearer abc:def",
+			DER:        der,
+		})
+		defer server.Close()
+
+		remote, err := NewRemoteSigner(server.URL, "main", "Bearer abc:def", common.Address{})
+		if err != nil {
+			t.Fatalf("der=%v: %v", der, err)
+		}
+		if remote.Address() != local.Address() {
+			t.Errorf("der=%v: address %s, want %s", der, remote.Address().Hex(), local.Address().Hex())
+		}
+		got, err := remote.SignHash(hash)
+		if err != nil {
+			t.Fatalf("der=%v: %v", der, err)
+		}
+		if common.Bytes2Hex(got) != common.Bytes2Hex(want) {
+			t.Errorf("der=%v: SignHash = %x, want %x", der, got, want)
+		}
+
+		// A service that signs with another key than the configured address is rejected.
+		wrong, err := NewRemoteSigner(server.URL, "other", "Bearer abc:def", local.Address())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wrong.SignHash(hash); err == nil {
+			t.Errorf("der=%v: signature from the wrong key accepted", der)
+		}
+
+		if _, err := NewRemoteSigner(server.URL, "main", "Bearer wrong", common.Address{}); err == nil {
+			t.Errorf("der=%v: wrong auth accepted", der)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package signer
+
+import (
+	"crypto/subtle"
+	"encoding/asn1"
+	"encoding/json"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// RemoteSignerServer is a reference implementation of the service RemoteSigner talks to, signing digests with
+// local signers. It is meant for testing integrations, not for holding production keys.
+type RemoteSignerServer struct {
+	// Signers by key ID.
+	Keys map[string]Signer
+	// If AuthHeader is set, requests must carry it with the value AuthValue.
+	AuthHeader string
+	AuthValue  string
+	// Return DER encoded signatures without v, as KMS services do, instead of 65-byte r || s || v.
+	DER bool
+}
+
+func (server *RemoteSignerServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if server.AuthHeader != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(server.AuthHeader)), []byte(server.AuthValue)) != 1 {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/keys/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	keyID, action, _ := strings.Cut(path, "/")
+	key, ok := server.Keys[keyID]
+	if !ok {
+		http.Error(w, "unknown key", http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		writeJSON(w, RemoteKeyResponse{Address: key.Address().Hex()})
+	case action == "sign" && r.Method == http.MethodPost:
+		var request RemoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		digest, err := hexutil.Decode(request.Digest)
+		if err != nil || len(digest) != common.HashLength {
+			http.Error(w, "digest must be 32 hex-encoded bytes", http.StatusBadRequest)
+			return
+		}
+
+		signature, err := key.SignHash(common.BytesToHash(digest))
+		if err != nil {
+			log.Printf("signing with key %s failed: %v", keyID, err)
+			http.Error(w, "signing failed", http.StatusInternalServerError)
+			return
+		}
+		if server.DER {
+			signature, err = asn1.Marshal(struct{ R, S *big.Int }{new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:64])})
+			if err != nil {
+				http.Error(w, "encoding failed", http.StatusInternalServerError)
+				return
+			}
+		}
+		log.Printf("signed digest %s with key %s (%s)", request.Digest, keyID, key.Address().Hex())
+		writeJSON(w, RemoteSignResponse{Signature: hexutil.Encode(signature)})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("failed to write response: %v", err)
+	}
+}
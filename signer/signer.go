@@ -0,0 +1,82 @@
+// Package signer abstracts over the accounts the CLI signs with. Commands take a signer specification (see Open)
+// instead of a keystore path, so that the same command can sign with a keystore, a raw private key or a mnemonic.
+package signer
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Signer signs on behalf of a single account. Signatures are 65 bytes, r || s || v, with v either 27 or 28.
+type Signer interface {
+	// Address of the signing account.
+	Address() common.Address
+	// Signs a 32 byte hash as is, without any prefix. Backends which only sign structured data may not support this.
+	SignHash(hash common.Hash) ([]byte, error)
+	// Signs EIP-712 typed data, as eth_signTypedData_v4 does.
+	SignTypedData(typedData apitypes.TypedData) ([]byte, error)
+	// Signs a transaction for the given chain.
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// Returns transaction options which sign with the given signer, as bind.NewKeyedTransactorWithChainID does for a
+// private key.
+func NewTransactor(s Signer, chainID *big.Int) (*bind.TransactOpts, error) {
+	if chainID == nil {
+		return nil, bind.ErrNoChainID
+	}
+	from := s.Address()
+	return &bind.TransactOpts{
+		From: from,
+		Signer: func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if address != from {
+				return nil, bind.ErrNotAuthorized
+			}
+			return s.SignTx(tx, chainID)
+		},
+	}, nil
+}
+
+// KeySigner signs with a private key held in memory.
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+func NewKeySigner(key *ecdsa.PrivateKey) *KeySigner {
+	return &KeySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func (s *KeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *KeySigner) SignHash(hash common.Hash) ([]byte, error) {
+	signature, err := crypto.Sign(hash.Bytes(), s.key)
+	if err != nil {
+		return nil, err
+	}
+
+	// Adjust the V value for Ethereum signature replay protection
+	signature[64] += 27
+	return signature, nil
+}
+
+func (s *KeySigner) SignTypedData(typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return s.SignHash(common.BytesToHash(hash))
+}
+
+func (s *KeySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
@@ -0,0 +1,223 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/term"
+)
+
+// Environment variable holding the keystore password when no password is given.
+const PasswordEnvVar = "SAFES_PASSWORD"
+
+// Environment variable holding the mnemonic passphrase when no password is given. It is separate from
+// PasswordEnvVar so that a keystore password is never used as a passphrase by accident.
+const MnemonicPassphraseEnvVar = "SAFES_MNEMONIC_PASSPHRASE"
+
+// Flag help listing the signer specifications accepted by Open.
+const SpecUsage = "keystore path, env:VAR, hexfile:PATH, mnemonic:SOURCE[?path=DERIVATION], clef:ENDPOINT[?address=ADDRESS] or remote:URL?key=ID"
+
+// Flag help for the password passed to Open.
+const PasswordUsage = "Keystore password or mnemonic passphrase, or file:PATH or env:VAR to read it (defaults to $" + PasswordEnvVar + ", or $" + MnemonicPassphraseEnvVar + " for mnemonics; keystores prompt for a missing password)"
+
+// Opens the signer described by spec. The supported forms are:
+//
+//	PATH or keystore:PATH                            encrypted JSON keystore
+//	env:VAR                                          hex private key in an environment variable
+//	hexfile:PATH                                     hex private key in a file
+//	mnemonic:SOURCE[?path=DERIVATION]                BIP-39 mnemonic, where SOURCE is a file path or env:VAR
+//	clef:ENDPOINT[?address=ADDRESS]                  Clef external signer at an IPC path or HTTP URL
+//	remote:URL?key=ID[&address=ADDRESS][&auth=AUTH]  HTTP signing service, see RemoteSigner
+//
+// The mnemonic derivation path defaults to DefaultDerivationPath. The password unlocks keystores and is used as
+// the mnemonic passphrase; it is resolved with ResolvePassword, except that an empty mnemonic passphrase falls back
+// to MnemonicPassphraseEnvVar instead of PasswordEnvVar. A keystore without a password prompts for one. The remote
+// signer's auth header is taken from the auth option, or else the password, and resolved the same way.
+func Open(spec string, password string) (Signer, error) {
+	if spec == "" {
+		return nil, fmt.Errorf("no signer specified")
+	}
+
+	scheme, rest, found := strings.Cut(spec, ":")
+	if !found {
+		return openKeystore(spec, password)
+	}
+
+	switch scheme {
+	case "keystore":
+		return openKeystore(rest, password)
+	case "env":
+		value, ok := os.LookupEnv(rest)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", rest)
+		}
+		return signerFromHex(value, "environment variable "+rest)
+	case "hexfile":
+		content, err := os.ReadFile(rest)
+		if err != nil {
+			return nil, err
+		}
+		return signerFromHex(string(content), rest)
+	case "mnemonic":
+		return openMnemonic(rest, password)
+	case "clef":
+		return openClef(rest)
+	case "remote":
+		return openRemote(rest, password)
+	}
+
+	// Anything else, for example a Windows drive letter, is taken to be a keystore path.
+	return openKeystore(spec, password)
+}
+
+// Returns the address of the signer described by spec. Keystores are not decrypted, since they record their
+// address in the clear.
+func AddressFromSpec(spec string, password string) (common.Address, error) {
+	path, isKeystore := keystorePath(spec)
+	if !isKeystore {
+		s, err := Open(spec, password)
+		if err != nil {
+			return common.Address{}, err
+		}
+		return s.Address(), nil
+	}
+
+	keystoreContent, err := os.ReadFile(path)
+	if err != nil {
+		return common.Address{}, err
+	}
+	var keystoreJSON struct {
+		Address string `json:"address"`
+	}
+	if err := json.Unmarshal(keystoreContent, &keystoreJSON); err != nil {
+		return common.Address{}, fmt.Errorf("error parsing keystore file: %w", err)
+	}
+	if !common.IsHexAddress(keystoreJSON.Address) {
+		return common.Address{}, fmt.Errorf("keystore file %s does not contain a valid address", path)
+	}
+	return common.HexToAddress(keystoreJSON.Address), nil
+}
+
+// Resolves a password flag value. Values of the form file:PATH are read from a file (without its trailing
+// newline) and env:VAR from an environment variable; anything else is the password itself. An empty value falls
+// back to the PasswordEnvVar environment variable.
+func ResolvePassword(value string) (string, error) {
+	return resolveSecret(value, PasswordEnvVar)
+}
+
+// Resolves a secret flag value as ResolvePassword does, falling back to the given environment variable.
+func resolveSecret(value string, fallbackEnvVar string) (string, error) {
+	switch {
+	case value == "":
+		return os.Getenv(fallbackEnvVar), nil
+	case strings.HasPrefix(value, "file:"):
+		content, err := os.ReadFile(strings.TrimPrefix(value, "file:"))
+		if err != nil {
+			return "", fmt.Errorf("error reading password file: %w", err)
+		}
+		return strings.TrimRight(string(content), "\r\n"), nil
+	case strings.HasPrefix(value, "env:"):
+		name := strings.TrimPrefix(value, "env:")
+		password, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("password environment variable %s is not set", name)
+		}
+		return password, nil
+	}
+	return value, nil
+}
+
+func keystorePath(spec string) (string, bool) {
+	scheme, rest, found := strings.Cut(spec, ":")
+	if !found {
+		return spec, true
+	}
+	switch scheme {
+	case "keystore":
+		return rest, true
+	case "env", "hexfile", "mnemonic", "clef", "remote":
+		return "", false
+	}
+	return spec, true
+}
+
+func openKeystore(keystoreFile string, password string) (Signer, error) {
+	keystoreContent, err := os.ReadFile(keystoreFile)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err = ResolvePassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	// If there is still no password, prompt user for password.
+	if password == "" {
+		fmt.Printf("Please provide a password for keystore (%s): ", keystoreFile)
+		passwordRaw, inputErr := term.ReadPassword(int(os.Stdin.Fd()))
+		if inputErr != nil {
+			return nil, fmt.Errorf("error reading password: %s", inputErr.Error())
+		}
+		fmt.Print("\n")
+		password = string(passwordRaw)
+	}
+
+	key, err := keystore.DecryptKey(keystoreContent, password)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+func signerFromHex(value string, source string) (Signer, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(strings.TrimSpace(value), "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key in %s: %w", source, err)
+	}
+	return NewKeySigner(key), nil
+}
+
+func openMnemonic(spec string, password string) (Signer, error) {
+	source, query, _ := strings.Cut(spec, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mnemonic options %q: %w", query, err)
+	}
+	path := DefaultDerivationPath
+	if params.Has("path") {
+		path = params.Get("path")
+	}
+
+	var mnemonic string
+	if name, ok := strings.CutPrefix(source, "env:"); ok {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, fmt.Errorf("environment variable %s is not set", name)
+		}
+		mnemonic = value
+	} else {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return nil, err
+		}
+		mnemonic = string(content)
+	}
+
+	passphrase, err := resolveSecret(password, MnemonicPassphraseEnvVar)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := DeriveMnemonicKey(mnemonic, passphrase, path)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySigner(key), nil
+}
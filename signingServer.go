@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/G7DAO/safes/signer"
+	"github.com/spf13/cobra"
+)
+
+func CreateSigningServerCmd() *cobra.Command {
+	var (
+		listen   string
+		keySpecs []string
+		password string
+		auth     string
+		der      bool
+	)
+
+	signingServerCmd := &cobra.Command{
+		Use:   "signing-server",
+		Short: "Run the reference remote signing service",
+		Long: `Run a reference implementation of the HTTP signing service used by remote: signers, signing digests with local
+signers. It serves GET /keys/KEY_ID and POST /keys/KEY_ID/sign, and is meant for testing remote signer integrations.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(keySpecs) == 0 {
+				return fmt.Errorf("at least one --key must be specified")
+			}
+			for _, keySpec := range keySpecs {
+				if keyID, spec, found := strings.Cut(keySpec, "="); !found || keyID == "" || spec == "" {
+					return fmt.Errorf("invalid --key %s, expected KEY_ID=SIGNER", keySpec)
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			server := &signer.RemoteSignerServer{Keys: make(map[string]signer.Signer), DER: der}
+
+			for _, keySpec := range keySpecs {
+				keyID, spec, _ := strings.Cut(keySpec, "=")
+				if _, exists := server.Keys[keyID]; exists {
+					return fmt.Errorf("key ID %s specified more than once", keyID)
+				}
+				key, err := signer.Open(spec, password)
+				if err != nil {
+					return fmt.Errorf("failed to open signer for key %s: %w", keyID, err)
+				}
+				server.Keys[keyID] = key
+				cmd.Printf("Key %s: %s\n", keyID, key.Address().Hex())
+			}
+
+			if auth != "" {
+				resolved, err := signer.ResolvePassword(auth)
+				if err != nil {
+					return err
+				}
+				server.AuthHeader, server.AuthValue, err = signer.ParseAuthHeader(resolved)
+				if err != nil {
+					return err
+				}
+			} else {
+				cmd.Println("Warning: no --auth given, anyone who can reach the server can sign with its keys")
+			}
+
+			cmd.Printf("Listening on %s\n", listen)
+			return http.ListenAndServe(listen, server)
+		},
+	}
+
+	signingServerCmd.Flags().StringVar(&listen, "listen", "127.0.0.1:8551", "Address to listen on")
+	signingServerCmd.Flags().StringArrayVar(&keySpecs, "key", nil, "Key to serve as KEY_ID=SIGNER, where SIGNER is any signer specification accepted by --keyfile (repeatable)")
+	signingServerCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	signingServerCmd.Flags().StringVar(&auth, "auth", "", "Auth header clients must send, as an Authorization value or \"header=Header-Name:value\", or file:PATH or env:VAR to read it")
+	signingServerCmd.Flags().BoolVar(&der, "der", false, "Return DER encoded signatures without v, as KMS services do")
+
+	return signingServerCmd
+}
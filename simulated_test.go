@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"sort"
+	"testing"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/bindings/SafeProxy"
+	"github.com/G7DAO/safes/bindings/SafeProxyFactory"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// Chain ID of the simulated backend.
+var simulatedChainID = big.NewInt(1337)
+
+// A simulated chain with a funded deployer account.
+type testChain struct {
+	backend  *simulated.Backend
+	client   simulated.Client
+	deployer *bind.TransactOpts
+}
+
+func newTestChain(t *testing.T) *testChain {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployer, err := bind.NewKeyedTransactorWithChainID(key, simulatedChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := simulated.NewBackend(types.GenesisAlloc{deployer.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)}})
+	t.Cleanup(func() { backend.Close() })
+	return &testChain{backend: backend, client: backend.Client(), deployer: deployer}
+}
+
+// Deploys a 1.4.1 Safe behind a proxy and sets it up with the given owners and threshold.
+func (c *testChain) deploySafe(t *testing.T, owners []common.Address, threshold int64) common.Address {
+	t.Helper()
+	return c.deploySafeWithFallbackHandler(t, owners, threshold, common.Address{})
+}
+
+// As deploySafe, with the given fallback handler set during setup.
+func (c *testChain) deploySafeWithFallbackHandler(t *testing.T, owners []common.Address, threshold int64, fallbackHandler common.Address) common.Address {
+	t.Helper()
+	singleton, _, _, err := Safe.DeploySafe(c.deployer, c.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.backend.Commit()
+	proxy, _, _, err := SafeProxy.DeploySafeProxy(c.deployer, c.client, singleton)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.backend.Commit()
+	safeInstance, err := Safe.NewSafe(proxy, c.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := safeInstance.Setup(c.deployer, owners, big.NewInt(threshold), common.Address{}, nil, fallbackHandler, common.Address{}, big.NewInt(0), common.Address{}); err != nil {
+		t.Fatal(err)
+	}
+	c.backend.Commit()
+	return proxy
+}
+
+// Generates n keys and their addresses.
+func testKeys(t *testing.T, n int) ([]*ecdsa.PrivateKey, []common.Address) {
+	t.Helper()
+	keys := make([]*ecdsa.PrivateKey, n)
+	addresses := make([]common.Address, n)
+	for i := range keys {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = key
+		addresses[i] = crypto.PubkeyToAddress(key.PublicKey)
+	}
+	return keys, addresses
+}
+
+// Signs a SafeTxHash as an ECDSA owner signature, with v = 27 or 28.
+func signSafeTxHash(t *testing.T, key *ecdsa.PrivateKey, safeTxHash common.Hash) []byte {
+	t.Helper()
+	signature, err := crypto.Sign(safeTxHash.Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature[64] += 27
+	return signature
+}
+
+// Deploys a 1.4.1 SafeProxyFactory and Safe singleton, returning their addresses.
+func (c *testChain) deploySafeFactory(t *testing.T) (common.Address, common.Address) {
+	t.Helper()
+	factory, _, _, err := SafeProxyFactory.DeploySafeProxyFactory(c.deployer, c.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	singleton, _, _, err := Safe.DeploySafe(c.deployer, c.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.backend.Commit()
+	return factory, singleton
+}
+
+// Executes a call from a Safe to itself, signed by keys, which must reach the threshold. Returns an error if the
+// transaction would revert.
+func (c *testChain) execSelfCall(t *testing.T, safeAddress common.Address, keys []*ecdsa.PrivateKey, data []byte) error {
+	t.Helper()
+	safeInstance, err := Safe.NewSafe(safeAddress, c.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nonce, err := safeInstance.Nonce(&bind.CallOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	zero := big.NewInt(0)
+	hash, err := safeInstance.GetTransactionHash(&bind.CallOpts{}, safeAddress, zero, data, 0, zero, zero, zero, common.Address{}, common.Address{}, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sorted := append([]*ecdsa.PrivateKey{}, keys...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(crypto.PubkeyToAddress(sorted[i].PublicKey).Bytes(), crypto.PubkeyToAddress(sorted[j].PublicKey).Bytes()) < 0
+	})
+	var signatures []byte
+	for _, key := range sorted {
+		signatures = append(signatures, signSafeTxHash(t, key, hash)...)
+	}
+
+	if _, err := safeInstance.ExecTransaction(c.deployer, safeAddress, zero, data, 0, zero, zero, zero, common.Address{}, common.Address{}, signatures); err != nil {
+		return err
+	}
+	c.backend.Commit()
+	return nil
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/G7DAO/safes/bindings/CompatibilityFallbackHandler"
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/safetx"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const simulateTxAccessorABI = `[{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"bytes","name":"data","type":"bytes"},{"internalType":"enum Enum.Operation","name":"operation","type":"uint8"}],"name":"simulate","outputs":[{"internalType":"uint256","name":"estimate","type":"uint256"},{"internalType":"bool","name":"success","type":"bool"},{"internalType":"bytes","name":"returnData","type":"bytes"}],"stateMutability":"nonpayable","type":"function"}]`
+
+// Canonical SimulateTxAccessor deployments, by Safe version. As for MultiSend, the first candidate with code is used.
+var SimulateTxAccessorDeployments = map[string][]common.Address{
+	"1.3.0": {
+		common.HexToAddress("0x59AD6735bCd8152B84860Cb256dD9e96b85F69Da"),
+		common.HexToAddress("0x727a77a074D1E6c4530e814F89E618a3298FC044"),
+	},
+	"1.4.1": {common.HexToAddress("0x3d4BA2E0884aa488718476ca2FB8Efc291A46199")},
+	"1.5.0": {common.HexToAddress("0x07EfA797c55B5DdE3698d876b277aBb6B893654C")},
+}
+
+// Percentage added to the simulated gas when it is used as safeTxGas, since the simulation runs with warm storage
+// and without the Safe's own execution overhead.
+const SafeTxGasEstimateMarginPercent = 10
+
+// SafeTxSimulation is the outcome of running the inner call of a SafeTx through SimulateTxAccessor.
+type SafeTxSimulation struct {
+	Accessor   common.Address
+	GasUsed    uint64
+	Success    bool
+	ReturnData []byte
+}
+
+// The safeTxGas to use for a simulated SafeTx: the measured gas plus SafeTxGasEstimateMarginPercent.
+func (s SafeTxSimulation) SafeTxGas() uint64 {
+	return s.GasUsed + s.GasUsed*SafeTxGasEstimateMarginPercent/100
+}
+
+// Returns the version of the given Safe, failing if it predates simulateAndRevert and SimulateTxAccessor, which were
+// added in 1.3.0.
+func SafeSimulationVersion(safeAddress common.Address, client bind.ContractCaller) (string, error) {
+	safeCaller, err := Safe.NewSafeCaller(safeAddress, client)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Safe caller: %w", err)
+	}
+
+	version, err := safeCaller.VERSION(&bind.CallOpts{})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch Safe version: %w", err)
+	}
+
+	supported, err := safetx.VersionAtLeast(version, 1, 3)
+	if err != nil {
+		return "", err
+	}
+	if !supported {
+		return "", fmt.Errorf("Safe version %s cannot simulate transactions: simulateAndRevert and SimulateTxAccessor were added in 1.3.0", version)
+	}
+	return version, nil
+}
+
+// Returns the SimulateTxAccessor matching the version of the given Safe, checking that it has code deployed.
+func ResolveSimulateTxAccessor(safeAddress common.Address, client bind.ContractCaller) (common.Address, error) {
+	version, err := SafeSimulationVersion(safeAddress, client)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	candidates := SimulateTxAccessorDeployments[version]
+	if len(candidates) == 0 {
+		return common.Address{}, fmt.Errorf("Safe version %s does not support simulation, or has no known SimulateTxAccessor (use --simulate-tx-accessor)", version)
+	}
+
+	for _, candidate := range candidates {
+		code, err := client.CodeAt(context.Background(), candidate, nil)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("failed to fetch code at %s: %w", candidate.Hex(), err)
+		}
+		if len(code) > 0 {
+			return candidate, nil
+		}
+	}
+
+	return common.Address{}, fmt.Errorf("no SimulateTxAccessor for Safe version %s found on this chain, please pass --simulate-tx-accessor", version)
+}
+
+// Simulates the inner call of a SafeTx from the Safe's context without executing it. The call goes through the
+// fallback handler's simulate method, which has the Safe delegatecall SimulateTxAccessor via simulateAndRevert. If
+// the Safe has no CompatibilityFallbackHandler, simulateAndRevert is called directly and its revert data decoded.
+func SimulateSafeTransaction(safeAddress common.Address, to common.Address, value *big.Int, data []byte, operation uint8, accessor common.Address, client bind.ContractCaller) (*SafeTxSimulation, error) {
+	if _, err := SafeSimulationVersion(safeAddress, client); err != nil {
+		return nil, err
+	}
+
+	accessorAbi, err := abi.JSON(strings.NewReader(simulateTxAccessorABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SimulateTxAccessor ABI: %w", err)
+	}
+	payload, err := accessorAbi.Pack("simulate", to, value, data, operation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode simulation: %w", err)
+	}
+
+	response, handlerErr := simulateThroughHandler(safeAddress, accessor, payload, client)
+	if handlerErr != nil {
+		var err error
+		response, err = simulateAndRevert(safeAddress, accessor, payload, client)
+		if err != nil {
+			return nil, fmt.Errorf("simulation failed through the fallback handler (%v) and through simulateAndRevert (%w)", handlerErr, err)
+		}
+	}
+
+	values, err := accessorAbi.Methods["simulate"].Outputs.Unpack(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode simulation result: %w", err)
+	}
+
+	estimate := values[0].(*big.Int)
+	if !estimate.IsUint64() {
+		return nil, fmt.Errorf("invalid gas estimate: %s", estimate.String())
+	}
+
+	return &SafeTxSimulation{
+		Accessor:   accessor,
+		GasUsed:    estimate.Uint64(),
+		Success:    values[1].(bool),
+		ReturnData: values[2].([]byte),
+	}, nil
+}
+
+func simulateThroughHandler(safeAddress common.Address, accessor common.Address, payload []byte, client bind.ContractCaller) ([]byte, error) {
+	handlerAbi, err := CompatibilityFallbackHandler.CompatibilityFallbackHandlerMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	input, err := handlerAbi.Pack("simulate", accessor, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &safeAddress, Data: input}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := handlerAbi.Methods["simulate"].Outputs.Unpack(output)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].([]byte), nil
+}
+
+// simulateAndRevert always reverts, with the delegatecall's success flag, the length of its return data and the
+// return data itself.
+func simulateAndRevert(safeAddress common.Address, accessor common.Address, payload []byte, client bind.ContractCaller) ([]byte, error) {
+	safeAbi, err := Safe.SafeMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	input, err := safeAbi.Pack("simulateAndRevert", accessor, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = client.CallContract(context.Background(), ethereum.CallMsg{To: &safeAddress, Data: input}, nil)
+	if err == nil {
+		return nil, fmt.Errorf("simulateAndRevert did not revert")
+	}
+	dataErr, ok := err.(rpc.DataError)
+	if !ok {
+		return nil, err
+	}
+	raw, ok := dataErr.ErrorData().(string)
+	if !ok {
+		return nil, err
+	}
+	revertData, decodeErr := hexutil.Decode(raw)
+	if decodeErr != nil || len(revertData) < 64 {
+		return nil, err
+	}
+
+	length := new(big.Int).SetBytes(revertData[32:64])
+	if !length.IsInt64() || length.Int64() > int64(len(revertData)-64) {
+		return nil, fmt.Errorf("malformed simulateAndRevert result")
+	}
+	result := revertData[64 : 64+length.Int64()]
+	if new(big.Int).SetBytes(revertData[:32]).Sign() == 0 {
+		return nil, fmt.Errorf("SimulateTxAccessor call failed: %s", RevertReason(result))
+	}
+	return result, nil
+}
+
+// Decodes revert data: Error(string) and Panic(uint256) reverts, and data whose selector is in the local signature
+// registry. Anything else is returned as hex.
+func RevertReason(data []byte) string {
+	if len(data) == 0 {
+		return "(no revert data)"
+	}
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+	if method, values, ok := DefaultSignatureRegistry().Decode(data); ok {
+		args := make([]string, len(values))
+		for i, value := range values {
+			args[i] = FormatABIValue(value)
+		}
+		return fmt.Sprintf("%s(%s)", method.RawName, strings.Join(args, ", "))
+	}
+	return "0x" + common.Bytes2Hex(data)
+}
+
+func PrintSafeTxSimulation(simulation *SafeTxSimulation) {
+	fmt.Printf("Simulation (via SimulateTxAccessor at %s):\n", simulation.Accessor.Hex())
+	fmt.Printf("  Success:        %t\n", simulation.Success)
+	fmt.Printf("  Gas used:       %d\n", simulation.GasUsed)
+	fmt.Printf("  Suggested safeTxGas: %d (gas used + %d%%)\n", simulation.SafeTxGas(), SafeTxGasEstimateMarginPercent)
+	if simulation.Success {
+		fmt.Printf("  Return data:    0x%s\n", common.Bytes2Hex(simulation.ReturnData))
+	} else {
+		fmt.Printf("  Revert reason:  %s\n", RevertReason(simulation.ReturnData))
+	}
+}
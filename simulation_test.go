@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/G7DAO/safes/bindings/CompatibilityFallbackHandler"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Runtime of a stand-in for SimulateTxAccessor. Like simulate(to, value, data, operation) in the real contract it
+// calls to with value and data, measures the gas the call used and returns (estimate, success, returnData). It
+// always uses CALL, and skips the accessor's check that it is being delegatecalled.
+var simulateTxAccessorRuntime = []byte{
+	0x5a,                                     // GAS
+	0x60, 0x04, 0x60, 0x44, 0x35, 0x01, 0x80, // PUSH1 4 PUSH1 0x44 CALLDATALOAD ADD DUP1
+	0x35, 0x90, 0x60, 0x20, 0x01, 0x81, 0x90, // CALLDATALOAD SWAP1 PUSH1 32 ADD DUP2 SWAP1
+	0x61, 0x10, 0x00, 0x37, // PUSH2 0x1000 CALLDATACOPY
+	0x60, 0x00, 0x60, 0x00, 0x82, 0x61, 0x10, 0x00, // PUSH1 0 PUSH1 0 DUP3 PUSH2 0x1000
+	0x60, 0x24, 0x35, 0x60, 0x04, 0x35, 0x5a, 0xf1, // PUSH1 0x24 CALLDATALOAD PUSH1 4 CALLDATALOAD GAS CALL
+	0x90, 0x50, 0x5a, 0x90, 0x91, 0x03, // SWAP1 POP GAS SWAP1 SWAP2 SUB
+	0x60, 0x00, 0x52, 0x60, 0x20, 0x52, // PUSH1 0 MSTORE PUSH1 32 MSTORE
+	0x60, 0x60, 0x60, 0x40, 0x52, // PUSH1 0x60 PUSH1 0x40 MSTORE
+	0x3d, 0x60, 0x60, 0x52, // RETURNDATASIZE PUSH1 0x60 MSTORE
+	0x3d, 0x60, 0x00, 0x60, 0x80, 0x3e, // RETURNDATASIZE PUSH1 0 PUSH1 0x80 RETURNDATACOPY
+	0x60, 0x20, 0x60, 0x1f, 0x3d, 0x01, 0x04, // PUSH1 32 PUSH1 31 RETURNDATASIZE ADD DIV
+	0x60, 0x20, 0x02, 0x60, 0x80, 0x01, // PUSH1 32 MUL PUSH1 0x80 ADD
+	0x60, 0x00, 0xf3, // PUSH1 0 RETURN
+}
+
+// Runtime returning its calldata: CALLDATASIZE PUSH1 0 PUSH1 0 CALLDATACOPY CALLDATASIZE PUSH1 0 RETURN
+var echoRuntime = []byte{0x36, 0x60, 0x00, 0x60, 0x00, 0x37, 0x36, 0x60, 0x00, 0xf3}
+
+// Runtime reverting with the word 0x2a: PUSH1 0x2a PUSH1 0 MSTORE PUSH1 32 PUSH1 0 REVERT
+var revertRuntime = []byte{0x60, 0x2a, 0x60, 0x00, 0x52, 0x60, 0x20, 0x60, 0x00, 0xfd}
+
+// Deploys a contract answering every call, VERSION() included, with the ABI encoding of the string version.
+func (c *testChain) deployVersionStub(t *testing.T, version string) common.Address {
+	t.Helper()
+	encoded := make([]byte, 96)
+	encoded[31] = 0x20
+	encoded[63] = byte(len(version))
+	copy(encoded[64:], version)
+	// PUSH1 96 PUSH1 12 PUSH1 0 CODECOPY PUSH1 96 PUSH1 0 RETURN, followed by the encoding.
+	runtime := []byte{0x60, 0x60, 0x60, 0x0c, 0x60, 0x00, 0x39, 0x60, 0x60, 0x60, 0x00, 0xf3}
+	return c.deployRuntime(t, append(runtime, encoded...))
+}
+
+// Simulates a succeeding and a reverting call on a Safe with a CompatibilityFallbackHandler, which goes through the
+// handler's simulate, and on one without, which decodes the revert of simulateAndRevert.
+func TestSimulateSafeTransaction(t *testing.T) {
+	chain := newTestChain(t)
+	_, owners := testKeys(t, 1)
+
+	handler, _, _, err := CompatibilityFallbackHandler.DeployCompatibilityFallbackHandler(chain.deployer, chain.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain.backend.Commit()
+	withHandler := chain.deploySafeWithFallbackHandler(t, owners, 1, handler)
+	withoutHandler := chain.deploySafe(t, owners, 1)
+
+	accessor := chain.deployRuntime(t, simulateTxAccessorRuntime)
+	echo := chain.deployRuntime(t, echoRuntime)
+	reverter := chain.deployRuntime(t, revertRuntime)
+
+	data := bytes.Repeat([]byte{0xab}, 40)
+	revertData := common.LeftPadBytes([]byte{0x2a}, 32)
+
+	accessorAbi, err := abi.JSON(strings.NewReader(simulateTxAccessorABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := accessorAbi.Pack("simulate", echo, big.NewInt(0), data, uint8(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := simulateThroughHandler(withHandler, accessor, payload, chain.client); err != nil {
+		t.Errorf("simulation through the fallback handler failed: %v", err)
+	}
+	if _, err := simulateThroughHandler(withoutHandler, accessor, payload, chain.client); err == nil {
+		t.Error("simulation through the fallback handler succeeded on a Safe without one")
+	}
+	if _, err := simulateAndRevert(withoutHandler, accessor, payload, chain.client); err != nil {
+		t.Errorf("simulateAndRevert failed: %v", err)
+	}
+
+	cases := []struct {
+		name       string
+		to         common.Address
+		success    bool
+		returnData []byte
+	}{
+		{"success", echo, true, data},
+		{"revert", reverter, false, revertData},
+	}
+	for _, safeAddress := range []common.Address{withHandler, withoutHandler} {
+		for _, c := range cases {
+			simulation, err := SimulateSafeTransaction(safeAddress, c.to, big.NewInt(0), data, 0, accessor, chain.client)
+			if err != nil {
+				t.Errorf("%s on %s: %v", c.name, safeAddress.Hex(), err)
+				continue
+			}
+			if simulation.Accessor != accessor {
+				t.Errorf("%s on %s: accessor %s, want %s", c.name, safeAddress.Hex(), simulation.Accessor.Hex(), accessor.Hex())
+			}
+			if simulation.Success != c.success {
+				t.Errorf("%s on %s: success %t, want %t", c.name, safeAddress.Hex(), simulation.Success, c.success)
+			}
+			if !bytes.Equal(simulation.ReturnData, c.returnData) {
+				t.Errorf("%s on %s: return data %x, want %x", c.name, safeAddress.Hex(), simulation.ReturnData, c.returnData)
+			}
+			if simulation.GasUsed == 0 || simulation.GasUsed > 100_000 {
+				t.Errorf("%s on %s: implausible gas estimate %d", c.name, safeAddress.Hex(), simulation.GasUsed)
+			}
+			if want := simulation.GasUsed + simulation.GasUsed*SafeTxGasEstimateMarginPercent/100; simulation.SafeTxGas() != want {
+				t.Errorf("%s on %s: SafeTxGas %d, want %d", c.name, safeAddress.Hex(), simulation.SafeTxGas(), want)
+			}
+		}
+	}
+}
+
+func TestSimulateSafeTransactionRequiresSafe130(t *testing.T) {
+	chain := newTestChain(t)
+	oldSafe := chain.deployVersionStub(t, "1.2.0")
+	accessor := chain.deployRuntime(t, simulateTxAccessorRuntime)
+
+	if _, err := ResolveSimulateTxAccessor(oldSafe, chain.client); err == nil || !strings.Contains(err.Error(), "1.3.0") {
+		t.Errorf("ResolveSimulateTxAccessor on a 1.2.0 Safe = %v, want an error naming 1.3.0", err)
+	}
+	if _, err := SimulateSafeTransaction(oldSafe, accessor, big.NewInt(0), nil, 0, accessor, chain.client); err == nil || !strings.Contains(err.Error(), "1.3.0") {
+		t.Errorf("SimulateSafeTransaction on a 1.2.0 Safe = %v, want an error naming 1.3.0", err)
+	}
+
+	if _, err := SafeSimulationVersion(chain.deployVersionStub(t, "1.3.0"), chain.client); err != nil {
+		t.Errorf("SafeSimulationVersion rejected a 1.3.0 Safe: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Token standard ABIs, limited to the methods used for transfers. ERC-721 and ERC-1155 overload balanceOf and
+// safeTransferFrom, so each standard has its own ABI.
+const erc20ABI = `[{"inputs":[],"name":"decimals","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"account","type":"address"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"name":"transfer","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"name":"spender","type":"address"},{"name":"amount","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"amount","type":"uint256"}],"name":"transferFrom","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}]`
+
+const erc721ABI = `[{"inputs":[],"name":"symbol","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"tokenId","type":"uint256"}],"name":"ownerOf","outputs":[{"name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"tokenId","type":"uint256"}],"name":"safeTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+const erc1155ABI = `[{"inputs":[{"name":"account","type":"address"},{"name":"id","type":"uint256"}],"name":"balanceOf","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"name":"from","type":"address"},{"name":"to","type":"address"},{"name":"id","type":"uint256"},{"name":"amount","type":"uint256"},{"name":"data","type":"bytes"}],"name":"safeTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+// Parses a decimal amount such as "12.5" into base units of a token with the given number of decimals.
+func ParseTokenAmount(amount string, decimals uint8) (*big.Int, error) {
+	whole, fraction, _ := strings.Cut(strings.TrimSpace(amount), ".")
+	if whole == "" && fraction == "" {
+		return nil, fmt.Errorf("invalid amount: %q", amount)
+	}
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("amount %s has more than %d decimal places", amount, decimals)
+	}
+	digits := whole + fraction + strings.Repeat("0", int(decimals)-len(fraction))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("invalid amount: %q", amount)
+		}
+	}
+
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount: %q", amount)
+	}
+	return value, nil
+}
+
+// Formats base units of a token with the given number of decimals, without trailing zeros.
+func FormatTokenAmount(amount *big.Int, decimals uint8) string {
+	negative := amount.Sign() < 0
+	digits := new(big.Int).Abs(amount).String()
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+
+	point := len(digits) - int(decimals)
+	formatted := digits[:point]
+	if fraction := strings.TrimRight(digits[point:], "0"); fraction != "" {
+		formatted += "." + fraction
+	}
+	if negative {
+		formatted = "-" + formatted
+	}
+	return formatted
+}
+
+// Calls a view method of a token contract and returns its unpacked outputs.
+func callToken(contractABI string, token common.Address, client *ethclient.Client, method string, args ...interface{}) ([]interface{}, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token ABI: %w", err)
+	}
+	input, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s call: %w", method, err)
+	}
+	output, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &token, Data: input}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s call to %s failed: %w", method, token.Hex(), err)
+	}
+	values, err := parsed.Unpack(method, output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s result from %s: %w", method, token.Hex(), err)
+	}
+	return values, nil
+}
+
+// Reads a token's symbol. Tokens which return bytes32 instead of a string (such as MKR) are supported; if the token
+// has no symbol, its address is returned.
+func TokenSymbol(token common.Address, client *ethclient.Client) string {
+	if values, err := callToken(erc20ABI, token, client, "symbol"); err == nil {
+		if symbol := values[0].(string); symbol != "" {
+			return symbol
+		}
+	}
+
+	output, err := client.CallContract(context.Background(), ethereum.CallMsg{To: &token, Data: common.FromHex("0x95d89b41")}, nil)
+	if err == nil && len(output) == 32 {
+		if symbol := strings.TrimRight(string(output), "\x00"); symbol != "" {
+			return symbol
+		}
+	}
+	return token.Hex()
+}
+
+func TokenDecimals(token common.Address, client *ethclient.Client) (uint8, error) {
+	values, err := callToken(erc20ABI, token, client, "decimals")
+	if err != nil {
+		return 0, err
+	}
+	return values[0].(uint8), nil
+}
+
+func ERC20BalanceOf(token common.Address, account common.Address, client *ethclient.Client) (*big.Int, error) {
+	values, err := callToken(erc20ABI, token, client, "balanceOf", account)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+func ERC721OwnerOf(token common.Address, tokenID *big.Int, client *ethclient.Client) (common.Address, error) {
+	values, err := callToken(erc721ABI, token, client, "ownerOf", tokenID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return values[0].(common.Address), nil
+}
+
+func ERC1155BalanceOf(token common.Address, account common.Address, id *big.Int, client *ethclient.Client) (*big.Int, error) {
+	values, err := callToken(erc1155ABI, token, client, "balanceOf", account, id)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
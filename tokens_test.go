@@ -0,0 +1,89 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParseTokenAmount(t *testing.T) {
+	valid := []struct {
+		amount   string
+		decimals uint8
+		want     string
+	}{
+		{"1", 18, "1000000000000000000"},
+		{"1.5", 18, "1500000000000000000"},
+		{"0.000001", 6, "1"},
+		{".25", 2, "25"},
+		{"2.", 3, "2000"},
+		{" 42 ", 0, "42"},
+		{"007.10", 2, "710"},
+		{"115792089237316195423570985008687907853269984665640564039457.584007913129639935", 18, "115792089237316195423570985008687907853269984665640564039457584007913129639935"},
+	}
+	for _, c := range valid {
+		got, err := ParseTokenAmount(c.amount, c.decimals)
+		if err != nil {
+			t.Errorf("ParseTokenAmount(%q, %d): %v", c.amount, c.decimals, err)
+			continue
+		}
+		if got.String() != c.want {
+			t.Errorf("ParseTokenAmount(%q, %d) = %s, want %s", c.amount, c.decimals, got.String(), c.want)
+		}
+	}
+
+	invalid := []struct {
+		amount   string
+		decimals uint8
+	}{
+		{"", 18},
+		{".", 18},
+		{"-1", 18},
+		{"+1", 18},
+		{"1e18", 18},
+		{"0x10", 18},
+		{"1.2.3", 18},
+		{"1,5", 18},
+		{"0.0000001", 6},
+		{"1.5", 0},
+	}
+	for _, c := range invalid {
+		if got, err := ParseTokenAmount(c.amount, c.decimals); err == nil {
+			t.Errorf("ParseTokenAmount(%q, %d) = %s, expected an error", c.amount, c.decimals, got.String())
+		}
+	}
+}
+
+func TestFormatTokenAmount(t *testing.T) {
+	cases := []struct {
+		amount   string
+		decimals uint8
+		want     string
+	}{
+		{"0", 18, "0"},
+		{"1", 18, "0.000000000000000001"},
+		{"1000000000000000000", 18, "1"},
+		{"1500000000000000000", 18, "1.5"},
+		{"123456", 6, "0.123456"},
+		{"710", 2, "7.1"},
+		{"42", 0, "42"},
+		{"-25", 2, "-0.25"},
+	}
+	for _, c := range cases {
+		amount, _ := new(big.Int).SetString(c.amount, 10)
+		if got := FormatTokenAmount(amount, c.decimals); got != c.want {
+			t.Errorf("FormatTokenAmount(%s, %d) = %s, want %s", c.amount, c.decimals, got, c.want)
+		}
+	}
+}
+
+func TestTokenAmountRoundTrip(t *testing.T) {
+	for _, amount := range []string{"0", "1", "0.5", "12.345678", "1000000", "0.000000000000000001"} {
+		parsed, err := ParseTokenAmount(amount, 18)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := FormatTokenAmount(parsed, 18); got != amount {
+			t.Errorf("%s formats back as %s", amount, got)
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/G7DAO/safes/signer"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+)
+
+// AssetTransfer is a call from a Safe moving a single asset, with a human-readable summary for review.
+type AssetTransfer struct {
+	To      common.Address
+	Value   *big.Int
+	Data    []byte
+	Summary string
+}
+
+// Builds a transfer of amount (in whole units, such as "1.5") of the native currency, checking the Safe's balance.
+// symbol is only used for display.
+func NewNativeTransfer(safeAddress common.Address, recipient common.Address, amount string, symbol string, client *ethclient.Client) (*AssetTransfer, error) {
+	value, err := ParseTokenAmount(amount, 18)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := client.BalanceAt(context.Background(), safeAddress, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch balance of %s: %w", safeAddress.Hex(), err)
+	}
+	if balance.Cmp(value) < 0 {
+		return nil, fmt.Errorf("Safe %s holds only %s %s", safeAddress.Hex(), FormatTokenAmount(balance, 18), symbol)
+	}
+
+	return &AssetTransfer{
+		To:      recipient,
+		Value:   value,
+		Summary: fmt.Sprintf("send %s %s to %s (balance after: %s %s)", FormatTokenAmount(value, 18), symbol, recipient.Hex(), FormatTokenAmount(new(big.Int).Sub(balance, value), 18), symbol),
+	}, nil
+}
+
+// Builds an ERC-20 transfer of amount (in whole tokens, such as "12.5"), reading the token's decimals and symbol and
+// checking the Safe's balance.
+func NewERC20Transfer(safeAddress common.Address, token common.Address, recipient common.Address, amount string, client *ethclient.Client) (*AssetTransfer, error) {
+	decimals, err := TokenDecimals(token, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read decimals, is %s an ERC-20 token? %w", token.Hex(), err)
+	}
+	symbol := TokenSymbol(token, client)
+
+	value, err := ParseTokenAmount(amount, decimals)
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := ERC20BalanceOf(token, safeAddress, client)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(value) < 0 {
+		return nil, fmt.Errorf("Safe %s holds only %s %s", safeAddress.Hex(), FormatTokenAmount(balance, decimals), symbol)
+	}
+
+	data, err := packTokenCall(erc20ABI, "transfer", recipient, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AssetTransfer{
+		To:      token,
+		Value:   big.NewInt(0),
+		Data:    data,
+		Summary: fmt.Sprintf("send %s %s to %s (balance after: %s %s)", FormatTokenAmount(value, decimals), symbol, recipient.Hex(), FormatTokenAmount(new(big.Int).Sub(balance, value), decimals), symbol),
+	}, nil
+}
+
+// Builds an ERC-721 safeTransferFrom of a single token, checking that the Safe owns it.
+func NewERC721Transfer(safeAddress common.Address, token common.Address, recipient common.Address, tokenID *big.Int, client *ethclient.Client) (*AssetTransfer, error) {
+	owner, err := ERC721OwnerOf(token, tokenID, client)
+	if err != nil {
+		return nil, err
+	}
+	if owner != safeAddress {
+		return nil, fmt.Errorf("token %s of %s is owned by %s, not by Safe %s", tokenID.String(), token.Hex(), owner.Hex(), safeAddress.Hex())
+	}
+
+	data, err := packTokenCall(erc721ABI, "safeTransferFrom", safeAddress, recipient, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AssetTransfer{
+		To:      token,
+		Value:   big.NewInt(0),
+		Data:    data,
+		Summary: fmt.Sprintf("send %s #%s to %s", TokenSymbol(token, client), tokenID.String(), recipient.Hex()),
+	}, nil
+}
+
+// Builds an ERC-1155 safeTransferFrom of amount units of token id, checking the Safe's balance.
+func NewERC1155Transfer(safeAddress common.Address, token common.Address, recipient common.Address, id *big.Int, amount *big.Int, data []byte, client *ethclient.Client) (*AssetTransfer, error) {
+	balance, err := ERC1155BalanceOf(token, safeAddress, id, client)
+	if err != nil {
+		return nil, err
+	}
+	if balance.Cmp(amount) < 0 {
+		return nil, fmt.Errorf("Safe %s holds only %s of token %s of %s", safeAddress.Hex(), balance.String(), id.String(), token.Hex())
+	}
+
+	calldata, err := packTokenCall(erc1155ABI, "safeTransferFrom", safeAddress, recipient, id, amount, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AssetTransfer{
+		To:      token,
+		Value:   big.NewInt(0),
+		Data:    calldata,
+		Summary: fmt.Sprintf("send %s of token #%s of %s to %s (balance after: %s)", amount.String(), id.String(), token.Hex(), recipient.Hex(), new(big.Int).Sub(balance, amount).String()),
+	}, nil
+}
+
+func packTokenCall(contractABI string, method string, args ...interface{}) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token ABI: %w", err)
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", method, err)
+	}
+	return data, nil
+}
+
+func createTransferSafeProposalCmd() *cobra.Command {
+	transferCmd := &cobra.Command{
+		Use:   "transfer",
+		Short: "Propose a transfer of native currency or tokens from a Safe",
+	}
+
+	transferCmd.AddCommand(createNativeTransferCmd())
+	transferCmd.AddCommand(createERC20TransferCmd())
+	transferCmd.AddCommand(createERC721TransferCmd())
+	transferCmd.AddCommand(createERC1155TransferCmd())
+
+	return transferCmd
+}
+
+func createNativeTransferCmd() *cobra.Command {
+	var amount, symbol string
+
+	nativeCmd := createAssetTransferCmd("native", "Propose a transfer of the chain's native currency", nil, func(safeAddress, token, recipient common.Address, client *ethclient.Client) (*AssetTransfer, error) {
+		return NewNativeTransfer(safeAddress, recipient, amount, symbol, client)
+	})
+	nativeCmd.Flags().StringVar(&amount, "amount", "", "Amount to send in whole units, e.g. 1.5")
+	nativeCmd.Flags().StringVar(&symbol, "symbol", "ETH", "Symbol of the native currency, for display")
+	nativeCmd.MarkFlagRequired("amount")
+
+	return nativeCmd
+}
+
+func createERC20TransferCmd() *cobra.Command {
+	var amount string
+
+	erc20Cmd := createAssetTransferCmd("erc20", "Propose an ERC-20 token transfer", nil, func(safeAddress, token, recipient common.Address, client *ethclient.Client) (*AssetTransfer, error) {
+		return NewERC20Transfer(safeAddress, token, recipient, amount, client)
+	})
+	erc20Cmd.Flags().StringVar(&amount, "amount", "", "Amount to send in whole tokens, e.g. 12.5 (the token's decimals are read from the contract)")
+	erc20Cmd.MarkFlagRequired("amount")
+
+	return erc20Cmd
+}
+
+func createERC721TransferCmd() *cobra.Command {
+	var tokenIDRaw string
+	var tokenID *big.Int
+
+	validate := func() error {
+		tokenID = new(big.Int)
+		if _, ok := tokenID.SetString(tokenIDRaw, 0); !ok || tokenID.Sign() < 0 {
+			return fmt.Errorf("invalid --token-id: %s", tokenIDRaw)
+		}
+		return nil
+	}
+	erc721Cmd := createAssetTransferCmd("erc721", "Propose an ERC-721 token transfer with safeTransferFrom", validate, func(safeAddress, token, recipient common.Address, client *ethclient.Client) (*AssetTransfer, error) {
+		return NewERC721Transfer(safeAddress, token, recipient, tokenID, client)
+	})
+	erc721Cmd.Flags().StringVar(&tokenIDRaw, "token-id", "", "ID of the token to send")
+	erc721Cmd.MarkFlagRequired("token-id")
+
+	return erc721Cmd
+}
+
+func createERC1155TransferCmd() *cobra.Command {
+	var tokenIDRaw, amountRaw, data string
+	var tokenID, amount *big.Int
+
+	validate := func() error {
+		tokenID = new(big.Int)
+		if _, ok := tokenID.SetString(tokenIDRaw, 0); !ok || tokenID.Sign() < 0 {
+			return fmt.Errorf("invalid --token-id: %s", tokenIDRaw)
+		}
+		amount = new(big.Int)
+		if _, ok := amount.SetString(amountRaw, 10); !ok || amount.Sign() <= 0 {
+			return fmt.Errorf("invalid --amount: %s", amountRaw)
+		}
+		if data != "" && !IsValidHex(data) {
+			return fmt.Errorf("invalid --data hex: %s", data)
+		}
+		return nil
+	}
+	erc1155Cmd := createAssetTransferCmd("erc1155", "Propose an ERC-1155 token transfer with safeTransferFrom", validate, func(safeAddress, token, recipient common.Address, client *ethclient.Client) (*AssetTransfer, error) {
+		return NewERC1155Transfer(safeAddress, token, recipient, tokenID, amount, common.FromHex(data), client)
+	})
+	erc1155Cmd.Flags().StringVar(&tokenIDRaw, "token-id", "", "ID of the token to send")
+	erc1155Cmd.Flags().StringVar(&amountRaw, "amount", "", "Number of units to send")
+	erc1155Cmd.Flags().StringVar(&data, "data", "", "Hex-encoded data passed to the recipient's onERC1155Received")
+	erc1155Cmd.MarkFlagRequired("token-id")
+	erc1155Cmd.MarkFlagRequired("amount")
+
+	return erc1155Cmd
+}
+
+// Builds a transfer subcommand with the flags shared by every asset type. validate checks the asset-specific flags
+// and may be nil; build is called once the chain is reachable. Every asset type except native takes --token.
+func createAssetTransferCmd(use string, short string, validate func() error, build func(safeAddress, token, recipient common.Address, client *ethclient.Client) (*AssetTransfer, error)) *cobra.Command {
+	var (
+		safe         string
+		token        string
+		to           string
+		keyfile      string
+		password     string
+		yes          bool
+		gasFlags     safeTxGasFlags
+		gasParams    SafeTxGasParams
+		safeNonceRaw string
+		safeNonce    *big.Int
+	)
+
+	transferCmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if !common.IsHexAddress(safe) {
+				return fmt.Errorf("invalid safe address: %s", safe)
+			}
+			if !common.IsHexAddress(to) {
+				return fmt.Errorf("invalid to address: %s", to)
+			}
+			if common.HexToAddress(to) == (common.Address{}) {
+				return fmt.Errorf("refusing to send to the zero address")
+			}
+			if use != "native" && !common.IsHexAddress(token) {
+				return fmt.Errorf("invalid token address: %s", token)
+			}
+			if keyfile == "" {
+				return fmt.Errorf("--keyfile not specified (this should be a keystore path or signer specification)")
+			}
+			var err error
+			gasParams, err = gasFlags.Parse()
+			if err != nil {
+				return err
+			}
+			if safeNonceRaw != "" {
+				var err error
+				safeNonce, err = parseSafeNonce("safe-nonce", safeNonceRaw)
+				if err != nil {
+					return err
+				}
+			}
+			if validate != nil {
+				return validate()
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := ethclient.Dial(rpcURL)
+			if err != nil {
+				return fmt.Errorf("failed to connect to the Ethereum client: %v", err)
+			}
+
+			chainID, err := client.ChainID(context.Background())
+			if err != nil {
+				return fmt.Errorf("failed to get chain ID: %v", err)
+			}
+
+			if err := gasParams.Validate(client); err != nil {
+				return err
+			}
+
+			safeAddress := common.HexToAddress(safe)
+			tokenAddress := common.HexToAddress(token)
+			recipient := common.HexToAddress(to)
+
+			transfer, err := build(safeAddress, tokenAddress, recipient, client)
+			if err != nil {
+				return err
+			}
+
+			if recipient == tokenAddress && use != "native" {
+				cmd.Println("Warning: the recipient is the token contract itself, tokens sent there are usually lost")
+			}
+			if use == "erc721" || use == "erc1155" {
+				code, err := client.CodeAt(context.Background(), recipient, nil)
+				if err != nil {
+					return fmt.Errorf("failed to fetch code at %s: %v", recipient.Hex(), err)
+				}
+				if len(code) > 0 {
+					cmd.Printf("Note: %s is a contract, safeTransferFrom reverts unless it accepts %s tokens\n", recipient.Hex(), strings.ToUpper(use))
+				}
+			}
+
+			cmd.Printf("Transfer from Safe %s: %s\n", safeAddress.Hex(), transfer.Summary)
+			if len(transfer.Data) > 0 {
+				DefaultSignatureRegistry().PrintCalldata(transfer.Data, "  ")
+			}
+			if !yes {
+				ok, err := ConfirmPrompt("Sign and propose this transfer?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return fmt.Errorf("transfer not confirmed")
+				}
+			}
+
+			key, err := signer.Open(keyfile, password)
+			if err != nil {
+				return err
+			}
+
+			return ProposeSafeCall(safeAddress, transfer.To, transfer.Value, transfer.Data, gasParams, safeNonce, chainID, key, client, SafeClientBaseURL(safeAPIURL))
+		},
+	}
+
+	transferCmd.Flags().StringVar(&safe, "safe", "", "Safe address")
+	transferCmd.Flags().StringVar(&to, "to", "", "Recipient address")
+	if use != "native" {
+		transferCmd.Flags().StringVar(&token, "token", "", "Token contract address")
+		transferCmd.MarkFlagRequired("token")
+	}
+	transferCmd.Flags().StringVarP(&keyfile, "keyfile", "k", "", "Signer: "+signer.SpecUsage)
+	transferCmd.Flags().StringVarP(&password, "password", "p", "", signer.PasswordUsage)
+	transferCmd.Flags().StringVar(&rpcURL, "rpc", "", "RPC URL of the Safe's chain")
+	transferCmd.Flags().StringVar(&safeAPIURL, "safe-api", "", "Override default Safe client gateway base URL")
+	transferCmd.Flags().BoolVarP(&yes, "yes", "y", false, "Propose without asking for confirmation")
+	addSafeTxGasFlags(transferCmd, &gasFlags)
+	transferCmd.Flags().StringVar(&safeNonceRaw, "safe-nonce", "", "Safe nonce for the proposal (defaults to the next nonce not taken by a queued proposal)")
+	transferCmd.MarkFlagRequired("safe")
+	transferCmd.MarkFlagRequired("to")
+	transferCmd.MarkFlagRequired("rpc")
+
+	return transferCmd
+}
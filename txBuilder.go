@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const TxBuilderFileVersion = "1.0"
+
+// TxBuilderBatch is the batch file format read and written by the Safe{Wallet} Transaction Builder app.
+type TxBuilderBatch struct {
+	Version      string                 `json:"version"`
+	ChainID      string                 `json:"chainId"`
+	CreatedAt    int64                  `json:"createdAt"`
+	Meta         TxBuilderMeta          `json:"meta"`
+	Transactions []TxBuilderTransaction `json:"transactions"`
+}
+
+type TxBuilderMeta struct {
+	Name                    string `json:"name"`
+	Description             string `json:"description"`
+	TxBuilderVersion        string `json:"txBuilderVersion,omitempty"`
+	CreatedFromSafeAddress  string `json:"createdFromSafeAddress"`
+	CreatedFromOwnerAddress string `json:"createdFromOwnerAddress"`
+	Checksum                string `json:"checksum,omitempty"`
+}
+
+// TxBuilderTransaction is one call of a batch. It holds either raw data, or a contract method together with the
+// string values of its inputs.
+type TxBuilderTransaction struct {
+	To                   string                   `json:"to"`
+	Value                string                   `json:"value"`
+	Data                 *string                  `json:"data"`
+	ContractMethod       *TxBuilderContractMethod `json:"contractMethod"`
+	ContractInputsValues map[string]string        `json:"contractInputsValues"`
+}
+
+type TxBuilderContractMethod struct {
+	Inputs  []TxBuilderMethodInput `json:"inputs"`
+	Name    string                 `json:"name"`
+	Payable bool                   `json:"payable"`
+}
+
+type TxBuilderMethodInput struct {
+	InternalType string                 `json:"internalType"`
+	Name         string                 `json:"name"`
+	Type         string                 `json:"type"`
+	Components   []TxBuilderMethodInput `json:"components,omitempty"`
+}
+
+func (input TxBuilderMethodInput) argumentMarshaling() abi.ArgumentMarshaling {
+	components := make([]abi.ArgumentMarshaling, len(input.Components))
+	for i, component := range input.Components {
+		components[i] = component.argumentMarshaling()
+	}
+	return abi.ArgumentMarshaling{
+		Name:         input.Name,
+		Type:         input.Type,
+		InternalType: input.InternalType,
+		Components:   components,
+	}
+}
+
+// Encodes the calldata of a Transaction Builder call, either from its raw data or from its contract method and
+// input values.
+func (tx TxBuilderTransaction) Calldata() ([]byte, error) {
+	if tx.ContractMethod == nil {
+		if tx.Data == nil {
+			return []byte{}, nil
+		}
+		return hex.DecodeString(strings.TrimPrefix(*tx.Data, "0x"))
+	}
+
+	var arguments abi.Arguments
+	var values []interface{}
+	for _, input := range tx.ContractMethod.Inputs {
+		marshaling := input.argumentMarshaling()
+		argumentType, err := abi.NewType(marshaling.Type, marshaling.InternalType, marshaling.Components)
+		if err == nil {
+			err = checkABIType(argumentType)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid type for input %s: %w", input.Name, err)
+		}
+
+		raw, ok := tx.ContractInputsValues[input.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing value for input %s of %s", input.Name, tx.ContractMethod.Name)
+		}
+
+		value, err := ParseABIArgument(argumentType, raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for input %s of %s: %w", input.Name, tx.ContractMethod.Name, err)
+		}
+
+		arguments = append(arguments, abi.Argument{Name: input.Name, Type: argumentType})
+		values = append(values, value)
+	}
+
+	method := abi.NewMethod(tx.ContractMethod.Name, tx.ContractMethod.Name, abi.Function, "", false, tx.ContractMethod.Payable, arguments, nil)
+	packed, err := arguments.Pack(values...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", method.Sig, err)
+	}
+
+	return append(method.ID, packed...), nil
+}
+
+// Reads a Transaction Builder batch file and converts its transactions into MultiSend calls.
+func ReadTxBuilderBatch(path string) (*TxBuilderBatch, []MultiSendCall, error) {
+	var batch TxBuilderBatch
+	if err := readJSONFile(path, &batch); err != nil {
+		return nil, nil, err
+	}
+
+	if len(batch.Transactions) == 0 {
+		return nil, nil, fmt.Errorf("%s contains no transactions", path)
+	}
+
+	calls := make([]MultiSendCall, 0, len(batch.Transactions))
+	for i, tx := range batch.Transactions {
+		if !common.IsHexAddress(tx.To) {
+			return nil, nil, fmt.Errorf("transaction %d in %s: invalid to address: %s", i, path, tx.To)
+		}
+
+		// Values are exported as decimal strings. ParseInteger also accepts 0x hex, but never reads a leading
+		// zero as octal.
+		value := new(big.Int)
+		if tx.Value != "" {
+			parsed, ok := ParseInteger(tx.Value)
+			if !ok || parsed.Sign() < 0 {
+				return nil, nil, fmt.Errorf("transaction %d in %s: invalid value: %s", i, path, tx.Value)
+			}
+			value = parsed
+		}
+
+		data, err := tx.Calldata()
+		if err != nil {
+			return nil, nil, fmt.Errorf("transaction %d in %s: %w", i, path, err)
+		}
+
+		calls = append(calls, MultiSendCall{To: common.HexToAddress(tx.To), Value: value, Data: data})
+	}
+
+	return &batch, calls, nil
+}
+
+// Builds a Transaction Builder batch from a list of calls. Calls are exported with raw data, which every version of
+// the Transaction Builder can import.
+func NewTxBuilderBatch(chainID *big.Int, safeAddress common.Address, name string, calls []MultiSendCall) (*TxBuilderBatch, error) {
+	batch := &TxBuilderBatch{
+		Version:   TxBuilderFileVersion,
+		ChainID:   chainID.String(),
+		CreatedAt: time.Now().UnixMilli(),
+		Meta: TxBuilderMeta{
+			Name:                   name,
+			CreatedFromSafeAddress: safeAddress.Hex(),
+		},
+	}
+
+	for i, call := range calls {
+		if call.Operation != 0 {
+			return nil, fmt.Errorf("call %d is a DelegateCall, which the Transaction Builder cannot represent", i)
+		}
+		data := "0x" + common.Bytes2Hex(call.Data)
+		batch.Transactions = append(batch.Transactions, TxBuilderTransaction{
+			To:    call.To.Hex(),
+			Value: call.Value.String(),
+			Data:  &data,
+		})
+	}
+
+	return batch, nil
+}
+
+// Returns the calls performed by a SafeTx: the inner calls of a MultiSend DelegateCall, or the SafeTx itself.
+func SafeTransactionCalls(txData Safe.SafeTransactionData) ([]MultiSendCall, error) {
+	data, err := hex.DecodeString(strings.TrimPrefix(txData.Data, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SafeTx data: %w", err)
+	}
+
+	if txData.Operation == Safe.DelegateCall {
+		if calls, err := DecodeMultiSend(data); err == nil {
+			return calls, nil
+		}
+	}
+
+	value := new(big.Int)
+	if txData.Value != "" {
+		if _, ok := value.SetString(txData.Value, 10); !ok {
+			return nil, fmt.Errorf("invalid SafeTx value: %s", txData.Value)
+		}
+	}
+
+	return []MultiSendCall{{
+		To:        common.HexToAddress(txData.To),
+		Value:     value,
+		Data:      data,
+		Operation: uint8(txData.Operation),
+	}}, nil
+}
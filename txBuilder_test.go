@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Returns the calls in testdata/txBuilderBatch.json, encoded independently of the Transaction Builder reader.
+func txBuilderFixtureCalls(t *testing.T) []MultiSendCall {
+	t.Helper()
+	dead := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	beef := common.HexToAddress("0x000000000000000000000000000000000000bEEF")
+
+	paymentType, err := abi.NewType("tuple[]", "", []abi.ArgumentMarshaling{{Name: "to", Type: "address"}, {Name: "amount", Type: "uint256"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	boolType, _ := abi.NewType("bool", "", nil)
+	type payment struct {
+		To     common.Address
+		Amount *big.Int
+	}
+	pay := abi.NewMethod("pay", "pay", abi.Function, "", false, false, abi.Arguments{{Type: paymentType}, {Type: boolType}}, nil)
+	payArgs, err := pay.Inputs.Pack([]payment{{dead, big.NewInt(5)}, {beef, big.NewInt(6)}}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ether, _ := new(big.Int).SetString("1000000000000000000", 10)
+	return []MultiSendCall{
+		{
+			To:    common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+			Value: big.NewInt(0),
+			Data:  common.FromHex("0xa9059cbb000000000000000000000000000000000000000000000000000000000000dead00000000000000000000000000000000000000000000000000000000000f4240"),
+		},
+		{
+			To:    common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"),
+			Value: big.NewInt(250000000000000000),
+			Data:  common.FromHex("0xd0e30db0"),
+		},
+		{To: beef, Value: ether, Data: []byte{}},
+		{
+			To:    beef,
+			Value: big.NewInt(0),
+			Data:  common.FromHex("0x095ea7b3000000000000000000000000000000000000000000000000000000000000dead00000000000000000000000000000000000000000000000000000000000003e8"),
+		},
+		{To: beef, Value: big.NewInt(0), Data: append(append([]byte{}, pay.ID...), payArgs...)},
+	}
+}
+
+func compareCalls(t *testing.T, got, want []MultiSendCall) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d calls, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].To != want[i].To || got[i].Value.Cmp(want[i].Value) != 0 || !bytes.Equal(got[i].Data, want[i].Data) || got[i].Operation != want[i].Operation {
+			t.Errorf("call %d is {%s %s 0x%x %d}, want {%s %s 0x%x %d}", i,
+				got[i].To.Hex(), got[i].Value.String(), got[i].Data, got[i].Operation,
+				want[i].To.Hex(), want[i].Value.String(), want[i].Data, want[i].Operation)
+		}
+	}
+}
+
+func TestReadTxBuilderBatch(t *testing.T) {
+	batch, calls, err := ReadTxBuilderBatch(filepath.Join("testdata", "txBuilderBatch.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.ChainID != "13746" || batch.Meta.CreatedFromSafeAddress != "0x5aFE3855358E112B5647B952709E6165e1c1eEEe" {
+		t.Errorf("read batch for chain %s and Safe %s", batch.ChainID, batch.Meta.CreatedFromSafeAddress)
+	}
+	compareCalls(t, calls, txBuilderFixtureCalls(t))
+}
+
+// Exports the fixture's calls as a new batch and reads it back, checking the JSON written for each transaction.
+func TestNewTxBuilderBatchRoundTrip(t *testing.T) {
+	want := txBuilderFixtureCalls(t)
+	safeAddress := common.HexToAddress("0x5aFE3855358E112B5647B952709E6165e1c1eEEe")
+	batch, err := NewTxBuilderBatch(big.NewInt(13746), safeAddress, "Safe transaction nonce 7", want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "batch.json")
+	if err := WriteJSONFile(path, batch); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var exported struct {
+		Version      string `json:"version"`
+		ChainID      string `json:"chainId"`
+		Meta         map[string]interface{}
+		Transactions []map[string]interface{} `json:"transactions"`
+	}
+	if err := json.Unmarshal(content, &exported); err != nil {
+		t.Fatal(err)
+	}
+	if exported.Version != TxBuilderFileVersion || exported.ChainID != "13746" || exported.Meta["createdFromSafeAddress"] != safeAddress.Hex() {
+		t.Errorf("exported header %s %s %v", exported.Version, exported.ChainID, exported.Meta)
+	}
+	for i, tx := range exported.Transactions {
+		wantTx := map[string]interface{}{
+			"to":                   want[i].To.Hex(),
+			"value":                want[i].Value.String(),
+			"data":                 "0x" + common.Bytes2Hex(want[i].Data),
+			"contractMethod":       nil,
+			"contractInputsValues": nil,
+		}
+		for key, value := range wantTx {
+			if tx[key] != value {
+				t.Errorf("transaction %d has %s %v, want %v", i, key, tx[key], value)
+			}
+		}
+	}
+
+	_, calls, err := ReadTxBuilderBatch(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compareCalls(t, calls, want)
+
+	if _, err := NewTxBuilderBatch(big.NewInt(1), safeAddress, "", []MultiSendCall{{To: safeAddress, Value: big.NewInt(0), Operation: 1}}); err == nil {
+		t.Error("exported a DelegateCall")
+	}
+}
+
+func TestReadTxBuilderBatchValues(t *testing.T) {
+	cases := []struct {
+		value string
+		want  string
+	}{
+		{"", "0"},
+		{"0", "0"},
+		{"010", "10"},
+		{"1000000000000000000", "1000000000000000000"},
+		{"0x10", "16"},
+		{"0b11", ""},
+		{"0o17", ""},
+		{"-1", ""},
+		{"1e18", ""},
+		{"1.5", ""},
+	}
+	for _, c := range cases {
+		batch := TxBuilderBatch{Version: TxBuilderFileVersion, Transactions: []TxBuilderTransaction{{To: "0x000000000000000000000000000000000000bEEF", Value: c.value}}}
+		path := filepath.Join(t.TempDir(), "batch.json")
+		if err := WriteJSONFile(path, batch); err != nil {
+			t.Fatal(err)
+		}
+		_, calls, err := ReadTxBuilderBatch(path)
+		if c.want == "" {
+			if err == nil {
+				t.Errorf("value %q read as %s, expected an error", c.value, calls[0].Value.String())
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("value %q: %v", c.value, err)
+		} else if calls[0].Value.String() != c.want {
+			t.Errorf("value %q read as %s, want %s", c.value, calls[0].Value.String(), c.want)
+		}
+	}
+}
+
+func TestTxBuilderTransactionCalldata(t *testing.T) {
+	method := &TxBuilderContractMethod{
+		Name:   "transfer",
+		Inputs: []TxBuilderMethodInput{{Name: "to", Type: "address"}, {Name: "amount", Type: "uint256"}},
+	}
+	invalid := map[string]TxBuilderTransaction{
+		"missing input":  {ContractMethod: method, ContractInputsValues: map[string]string{"to": "0x000000000000000000000000000000000000dEaD"}},
+		"invalid amount": {ContractMethod: method, ContractInputsValues: map[string]string{"to": "0x000000000000000000000000000000000000dEaD", "amount": "-1"}},
+		"invalid type":   {ContractMethod: &TxBuilderContractMethod{Name: "f", Inputs: []TxBuilderMethodInput{{Name: "x", Type: "uint7"}}}, ContractInputsValues: map[string]string{"x": "1"}},
+		"invalid data":   {Data: stringPointer("0xabc")},
+	}
+	for name, tx := range invalid {
+		if data, err := tx.Calldata(); err == nil {
+			t.Errorf("%s: encoded as 0x%x, expected an error", name, data)
+		}
+	}
+
+	if data, err := (TxBuilderTransaction{}).Calldata(); err != nil || len(data) != 0 {
+		t.Errorf("transaction without data or method encoded as 0x%x, %v", data, err)
+	}
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/G7DAO/safes/safetx"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Kinds of owner signature understood by Safe.checkNSignatures, identified by the v byte.
+const (
+	SafeSignatureContract     = "contract (EIP-1271)"
+	SafeSignatureApprovedHash = "approved hash"
+	SafeSignatureEthSign      = "eth_sign"
+	SafeSignatureECDSA        = "ECDSA"
+)
+
+// Determines the kind of a Safe owner signature and the owner it claims to be from. ECDSA and eth_sign signatures
+// are recovered; for contract and approved-hash signatures the owner is encoded in r and is only validated by the
+// Safe itself.
+func ParseSafeSignature(safeTxHash common.Hash, signature []byte) (common.Address, string, error) {
+	if len(signature) < 65 {
+		return common.Address{}, "", fmt.Errorf("signature has length %d, expected at least 65", len(signature))
+	}
+
+	v := signature[64]
+	switch {
+	case v == 0:
+		return common.BytesToAddress(signature[:32]), SafeSignatureContract, nil
+	case v == 1:
+		return common.BytesToAddress(signature[:32]), SafeSignatureApprovedHash, nil
+	case v > 30:
+		sig := make([]byte, 65)
+		copy(sig, signature[:65])
+		sig[64] -= 31
+		publicKey, err := crypto.SigToPub(accounts.TextHash(safeTxHash.Bytes()), sig)
+		if err != nil {
+			return common.Address{}, SafeSignatureEthSign, fmt.Errorf("failed to recover signer: %w", err)
+		}
+		return crypto.PubkeyToAddress(*publicKey), SafeSignatureEthSign, nil
+	case v == 27 || v == 28:
+		sig := make([]byte, 65)
+		copy(sig, signature[:65])
+		sig[64] -= 27
+		publicKey, err := crypto.SigToPub(safeTxHash.Bytes(), sig)
+		if err != nil {
+			return common.Address{}, SafeSignatureECDSA, fmt.Errorf("failed to recover signer: %w", err)
+		}
+		return crypto.PubkeyToAddress(*publicKey), SafeSignatureECDSA, nil
+	}
+
+	return common.Address{}, "", fmt.Errorf("unsupported signature type (v = %d)", v)
+}
+
+// Returns the EIP-712 encoded SafeTx, 0x1901 || domainSeparator || structHash, whose keccak256 is the SafeTxHash.
+// Contract signatures are checked against it.
+func SafeTxHashPreimage(safeAddress common.Address, txData Safe.SafeTransactionData, chainID *big.Int, safeVersion string) ([]byte, error) {
+	typedData, err := safetx.TypedData(safeAddress, SafeTxFromData(txData), chainID, safeVersion)
+	if err != nil {
+		return nil, err
+	}
+	_, rawData, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return []byte(rawData), nil
+}
+
+// Safe 1.5.0 replaced checkNSignatures(bytes32,bytes,bytes,uint256) with a form that takes the executor instead of
+// the SafeTx preimage.
+const checkNSignaturesV150ABI = `[{"inputs":[{"internalType":"address","name":"executor","type":"address"},{"internalType":"bytes32","name":"dataHash","type":"bytes32"},{"internalType":"bytes","name":"signatures","type":"bytes"},{"internalType":"uint256","name":"requiredSignatures","type":"uint256"}],"name":"checkNSignatures","outputs":[],"stateMutability":"view","type":"function"}]`
+
+// Validates one owner signature with the Safe's own checkNSignatures, using the form of the call that matches the
+// Safe's version. Safes before 1.3.0 have no checkNSignatures; for them checked is false and nothing is validated
+// on-chain. When checked is true, a non-nil error is the Safe rejecting the signature.
+func CheckSafeSignatureOnChain(caller bind.ContractCaller, safeAddress common.Address, version string, safeTxHash common.Hash, preimage []byte, signature []byte) (bool, error) {
+	available, err := safetx.VersionAtLeast(version, 1, 3)
+	if err != nil {
+		return false, err
+	}
+	if !available {
+		return false, nil
+	}
+	takesExecutor, err := safetx.VersionAtLeast(version, 1, 5)
+	if err != nil {
+		return false, err
+	}
+
+	if !takesExecutor {
+		safeCaller, err := Safe.NewSafeCaller(safeAddress, caller)
+		if err != nil {
+			return false, fmt.Errorf("failed to create Safe caller: %w", err)
+		}
+		return true, safeCaller.CheckNSignatures(&bind.CallOpts{}, safeTxHash, preimage, signature, big.NewInt(1))
+	}
+
+	parsed, err := abi.JSON(strings.NewReader(checkNSignaturesV150ABI))
+	if err != nil {
+		return false, fmt.Errorf("failed to parse checkNSignatures ABI: %w", err)
+	}
+	// With no executor, approved hashes are only accepted if they were approved on-chain.
+	input, err := parsed.Pack("checkNSignatures", common.Address{}, safeTxHash, signature, big.NewInt(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to encode checkNSignatures call: %w", err)
+	}
+	_, err = caller.CallContract(context.Background(), ethereum.CallMsg{To: &safeAddress, Data: input}, nil)
+	return true, err
+}
+
+// Independently verifies a proposal returned by the Safe client gateway. The SafeTxHash is recomputed locally and
+// via the Safe's getTransactionHash, and every confirmation is recovered, checked against the current owners and
+// validated on-chain with checkNSignatures. Safes before 1.3.0 have no checkNSignatures, so only ECDSA and eth_sign
+// confirmations are validated, by local recovery, and the report says so. A report is printed; any mismatch is
+// returned as an error.
+func VerifySafeProposal(safeTxHash common.Hash, chainID *big.Int, client *ethclient.Client, safeClientURL string) error {
+	details, err := GetTransactionDetails(safeClientURL, chainID, safeTxHash.Hex())
+	if err != nil {
+		return err
+	}
+
+	txData, err := details.SafeTransactionData()
+	if err != nil {
+		return fmt.Errorf("failed to parse transaction: %w", err)
+	}
+	if !common.IsHexAddress(details.SafeAddress) {
+		return fmt.Errorf("service returned an invalid safe address: %s", details.SafeAddress)
+	}
+	safeAddress := common.HexToAddress(details.SafeAddress)
+
+	safeInstance, err := Safe.NewSafe(safeAddress, client)
+	if err != nil {
+		return fmt.Errorf("failed to create Safe instance: %w", err)
+	}
+	version, err := safeInstance.VERSION(&bind.CallOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch Safe version: %w", err)
+	}
+
+	fmt.Printf("Safe %s (version %s) on chain %s\n", safeAddress.Hex(), version, chainID.String())
+	PrintSafeTransactionData(txData)
+
+	var failures []string
+	fail := func(format string, args ...interface{}) {
+		message := fmt.Sprintf(format, args...)
+		failures = append(failures, message)
+		fmt.Println("  FAIL:", message)
+	}
+	pass := func(format string, args ...interface{}) {
+		fmt.Println("  OK:  ", fmt.Sprintf(format, args...))
+	}
+	note := func(format string, args ...interface{}) {
+		fmt.Println("  NOTE:", fmt.Sprintf(format, args...))
+	}
+
+	fmt.Println("SafeTxHash:")
+	recomputed, err := safetx.Hash(safeAddress, SafeTxFromData(txData), chainID, version)
+	if err != nil {
+		return fmt.Errorf("failed to calculate SafeTxHash: %w", err)
+	}
+	if reported := common.HexToHash(txData.SafeTxHash); reported != safeTxHash {
+		fail("service returned SafeTxHash %s for requested %s", reported.Hex(), safeTxHash.Hex())
+	}
+	if recomputed == safeTxHash {
+		pass("recomputed locally: %s", recomputed.Hex())
+	} else {
+		fail("recomputed locally as %s", recomputed.Hex())
+	}
+
+	args, err := NewSafeTransactionArgs(txData)
+	if err != nil {
+		return err
+	}
+	onChainHash, err := safeInstance.GetTransactionHash(&bind.CallOpts{}, args.To, args.Value, args.Data, args.Operation, args.SafeTxGas, args.BaseGas, args.GasPrice, args.GasToken, args.RefundReceiver, args.Nonce)
+	if err != nil {
+		fail("getTransactionHash call failed: %v", err)
+	} else if common.Hash(onChainHash) == safeTxHash {
+		pass("getTransactionHash: %s", common.Hash(onChainHash).Hex())
+	} else {
+		fail("getTransactionHash returned %s", common.Hash(onChainHash).Hex())
+	}
+
+	owners, err := safeInstance.GetOwners(&bind.CallOpts{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch owners: %w", err)
+	}
+	isOwner := make(map[common.Address]bool)
+	for _, owner := range owners {
+		isOwner[owner] = true
+	}
+
+	preimage, err := SafeTxHashPreimage(safeAddress, txData, chainID, version)
+	if err != nil {
+		return err
+	}
+
+	confirmations := details.DetailedExecutionInfo.Confirmations
+	fmt.Printf("Confirmations (%d, threshold %d):\n", len(confirmations), details.DetailedExecutionInfo.ConfirmationsRequired)
+	seen := make(map[common.Address]bool)
+	for _, confirmation := range confirmations {
+		claimed := common.HexToAddress(confirmation.Signer.Value)
+		if confirmation.Signature == nil {
+			fail("%s: service returned no signature", claimed.Hex())
+			continue
+		}
+		signature, err := hex.DecodeString(strings.TrimPrefix(*confirmation.Signature, "0x"))
+		if err != nil {
+			fail("%s: invalid signature hex: %v", claimed.Hex(), err)
+			continue
+		}
+
+		signer, kind, err := ParseSafeSignature(safeTxHash, signature)
+		if err != nil {
+			fail("%s: %v", claimed.Hex(), err)
+			continue
+		}
+		if signer != claimed {
+			fail("%s: %s signature is from %s", claimed.Hex(), kind, signer.Hex())
+			continue
+		}
+		if seen[signer] {
+			fail("%s: duplicate confirmation", signer.Hex())
+			continue
+		}
+		seen[signer] = true
+		if !isOwner[signer] {
+			fail("%s: %s signature, but the signer is not currently an owner", signer.Hex(), kind)
+			continue
+		}
+
+		checked, err := CheckSafeSignatureOnChain(client, safeAddress, version, safeTxHash, preimage, signature)
+		if !checked {
+			if err != nil {
+				return err
+			}
+			if kind == SafeSignatureECDSA || kind == SafeSignatureEthSign {
+				pass("%s: valid %s signature from an owner, recovered locally", signer.Hex(), kind)
+			}
+			note("%s: %s signature not checked on-chain, checkNSignatures needs Safe 1.3.0 or later", signer.Hex(), kind)
+			continue
+		}
+		if err != nil {
+			fail("%s: %s signature rejected by checkNSignatures: %v", signer.Hex(), kind, err)
+			continue
+		}
+		pass("%s: valid %s signature from an owner", signer.Hex(), kind)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("verification of %s failed with %d problem(s)", safeTxHash.Hex(), len(failures))
+	}
+	fmt.Printf("Verified %s\n", safeTxHash.Hex())
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/G7DAO/safes/bindings/Safe"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestParseSafeSignature(t *testing.T) {
+	keys, addresses := testKeys(t, 1)
+	safeTxHash := crypto.Keccak256Hash([]byte("SafeTx"))
+
+	ethSign, err := crypto.Sign(accounts.TextHash(safeTxHash.Bytes()), keys[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethSign[64] += 31
+
+	ownerWord := common.LeftPadBytes(addresses[0].Bytes(), 32)
+	contract := append(append(append([]byte{}, ownerWord...), make([]byte, 32)...), 0)
+	approved := append(append(append([]byte{}, ownerWord...), make([]byte, 32)...), 1)
+
+	cases := []struct {
+		name      string
+		signature []byte
+		kind      string
+	}{
+		{"ECDSA", signSafeTxHash(t, keys[0], safeTxHash), SafeSignatureECDSA},
+		{"eth_sign", ethSign, SafeSignatureEthSign},
+		{"contract", contract, SafeSignatureContract},
+		{"approved hash", approved, SafeSignatureApprovedHash},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			signer, kind, err := ParseSafeSignature(safeTxHash, c.signature)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if signer != addresses[0] || kind != c.kind {
+				t.Errorf("ParseSafeSignature = %s, %s, want %s, %s", signer.Hex(), kind, addresses[0].Hex(), c.kind)
+			}
+		})
+	}
+
+	// A signature over another hash recovers to another address.
+	other, _, err := ParseSafeSignature(crypto.Keccak256Hash([]byte("other")), signSafeTxHash(t, keys[0], safeTxHash))
+	if err == nil && other == addresses[0] {
+		t.Error("signature over another hash recovered to the signer")
+	}
+
+	for _, invalid := range [][]byte{make([]byte, 64), append(make([]byte, 64), 29)} {
+		if _, _, err := ParseSafeSignature(safeTxHash, invalid); err == nil {
+			t.Errorf("ParseSafeSignature accepted %x", invalid)
+		}
+	}
+}
+
+func TestCheckSafeSignatureOnChain(t *testing.T) {
+	chain := newTestChain(t)
+	keys, owners := testKeys(t, 2)
+	safeAddress := chain.deploySafe(t, owners[:1], 1)
+
+	txData := Safe.SafeTransactionData{
+		To:             owners[1].Hex(),
+		Value:          "1",
+		Data:           "",
+		GasPrice:       "0",
+		GasToken:       Safe.NativeTokenAddress,
+		RefundReceiver: Safe.NativeTokenAddress,
+		Nonce:          big.NewInt(0),
+	}
+	preimage, err := SafeTxHashPreimage(safeAddress, txData, simulatedChainID, "1.4.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	safeTxHash := crypto.Keccak256Hash(preimage)
+
+	checked, err := CheckSafeSignatureOnChain(chain.client, safeAddress, "1.4.1", safeTxHash, preimage, signSafeTxHash(t, keys[0], safeTxHash))
+	if !checked || err != nil {
+		t.Errorf("owner signature: checked = %v, err = %v", checked, err)
+	}
+
+	checked, err = CheckSafeSignatureOnChain(chain.client, safeAddress, "1.4.1", safeTxHash, preimage, signSafeTxHash(t, keys[1], safeTxHash))
+	if !checked || err == nil {
+		t.Errorf("non-owner signature: checked = %v, err = %v", checked, err)
+	}
+
+	// Before 1.3.0 there is nothing to call, even on a Safe that would reject the signature.
+	for _, version := range []string{"1.1.1", "1.2.0"} {
+		checked, err = CheckSafeSignatureOnChain(chain.client, safeAddress, version, safeTxHash, preimage, signSafeTxHash(t, keys[1], safeTxHash))
+		if checked || err != nil {
+			t.Errorf("version %s: checked = %v, err = %v", version, checked, err)
+		}
+	}
+
+	if _, err := CheckSafeSignatureOnChain(chain.client, safeAddress, "garbage", safeTxHash, preimage, nil); err == nil {
+		t.Error("unknown version accepted")
+	}
+}